@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ReidMason/habit-tracker/internal/services/backupService"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+)
+
+func backupCommand() *Command {
+	return &Command{
+		Name:    "backup",
+		Summary: "Back up the database while it is in use",
+		Run:     runBackup,
+	}
+}
+
+func restoreCommand() *Command {
+	return &Command{
+		Name:    "restore",
+		Summary: "Replace the database with a backup, the server must be stopped",
+		Run:     runRestore,
+	}
+}
+
+func runBackup(app *App, args []string) error {
+	flags := newFlags("backup", "[file]", "Back up the database while it is in use, to the backups directory and removing old backups unless a file is given")
+	if err := parseFlags(flags, args, -1); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if !db.SupportsBackups() {
+		return storage.ErrBackupUnsupported
+	}
+
+	if flags.NArg() == 1 {
+		err = db.Backup(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(app.out, "Backed up database to %s\n", flags.Arg(0))
+		return nil
+	}
+
+	backups := app.cfg.Backups
+	backupStore := backupService.NewBackupService(db, app.logger, backups.Dir, backups.KeepDaily, backups.KeepWeekly)
+	backup, err := backupStore.CreateBackup()
+	if err != nil {
+		return err
+	}
+
+	removed, err := backupStore.PruneBackups()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.out, "Backed up database to %s\n", filepath.Join(backups.Dir, backup.Name))
+	for _, name := range removed {
+		fmt.Fprintf(app.out, "Removed old backup %s\n", name)
+	}
+	return nil
+}
+
+func runRestore(app *App, args []string) error {
+	flags := newFlags("restore", "--confirm <file>", "Replace the database with a backup, the server must be stopped")
+	confirm := flags.Bool("confirm", false, "confirm the current database should be replaced")
+	if err := parseFlags(flags, args, 1); err != nil {
+		return err
+	}
+
+	if !*confirm {
+		return errors.New("restoring replaces all data in the database, pass --confirm to continue")
+	}
+	if app.cfg.DBDriver != storage.DriverSqlite {
+		return storage.ErrBackupUnsupported
+	}
+
+	err := storage.Restore(flags.Arg(0), app.cfg.DBPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.out, "Restored database from %s\n", flags.Arg(0))
+	return nil
+}
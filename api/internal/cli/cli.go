@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ReidMason/habit-tracker/internal/config"
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+)
+
+// ErrUsage is returned when a command is given invalid arguments, after its
+// usage has been printed.
+var ErrUsage = errors.New("invalid usage")
+
+// errHelp is returned when a command's usage was asked for rather than run.
+var errHelp = errors.New("help requested")
+
+// Command is a command of the habit-tracker binary, either run directly or a
+// group of subcommands.
+type Command struct {
+	Run         func(app *App, args []string) error
+	Name        string
+	Usage       string
+	Summary     string
+	Subcommands []*Command
+}
+
+// App is what commands share: the loaded config, a logger and where to write output.
+type App struct {
+	cfg        *config.Config
+	logger     *slog.Logger
+	out        io.Writer
+	configPath string
+}
+
+func root() *Command {
+	return &Command{
+		Name:    "habit-tracker",
+		Summary: "Track habits. Runs serve when no command is given.",
+		Subcommands: []*Command{
+			serveCommand(),
+			migrateCommand(),
+			userCommand(),
+			backupCommand(),
+			restoreCommand(),
+			exportCommand(),
+			importCommand(),
+			dbCommand(),
+			configCommand(),
+		},
+	}
+}
+
+// Execute runs the command named by args, e.g. ["migrate", "up"]. Config
+// flags such as -db-path and -config come before the command.
+func Execute(args []string) error {
+	flags := flag.NewFlagSet("habit-tracker", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv(config.EnvName("config")), "path of a YAML or TOML config file")
+	overrides := config.RegisterFlags(flags)
+	flags.Usage = func() {
+		printUsage(flags.Output(), root(), []string{"habit-tracker"})
+		fmt.Fprintln(flags.Output(), "\nConfig flags, each can also be set with a HABIT_ environment variable:")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return ErrUsage
+	}
+	args = flags.Args()
+
+	cfg, err := config.Load(*configPath, overrides)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	logger, err := logger.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	app := &App{cfg: cfg, configPath: *configPath, logger: logger, out: os.Stdout}
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	command := root()
+	path := []string{command.Name}
+	for len(command.Subcommands) > 0 {
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			printUsage(os.Stderr, command, path)
+			if len(args) == 0 {
+				return ErrUsage
+			}
+			return nil
+		}
+
+		subcommand := findCommand(command.Subcommands, args[0])
+		if subcommand == nil {
+			printUsage(os.Stderr, command, path)
+			return fmt.Errorf("%w: unknown command %q", ErrUsage, strings.Join(append(path[1:], args[0]), " "))
+		}
+
+		command = subcommand
+		path = append(path, command.Name)
+		args = args[1:]
+	}
+
+	err = command.Run(app, args)
+	if errors.Is(err, errHelp) {
+		return nil
+	}
+
+	return err
+}
+
+func findCommand(commands []*Command, name string) *Command {
+	for _, command := range commands {
+		if command.Name == name {
+			return command
+		}
+	}
+
+	return nil
+}
+
+func printUsage(w io.Writer, command *Command, path []string) {
+	fmt.Fprintf(w, "%s\n\nUsage:\n  %s <command>\n\nCommands:\n", command.Summary, strings.Join(path, " "))
+	for _, subcommand := range command.Subcommands {
+		fmt.Fprintf(w, "  %-10s %s\n", subcommand.Name, subcommand.Summary)
+	}
+}
+
+// newFlags returns a flag set for a command that prints its usage on error.
+func newFlags(name string, usage string, summary string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "%s\n\nUsage:\n  habit-tracker %s %s\n", summary, name, usage)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parseFlags parses a command's flags, returning ErrUsage unless it was given
+// exactly nArgs positional arguments. A negative nArgs allows any number.
+func parseFlags(flags *flag.FlagSet, args []string, nArgs int) error {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return errHelp
+	}
+	if err != nil {
+		return ErrUsage
+	}
+
+	if nArgs >= 0 && flags.NArg() != nArgs {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	return nil
+}
+
+// openStorage opens the database, applying any migrations that have not been.
+func (a *App) openStorage() (*storage.Database, error) {
+	db, err := storage.Open(a.cfg.DBDriver, a.cfg.DataSource(), a.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.ApplyMigrations()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func configCommand() *Command {
+	return &Command{
+		Name:    "config",
+		Summary: "Show the configuration",
+		Subcommands: []*Command{
+			{Name: "print", Summary: "Print the effective configuration after files, environment and flags are applied", Run: runConfigPrint},
+		},
+	}
+}
+
+func runConfigPrint(app *App, args []string) error {
+	flags := newFlags("config print", "[-format yaml|toml]", "Print the effective configuration after files, environment and flags are applied, with passwords hidden")
+	format := flags.String("format", "yaml", "yaml or toml")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	if app.configPath != "" {
+		fmt.Fprintf(app.out, "# Loaded from %s\n", app.configPath)
+	}
+
+	cfg := app.cfg.Redacted()
+	switch *format {
+	case "yaml":
+		encoder := yaml.NewEncoder(app.out)
+		encoder.SetIndent(2)
+		return encoder.Encode(cfg)
+	case "toml":
+		return toml.NewEncoder(app.out).Encode(cfg)
+	}
+
+	return fmt.Errorf("%w: unknown format %q", ErrUsage, *format)
+}
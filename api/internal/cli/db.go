@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+func dbCommand() *Command {
+	return &Command{
+		Name:    "db",
+		Summary: "Manage the database",
+		Subcommands: []*Command{
+			{Name: "reset", Summary: "Delete all data and recreate the database", Run: runDbReset},
+		},
+	}
+}
+
+func runDbReset(app *App, args []string) error {
+	flags := newFlags("db reset", "--confirm", "Delete all data and recreate the database")
+	confirm := flags.Bool("confirm", false, "confirm all data should be deleted")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	if !*confirm {
+		return errors.New("resetting deletes all data in the database, pass --confirm to continue")
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Reset()
+	if err != nil {
+		return err
+	}
+
+	err = db.ApplyMigrations()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(app.out, "Reset database")
+	return nil
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"github.com/ReidMason/habit-tracker/internal/storage"
+)
+
+func migrateCommand() *Command {
+	return &Command{
+		Name:    "migrate",
+		Summary: "Manage database migrations",
+		Subcommands: []*Command{
+			{Name: "up", Summary: "Apply all pending migrations", Run: migration("up", (*storage.Database).ApplyMigrations)},
+			{Name: "down", Summary: "Roll back the most recent migration", Run: migration("down", (*storage.Database).RollbackMigration)},
+			{Name: "status", Summary: "Show which migrations have been applied", Run: migration("status", (*storage.Database).MigrationStatus)},
+			{Name: "redo", Summary: "Roll back and reapply the most recent migration", Run: migration("redo", (*storage.Database).RedoMigration)},
+		},
+	}
+}
+
+// migration returns a command that runs a migration without applying any
+// pending migrations first.
+func migration(name string, migrate func(*storage.Database) error) func(app *App, args []string) error {
+	return func(app *App, args []string) error {
+		flags := newFlags("migrate "+name, "", "Run the "+name+" migration command")
+		if err := parseFlags(flags, args, 0); err != nil {
+			return err
+		}
+
+		db, err := storage.Open(app.cfg.DBDriver, app.cfg.DataSource(), app.logger)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return migrate(db)
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/ReidMason/habit-tracker/internal/server"
+)
+
+func serveCommand() *Command {
+	return &Command{
+		Name:    "serve",
+		Summary: "Start the web server",
+		Run:     runServe,
+	}
+}
+
+func runServe(app *App, args []string) error {
+	flags := newFlags("serve", "[flags]", "Start the web server")
+	flags.StringVar(&app.cfg.ListenAddr, "listen-addr", app.cfg.ListenAddr, "server listen address")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if err := app.cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	srv, err := server.New(app.cfg, app.logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return srv.Start(ctx)
+}
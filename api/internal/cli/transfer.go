@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/exportService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitEntriesService"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+)
+
+func exportCommand() *Command {
+	return &Command{
+		Name:    "export",
+		Summary: "Export a user's habits and entries as JSON or CSV",
+		Run:     runExport,
+	}
+}
+
+func importCommand() *Command {
+	return &Command{
+		Name:    "import",
+		Summary: "Import habits and entries for a user from JSON, CSV or a Loop Habit Tracker backup",
+		Run:     runImport,
+	}
+}
+
+func newExportService(app *App, db *storage.Database) *exportService.ExportService {
+	// Nothing is subscribed to the events of a CLI import, running servers do
+	// not hear about it.
+	habitEntryStore := habitEntriesService.NewHabitEntriesService(db.Queries, app.logger)
+	return exportService.NewExportService(db.Queries, db, app.logger, habitEntryStore, eventsService.NewHub(app.logger))
+}
+
+func runExport(app *App, args []string) error {
+	flags := newFlags("export", "-user <id> [flags]", "Export a user's habits and entries as JSON or CSV")
+	userId := flags.Int64("user", 0, "ID of the user to export")
+	format := flags.String("format", "json", "json or csv")
+	layout := flags.String("layout", string(exportService.CSVEntries), "CSV layout, entries or calendar")
+	output := flags.String("o", "", "file to write to instead of stdout")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if *userId == 0 {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	w := app.out
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	exportStore := newExportService(app, db)
+	switch *format {
+	case "json":
+		export, err := exportStore.Export(*userId)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, export)
+	case "csv":
+		records, err := exportStore.ExportCSV(*userId, exportService.CSVLayout(*layout))
+		if err != nil {
+			return err
+		}
+		return csv.NewWriter(w).WriteAll(records)
+	}
+
+	return fmt.Errorf("%w: unknown format %q", ErrUsage, *format)
+}
+
+func runImport(app *App, args []string) error {
+	flags := newFlags("import", "-user <id> [flags] <file>", "Import habits and entries for a user, the format is detected from the file extension unless given")
+	userId := flags.Int64("user", 0, "ID of the user to import the habits for")
+	format := flags.String("format", "", "json, csv or loop")
+	mode := flags.String("mode", string(exportService.ImportMerge), "merge into or replace the user's habits")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without changing anything")
+	if err := parseFlags(flags, args, 1); err != nil {
+		return err
+	}
+	if *userId == 0 {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = importFormat(path)
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	exportStore := newExportService(app, db)
+	importMode := exportService.ImportMode(*mode)
+	switch *format {
+	case "loop":
+		result, err := exportStore.ImportLoop(*userId, path, importMode, *dryRun)
+		if err != nil {
+			return err
+		}
+		return writeJSON(app.out, result)
+	case "json", "csv":
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		result, err := importFile(exportStore, file, *format, *userId, importMode, *dryRun)
+		if err != nil {
+			return err
+		}
+		return writeJSON(app.out, result)
+	}
+
+	return fmt.Errorf("%w: unknown format %q", ErrUsage, *format)
+}
+
+func importFile(exportStore *exportService.ExportService, r io.Reader, format string, userId int64, mode exportService.ImportMode, dryRun bool) (exportService.ImportResult, error) {
+	if format == "csv" {
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return exportService.ImportResult{}, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		return exportStore.ImportCSV(userId, records, mode, dryRun)
+	}
+
+	var export exportService.Export
+	err := json.NewDecoder(r).Decode(&export)
+	if err != nil {
+		return exportService.ImportResult{}, fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	return exportStore.Import(userId, export, mode, dryRun)
+}
+
+// importFormat guesses the format of a file to import from its extension.
+// Loop Habit Tracker backups end in .db.
+func importFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".db", ".sqlite", ".sqlite3":
+		return "loop"
+	}
+
+	return "json"
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+)
+
+func userCommand() *Command {
+	return &Command{
+		Name:    "user",
+		Summary: "Manage users",
+		Subcommands: []*Command{
+			{Name: "create", Summary: "Create a user that can sign in", Run: runUserCreate},
+			{Name: "password", Summary: "Set a user's password, including users created before accounts existed", Run: runUserPassword},
+			{Name: "list", Summary: "List all users", Run: runUserList},
+			{Name: "delete", Summary: "Delete a user and all of their habits", Run: runUserDelete},
+			{Name: "admin", Summary: "Grant or revoke access to the administration endpoints", Run: runUserAdmin},
+		},
+	}
+}
+
+func runUserCreate(app *App, args []string) error {
+	flags := newFlags("user create", "-name <name> [-password <password>] [-admin]", "Create a user, reading the password from stdin if it is not given")
+	name := flags.String("name", "", "name the user signs in with")
+	password := flags.String("password", "", "password the user signs in with")
+	admin := flags.Bool("admin", false, "allow the user to use the administration endpoints")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	if err := readPassword(password); err != nil {
+		return err
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	authStore := authService.NewAuthService(db.Queries, app.logger)
+	user, err := authStore.CreateUser(context.Background(), *name, *password)
+	if err != nil {
+		return err
+	}
+
+	if *admin {
+		err = authStore.SetAdmin(user.Id, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(app.out, "Created user %q with ID %d\n", user.Name, user.Id)
+	return nil
+}
+
+func runUserPassword(app *App, args []string) error {
+	flags := newFlags("user password", "[-password <password>] <id>", "Set a user's password, reading it from stdin if it is not given")
+	password := flags.String("password", "", "password the user signs in with")
+	if err := parseFlags(flags, args, 1); err != nil {
+		return err
+	}
+
+	userId, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+	if err != nil {
+		flags.Usage()
+		return ErrUsage
+	}
+	if err := readPassword(password); err != nil {
+		return err
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	user, err := authService.NewAuthService(db.Queries, app.logger).SetPassword(context.Background(), userId, *password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.out, "Set the password for user %q\n", user.Name)
+	return nil
+}
+
+// readPassword reads the password from the first line of stdin if it was not
+// given as a flag, so it can be kept out of the shell history.
+func readPassword(password *string) error {
+	if *password != "" {
+		return nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
+
+func runUserList(app *App, args []string) error {
+	flags := newFlags("user list", "", "List all users")
+	if err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	users, err := authService.NewAuthService(db.Queries, app.logger).GetUsers()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(app.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tADMIN")
+	for _, user := range users {
+		fmt.Fprintf(w, "%d\t%s\t%t\n", user.Id, user.Name, user.Admin)
+	}
+
+	return w.Flush()
+}
+
+func runUserDelete(app *App, args []string) error {
+	flags := newFlags("user delete", "<id>", "Delete a user and all of their habits")
+	if err := parseFlags(flags, args, 1); err != nil {
+		return err
+	}
+
+	userId, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+	if err != nil {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = authService.NewAuthService(db.Queries, app.logger).DeleteUser(userId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.out, "Deleted user %d\n", userId)
+	return nil
+}
+
+func runUserAdmin(app *App, args []string) error {
+	flags := newFlags("user admin", "[-revoke] <id>", "Grant or revoke access to the administration endpoints")
+	revoke := flags.Bool("revoke", false, "revoke access instead of granting it")
+	if err := parseFlags(flags, args, 1); err != nil {
+		return err
+	}
+
+	userId, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+	if err != nil {
+		flags.Usage()
+		return ErrUsage
+	}
+
+	db, err := app.openStorage()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = authService.NewAuthService(db.Queries, app.logger).SetAdmin(userId, !*revoke)
+	if err != nil {
+		return err
+	}
+
+	if *revoke {
+		fmt.Fprintf(app.out, "User %d is no longer an admin\n", userId)
+	} else {
+		fmt.Fprintf(app.out, "User %d is now an admin\n", userId)
+	}
+	return nil
+}
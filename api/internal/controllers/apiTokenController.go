@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/apiTokensService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+type ApiTokenStore interface {
+	CreateToken(userId int64, name string, scope models.Scope) (models.ApiToken, error)
+	GetTokens(userId int64) ([]models.ApiToken, error)
+	RevokeToken(userId int64, tokenId int64) error
+}
+
+type ApiTokenController struct {
+	tokenStore ApiTokenStore
+	logger     logger.Logger
+}
+
+func NewApiTokenController(logger logger.Logger, tokenStore ApiTokenStore) *ApiTokenController {
+	return &ApiTokenController{
+		logger:     logger,
+		tokenStore: tokenStore,
+	}
+}
+
+func (a *ApiTokenController) GetTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := a.tokenStore.GetTokens(currentUserId(r))
+	if err != nil {
+		a.logger.Error("Failed to get API tokens", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, tokens)
+}
+
+func (a *ApiTokenController) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var token models.ApiToken
+	err := json.NewDecoder(r.Body).Decode(&token)
+	if err != nil {
+		a.logger.Error("Failed to decode API token", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	createdToken, err := a.tokenStore.CreateToken(currentUserId(r), token.Name, token.Scope)
+	if errors.Is(err, apiTokensService.ErrNameRequired) || errors.Is(err, apiTokensService.ErrInvalidScope) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		a.logger.Error("Failed to create API token", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("Created API token", slog.Int64("tokenId", createdToken.Id), slog.String("scope", string(createdToken.Scope)))
+	successWithBody(w, createdToken)
+}
+
+func (a *ApiTokenController) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	tokenId, err := strconv.ParseInt(r.PathValue("tokenId"), 10, 64)
+	if err != nil {
+		a.logger.Error("Failed to parse tokenId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = a.tokenStore.RevokeToken(currentUserId(r), tokenId)
+	if errors.Is(err, apiTokensService.ErrTokenNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		a.logger.Error("Failed to revoke API token", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("Revoked API token", slog.Int64("tokenId", tokenId))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+type AuditStore interface {
+	GetEvents(userId int64, filter auditService.Filter) (auditService.EventsPage, error)
+}
+
+type AuditController struct {
+	auditStore AuditStore
+	logger     logger.Logger
+}
+
+func NewAuditController(logger logger.Logger, auditStore AuditStore) *AuditController {
+	return &AuditController{
+		logger:     logger,
+		auditStore: auditStore,
+	}
+}
+
+// GetEvents returns a page of the user's audit events, newest first. They can
+// be filtered by entityType, entityId, action and the from and to RFC 3339
+// times. The cursor from one page is passed to get the next.
+func (a *AuditController) GetEvents(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, a.logger)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := auditService.Filter{
+		EntityType: query.Get("entityType"),
+		Action:     query.Get("action"),
+		Cursor:     query.Get("cursor"),
+		Limit:      defaultAuditLimit,
+	}
+
+	var err error
+	if value := query.Get("entityId"); value != "" {
+		filter.EntityId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "Invalid entityId")
+			return
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		filter.Limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || filter.Limit < 1 || filter.Limit > maxAuditLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "limit must be between 1 and %d", maxAuditLimit)
+			return
+		}
+	}
+	if value := query.Get("from"); value != "" {
+		filter.From, err = time.Parse(time.RFC3339, value)
+	}
+	if value := query.Get("to"); value != "" && err == nil {
+		filter.To, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Invalid time range: %v", err)
+		return
+	}
+
+	page, err := a.auditStore.GetEvents(userId, filter)
+	if errors.Is(err, auditService.ErrInvalidCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		a.logger.Error("Failed to get audit events", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, page)
+}
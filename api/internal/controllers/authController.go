@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+type credentials struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type AuthController struct {
+	authService *authService.AuthService
+	logger      logger.Logger
+}
+
+func NewAuthController(logger logger.Logger, authService *authService.AuthService) *AuthController {
+	return &AuthController{
+		logger:      logger,
+		authService: authService,
+	}
+}
+
+func (a *AuthController) Register(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	err := json.NewDecoder(r.Body).Decode(&creds)
+	if err != nil {
+		a.logger.Error("Failed to decode credentials", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	session, err := a.authService.Register(r.Context(), creds.Name, creds.Password)
+	switch {
+	case errors.Is(err, authService.ErrUserExists):
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err)
+		return
+	case errors.Is(err, authService.ErrNameRequired), errors.Is(err, authService.ErrPasswordTooShort):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	case err != nil:
+		a.logger.Error("Failed to register user", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("Registered user", slog.Int64("userId", session.User.Id))
+	setSessionCookie(w, r, session)
+	successWithBody(w, session.User)
+}
+
+func (a *AuthController) Login(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	err := json.NewDecoder(r.Body).Decode(&creds)
+	if err != nil {
+		a.logger.Error("Failed to decode credentials", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	session, err := a.authService.Login(creds.Name, creds.Password)
+	if errors.Is(err, authService.ErrInvalidCredentials) {
+		a.logger.Warn("Failed login attempt", slog.String("name", creds.Name))
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		a.logger.Error("Failed to log in", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("Logged in", slog.Int64("userId", session.User.Id))
+	setSessionCookie(w, r, session)
+	successWithBody(w, session.User)
+}
+
+func (a *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(authService.SessionCookieName)
+	if err == nil {
+		if err := a.authService.Logout(cookie.Value); err != nil {
+			a.logger.Error("Failed to delete session", slog.Any("error", err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     authService.SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *AuthController) Me(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.CurrentUser(r.Context())
+	successWithBody(w, user)
+}
+
+// UpdateSettings sets the timezone and hour the signed in user's days start at.
+func (a *AuthController) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, a.logger)
+	if !ok {
+		return
+	}
+
+	var settings models.UserSettings
+	err := json.NewDecoder(r.Body).Decode(&settings)
+	if err != nil {
+		a.logger.Error("Failed to decode settings", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := a.authService.UpdateSettings(r.Context(), userId, settings)
+	switch {
+	case errors.Is(err, authService.ErrInvalidSettings):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	case errors.Is(err, authService.ErrUserNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case err != nil:
+		a.logger.Error("Failed to update settings", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, user)
+}
+
+func setSessionCookie(w http.ResponseWriter, r *http.Request, session authService.Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     authService.SessionCookieName,
+		Value:    session.Token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
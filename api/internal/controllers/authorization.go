@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+// authorizeUserPath parses the userId path value and checks it is the signed in
+// user, writing the error response and returning false if not.
+func authorizeUserPath(w http.ResponseWriter, r *http.Request, logger logger.Logger) (int64, bool) {
+	userId, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil {
+		logger.Error("Failed to parse userId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
+
+	user, ok := middleware.CurrentUser(r.Context())
+	if !ok || user.Id != userId {
+		logger.Warn("Forbidden access to user", slog.Int64("userId", userId))
+		w.WriteHeader(http.StatusForbidden)
+		return 0, false
+	}
+
+	return userId, true
+}
+
+type HabitOwnerStore interface {
+	IsHabitOwner(userId int64, habitId int64) (bool, error)
+}
+
+// authorizeHabit writes a not found response and returns false if the habit
+// does not belong to the user, so other users' habit IDs are not revealed.
+func authorizeHabit(w http.ResponseWriter, logger logger.Logger, store HabitOwnerStore, userId int64, habitId int64) bool {
+	isOwner, err := store.IsHabitOwner(userId, habitId)
+	if err != nil {
+		logger.Error("Failed to check habit owner", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+
+	if !isOwner {
+		logger.Warn("Habit not found for user", slog.Int64("userId", userId), slog.Int64("habitId", habitId))
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
+
+// currentUserId returns the ID of the signed in user, or 0 if there isn't one.
+func currentUserId(r *http.Request) int64 {
+	user, _ := middleware.CurrentUser(r.Context())
+	return user.Id
+}
+
+// currentUserSettings returns the settings of the signed in user, which decide
+// the user's current day.
+func currentUserSettings(r *http.Request) models.UserSettings {
+	return userSettings(r.Context())
+}
+
+// userSettings returns the settings of the user signed in for ctx, such as
+// the context of a sync connection.
+func userSettings(ctx context.Context) models.UserSettings {
+	user, ok := middleware.CurrentUser(ctx)
+	if !ok {
+		return models.NewDefaultUserSettings()
+	}
+
+	return user.Settings
+}
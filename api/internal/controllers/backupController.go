@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/backupService"
+)
+
+type BackupStore interface {
+	CreateBackup() (backupService.Backup, error)
+	GetBackups() ([]backupService.Backup, error)
+	OpenBackup(name string) (*os.File, backupService.Backup, error)
+}
+
+type BackupController struct {
+	backupStore BackupStore
+	logger      logger.Logger
+}
+
+func NewBackupController(logger logger.Logger, backupStore BackupStore) *BackupController {
+	return &BackupController{
+		logger:      logger,
+		backupStore: backupStore,
+	}
+}
+
+func (b *BackupController) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	backup, err := b.backupStore.CreateBackup()
+	if err != nil {
+		b.logger.Error("Failed to create backup", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	b.logger.Info("Created backup on request", slog.Int64("userId", currentUserId(r)), slog.String("name", backup.Name))
+	successWithBody(w, backup)
+}
+
+func (b *BackupController) GetBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := b.backupStore.GetBackups()
+	if err != nil {
+		b.logger.Error("Failed to get backups", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, backups)
+}
+
+func (b *BackupController) DownloadBackup(w http.ResponseWriter, r *http.Request) {
+	file, backup, err := b.backupStore.OpenBackup(r.PathValue("name"))
+	if errors.Is(err, backupService.ErrBackupNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		b.logger.Error("Failed to open backup", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", backup.Name))
+	http.ServeContent(w, r, backup.Name, backup.CreatedAt, file)
+}
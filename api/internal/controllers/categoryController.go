@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/categoriesService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+type CategoryStore interface {
+	GetCategories(userId int64) ([]models.Category, error)
+	CreateCategory(ctx context.Context, userId int64, name string, colour string) (models.Category, error)
+	UpdateCategory(ctx context.Context, userId int64, categoryId int64, name string, colour string) (models.Category, error)
+	DeleteCategory(ctx context.Context, userId int64, categoryId int64) error
+}
+
+type categoryRequest struct {
+	Name   string `json:"name"`
+	Colour string `json:"colour"`
+}
+
+type CategoryController struct {
+	categoryStore CategoryStore
+	logger        logger.Logger
+}
+
+func NewCategoryController(logger logger.Logger, categoryStore CategoryStore) *CategoryController {
+	return &CategoryController{
+		logger:        logger,
+		categoryStore: categoryStore,
+	}
+}
+
+func (c *CategoryController) GetCategories(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	categories, err := c.categoryStore.GetCategories(userId)
+	if err != nil {
+		c.logger.Error("Failed to get categories", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, categories)
+}
+
+func (c *CategoryController) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	request, ok := c.decodeCategory(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := c.categoryStore.CreateCategory(r.Context(), userId, request.Name, request.Colour)
+	if c.writeCategoryError(w, err) {
+		return
+	}
+
+	c.logger.Info("Created category", slog.Int64("categoryId", category.Id))
+	successWithBody(w, category)
+}
+
+func (c *CategoryController) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	userId, categoryId, ok := c.parseCategoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	request, ok := c.decodeCategory(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := c.categoryStore.UpdateCategory(r.Context(), userId, categoryId, request.Name, request.Colour)
+	if c.writeCategoryError(w, err) {
+		return
+	}
+
+	c.logger.Info("Updated category", slog.Int64("categoryId", categoryId))
+	successWithBody(w, category)
+}
+
+// DeleteCategory deletes a category, leaving its habits without one.
+func (c *CategoryController) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	userId, categoryId, ok := c.parseCategoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := c.categoryStore.DeleteCategory(r.Context(), userId, categoryId)
+	if c.writeCategoryError(w, err) {
+		return
+	}
+
+	c.logger.Info("Deleted category", slog.Int64("categoryId", categoryId))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *CategoryController) decodeCategory(w http.ResponseWriter, r *http.Request) (categoryRequest, bool) {
+	var request categoryRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		c.logger.Error("Failed to decode category", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return categoryRequest{}, false
+	}
+
+	return request, true
+}
+
+// writeCategoryError writes the response for an error from the category store,
+// returning false if there was no error.
+func (c *CategoryController) writeCategoryError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, categoriesService.ErrCategoryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, categoriesService.ErrCategoryExists):
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err)
+	case errors.Is(err, categoriesService.ErrNameRequired), errors.Is(err, categoriesService.ErrNameTooLong):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+	default:
+		c.logger.Error("Failed to save category", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	return true
+}
+
+// parseCategoryPath parses the userId and categoryId path values, checking the
+// user is the signed in user.
+func (c *CategoryController) parseCategoryPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return 0, 0, false
+	}
+
+	categoryId, err := strconv.ParseInt(r.PathValue("categoryId"), 10, 64)
+	if err != nil {
+		c.logger.Error("Failed to parse categoryId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userId, categoryId, true
+}
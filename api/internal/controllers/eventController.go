@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+)
+
+// heartbeatInterval is how often a comment is sent on an idle event stream, so
+// proxies keep it open and clients notice when it has dropped.
+const heartbeatInterval = 15 * time.Second
+
+// EventPublisher tells a user's other devices about changes to their data.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
+}
+
+type EventSubscriber interface {
+	Subscribe(userId int64, lastEventId int64) (*eventsService.Subscription, []eventsService.Event)
+}
+
+type EventController struct {
+	events EventSubscriber
+	logger logger.Logger
+}
+
+func NewEventController(logger logger.Logger, events EventSubscriber) *EventController {
+	return &EventController{
+		logger: logger,
+		events: events,
+	}
+}
+
+// Stream sends the user's changes as server-sent events until they disconnect.
+// Reconnecting with a Last-Event-ID header first sends the events missed since,
+// or a resync event when they are no longer known.
+func (c *EventController) Stream(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	var lastEventId int64
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		var err error
+		lastEventId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "Last-Event-ID must be an event ID")
+			return
+		}
+	}
+
+	subscription, missed := c.events.Subscribe(userId, lastEventId)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		c.logger.Error("Failed to flush event stream", slog.Any("error", err))
+		return
+	}
+
+	c.logger.Debug("Event stream opened", slog.Int64("userId", userId), slog.Int("missed", len(missed)))
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			// The subscription ends when the client falls behind or the server
+			// shuts down, clients reconnect and resume from their last event
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event eventsService.Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, event.Data)
+	return err
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/exportService"
+)
+
+// maxImportSize is the largest import request body accepted.
+const maxImportSize = 32 << 20
+
+type ExportStore interface {
+	Export(userId int64) (exportService.Export, error)
+	Import(userId int64, export exportService.Export, mode exportService.ImportMode, dryRun bool) (exportService.ImportResult, error)
+	ExportCSV(userId int64, layout exportService.CSVLayout) ([][]string, error)
+	ImportCSV(userId int64, records [][]string, mode exportService.ImportMode, dryRun bool) (exportService.ImportResult, error)
+	ImportLoop(userId int64, path string, mode exportService.ImportMode, dryRun bool) (exportService.LoopImportResult, error)
+}
+
+type ExportController struct {
+	exportStore ExportStore
+	logger      logger.Logger
+}
+
+func NewExportController(logger logger.Logger, exportStore ExportStore) *ExportController {
+	return &ExportController{
+		logger:      logger,
+		exportStore: exportStore,
+	}
+}
+
+func (e *ExportController) Export(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, e.logger)
+	if !ok {
+		return
+	}
+
+	export, err := e.exportStore.Export(userId)
+	if err != nil {
+		e.logger.Error("Failed to export habits", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("habits-%s.json", export.ExportedAt.Format(time.DateOnly))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	e.logger.Info("Exported habits", slog.Int64("userId", userId), slog.Int("habits", len(export.Habits)))
+	successWithBody(w, export)
+}
+
+// ExportCSV exports the user's entries as CSV, one row per entry unless the
+// layout query parameter is calendar.
+func (e *ExportController) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, e.logger)
+	if !ok {
+		return
+	}
+
+	layout := exportService.CSVLayout(r.URL.Query().Get("layout"))
+	if layout == "" {
+		layout = exportService.CSVEntries
+	}
+
+	records, err := e.exportStore.ExportCSV(userId, layout)
+	if errors.Is(err, exportService.ErrInvalidLayout) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		e.logger.Error("Failed to export habits", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("habits-%s-%s.csv", layout, currentUserSettings(r).Today().Format(time.DateOnly))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	err = csv.NewWriter(w).WriteAll(records)
+	if err != nil {
+		e.logger.Error("Failed to write CSV", slog.Any("error", err))
+	}
+}
+
+// Import imports an export document, merging it into the user's habits unless
+// the mode query parameter is replace.
+func (e *ExportController) Import(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, e.logger)
+	if !ok {
+		return
+	}
+
+	mode, dryRun, ok := e.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var export exportService.Export
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&export)
+	if err != nil {
+		e.logger.Error("Failed to decode export", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Failed to decode export: %v", err)
+		return
+	}
+
+	result, err := e.exportStore.Import(userId, export, mode, dryRun)
+	e.writeImportResult(w, userId, mode, result, err)
+}
+
+// ImportCSV imports CSV in either the entries or calendar layout. Habits are
+// matched to the user's habits by name, and created if there is no match.
+func (e *ExportController) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, e.logger)
+	if !ok {
+		return
+	}
+
+	mode, dryRun, ok := e.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	reader := csv.NewReader(http.MaxBytesReader(w, r.Body, maxImportSize))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		e.logger.Error("Failed to read CSV", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Failed to read CSV: %v", err)
+		return
+	}
+
+	result, err := e.exportStore.ImportCSV(userId, records, mode, dryRun)
+	e.writeImportResult(w, userId, mode, result, err)
+}
+
+// ImportLoop imports a Loop Habit Tracker backup, sent either as the request
+// body or as the file field of a multipart form.
+func (e *ExportController) ImportLoop(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, e.logger)
+	if !ok {
+		return
+	}
+
+	mode, dryRun, ok := e.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	var backup io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			e.logger.Error("Failed to read backup file", slog.Any("error", err))
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Failed to read backup file: %v", err)
+			return
+		}
+		defer file.Close()
+		backup = file
+	}
+
+	// The backup is a SQLite database, which can only be opened from a file.
+	file, err := os.CreateTemp("", "loop-backup-*.db")
+	if err != nil {
+		e.logger.Error("Failed to create backup file", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, backup)
+	file.Close()
+	if err != nil {
+		e.logger.Error("Failed to save backup file", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := e.exportStore.ImportLoop(userId, file.Name(), mode, dryRun)
+	if errors.Is(err, exportService.ErrInvalidLoopBackup) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		e.writeImportResult(w, userId, mode, result.ImportResult, err)
+		return
+	}
+
+	e.logger.Info("Imported Loop Habit Tracker backup", slog.Int64("userId", userId), slog.Bool("dryRun", dryRun), slog.Int("skipped", len(result.Skipped)))
+	successWithBody(w, result)
+}
+
+// importOptions reads the mode and dryRun query parameters, writing a bad
+// request response and returning false if dryRun is not a boolean.
+func (e *ExportController) importOptions(w http.ResponseWriter, r *http.Request) (exportService.ImportMode, bool, bool) {
+	mode := exportService.ImportMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = exportService.ImportMerge
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "dryRun must be true or false")
+			return "", false, false
+		}
+	}
+
+	return mode, dryRun, true
+}
+
+func (e *ExportController) writeImportResult(w http.ResponseWriter, userId int64, mode exportService.ImportMode, result exportService.ImportResult, err error) {
+	if errors.Is(err, exportService.ErrUnsupportedVersion) || errors.Is(err, exportService.ErrInvalidMode) || errors.Is(err, exportService.ErrInvalidExport) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		e.logger.Error("Failed to import habits", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	e.logger.Info("Imported habits", slog.Int64("userId", userId), slog.String("mode", string(mode)), slog.Bool("dryRun", result.DryRun), slog.Int("conflicts", len(result.Conflicts)))
+	successWithBody(w, result)
+}
@@ -1,23 +1,41 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
 	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
 )
 
 type HabitStore interface {
-	GetActiveHabits(userId int64) ([]habitsService.Habit, error)
-	GetHabits(userId int64) ([]habitsService.Habit, error)
-	UpdateHabits(habits []habitsService.Habit) ([]habitsService.Habit, error)
-	DeleteHabit(habitId int64) (habitsService.Habit, error)
-	CreateHabit(userId int64, name string, colour string) (habitsService.Habit, error)
+	HabitOwnerStore
+	GetActiveHabits(userId int64, dateRange models.DateRange) ([]habitsService.Habit, error)
+	GetHabits(userId int64, dateRange models.DateRange) ([]habitsService.Habit, error)
+	GetArchivedHabits(userId int64, dateRange models.DateRange) ([]habitsService.Habit, error)
+	GetHabitEntries(habitId int64, dateRange models.DateRange, limit int64) (models.HabitEntriesPage, error)
+	UpdateHabits(ctx context.Context, userId int64, habits []habitsService.Habit) ([]habitsService.Habit, error)
+	ReorderHabits(ctx context.Context, userId int64, habitIds []int64) ([]habitsService.Habit, error)
+	ArchiveHabit(ctx context.Context, userId int64, habitId int64) (habitsService.Habit, error)
+	UnarchiveHabit(ctx context.Context, userId int64, habitId int64) (habitsService.Habit, error)
+	PurgeHabit(ctx context.Context, userId int64, habitId int64) (habitsService.Habit, error)
+	CreateHabit(ctx context.Context, userId int64, habit habitsService.Habit) (habitsService.Habit, error)
+	GroupByCategory(userId int64, habits []habitsService.Habit) ([]habitsService.HabitGroup, error)
 }
 
+const (
+	defaultEntriesLimit = 100
+	maxEntriesLimit     = 1000
+)
+
 type HabitController struct {
 	habitsStore HabitStore
 	logger      logger.Logger
@@ -30,26 +48,141 @@ func NewHabitController(logger logger.Logger, habitStore HabitStore) *HabitContr
 	}
 }
 
+// GetHabits returns the user's active habits, or their archived habits when
+// archived is true. The category query parameter keeps only the habits in a
+// category, or without one when it is none, each tag query parameter keeps only
+// the habits with that tag, and group=category groups them by category.
 func (h *HabitController) GetHabits(w http.ResponseWriter, r *http.Request) {
-	userId, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
-	if err != nil {
-		h.logger.Error("Failed to parse userId", slog.Any("error", err))
+	userId, ok := authorizeUserPath(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	dateRange, ok := h.parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	group := query.Get("group")
+	if group != "" && group != "category" {
 		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "group must be category")
 		return
 	}
 
-	activeHabits, err := h.habitsStore.GetActiveHabits(userId)
+	var habits []habitsService.Habit
+	var err error
+	if query.Get("archived") == "true" {
+		habits, err = h.habitsStore.GetArchivedHabits(userId, dateRange)
+	} else {
+		habits, err = h.habitsStore.GetActiveHabits(userId, dateRange)
+	}
 	if err != nil {
 		h.logger.Error("Failed to get habits", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Debug("Got habits", slog.Any("habits", activeHabits))
-	successWithBody(w, activeHabits)
+	if value := query.Get("category"); value != "" {
+		var categoryId int64
+		if value != "none" {
+			categoryId, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || categoryId < 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, "category must be a category ID or none")
+				return
+			}
+		}
+		habits = habitsService.FilterByCategory(habits, categoryId)
+	}
+
+	if values := query["tag"]; len(values) > 0 {
+		tagIds := make([]int64, len(values))
+		for i, value := range values {
+			tagIds[i], err = strconv.ParseInt(value, 10, 64)
+			if err != nil || tagIds[i] < 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, "tag must be a tag ID")
+				return
+			}
+		}
+		habits = habitsService.FilterByTags(habits, tagIds)
+	}
+
+	if group == "" {
+		h.logger.Debug("Got habits", slog.Any("habits", habits))
+		successWithBody(w, habits)
+		return
+	}
+
+	groups, err := h.habitsStore.GroupByCategory(userId, habits)
+	if err != nil {
+		h.logger.Error("Failed to group habits", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, groups)
+}
+
+// GetHabitEntries returns a page of a habit's entries, optionally between the
+// from and to dates. The cursor from one page is passed to get the next.
+func (h *HabitController) GetHabitEntries(w http.ResponseWriter, r *http.Request) {
+	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
+	if err != nil {
+		h.logger.Error("Failed to parse habitId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !h.authorizeHabit(w, currentUserId(r), habitId) {
+		return
+	}
+
+	dateRange, ok := h.parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	var limit int64 = defaultEntriesLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 1 || limit > maxEntriesLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "limit must be between 1 and %d", maxEntriesLimit)
+			return
+		}
+	}
+
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		cursor, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Invalid cursor")
+			return
+		}
+
+		if next := cursor.AddDate(0, 0, 1); next.After(dateRange.From) {
+			dateRange.From = next
+		}
+	}
+
+	page, err := h.habitsStore.GetHabitEntries(habitId, dateRange, limit)
+	if err != nil {
+		h.logger.Error("Failed to get habit entries", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, page)
 }
 
 func (h *HabitController) EditHabits(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, h.logger)
+	if !ok {
+		return
+	}
+
 	var habits []habitsService.Habit
 	err := json.NewDecoder(r.Body).Decode(&habits)
 	if err != nil {
@@ -58,7 +191,16 @@ func (h *HabitController) EditHabits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedHabits, err := h.habitsStore.UpdateHabits(habits)
+	for _, habit := range habits {
+		if !h.validSchedule(w, habit.Schedule) || !h.validTarget(w, habit.Target) {
+			return
+		}
+	}
+
+	updatedHabits, err := h.habitsStore.UpdateHabits(r.Context(), userId, habits)
+	if h.writeDetailsError(w, err) || h.writeConflictError(w, err) || h.writeNotFoundError(w, userId, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to edit habit", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -69,6 +211,46 @@ func (h *HabitController) EditHabits(w http.ResponseWriter, r *http.Request) {
 	successWithBody(w, updatedHabits)
 }
 
+type reorderHabitsRequest struct {
+	HabitIds []int64 `json:"habitIds"`
+}
+
+// ReorderHabits moves a user's habits into the order of the given habit IDs.
+func (h *HabitController) ReorderHabits(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var request reorderHabitsRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.logger.Error("Failed to decode habit order", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	habits, err := h.habitsStore.ReorderHabits(r.Context(), userId, request.HabitIds)
+	switch {
+	case errors.Is(err, habitsService.ErrHabitNotFound):
+		h.logger.Warn("Habit not found for user", slog.Int64("userId", userId), slog.Any("error", err))
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, err)
+		return
+	case errors.Is(err, habitsService.ErrInvalidOrder):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	case err != nil:
+		h.logger.Error("Failed to reorder habits", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Reordered habits", slog.Int64("userId", userId))
+	successWithBody(w, habits)
+}
+
 func (h *HabitController) EditHabit(w http.ResponseWriter, r *http.Request) {
 	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
 	if err != nil {
@@ -76,6 +258,9 @@ func (h *HabitController) EditHabit(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !h.authorizeHabit(w, currentUserId(r), habitId) {
+		return
+	}
 
 	var habit habitsService.Habit
 	err = json.NewDecoder(r.Body).Decode(&habit)
@@ -84,8 +269,24 @@ func (h *HabitController) EditHabit(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !h.validSchedule(w, habit.Schedule) || !h.validTarget(w, habit.Target) {
+		return
+	}
+	habit.Id = habitId
+	if habit.Version == 0 {
+		version, ok := parseIfMatch(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "If-Match must be a habit version")
+			return
+		}
+		habit.Version = version
+	}
 
-	updatedHabits, err := h.habitsStore.UpdateHabits([]habitsService.Habit{habit})
+	updatedHabits, err := h.habitsStore.UpdateHabits(r.Context(), currentUserId(r), []habitsService.Habit{habit})
+	if h.writeDetailsError(w, err) || h.writeConflictError(w, err) || h.writeNotFoundError(w, currentUserId(r), err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to edit habit", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -97,22 +298,26 @@ func (h *HabitController) EditHabit(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HabitController) CreateHabit(w http.ResponseWriter, r *http.Request) {
-	userId, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
-	if err != nil {
-		h.logger.Error("Failed to parse userId", slog.Any("error", err))
-		w.WriteHeader(http.StatusBadRequest)
+	userId, ok := authorizeUserPath(w, r, h.logger)
+	if !ok {
 		return
 	}
 
 	var habit habitsService.Habit
-	err = json.NewDecoder(r.Body).Decode(&habit)
+	err := json.NewDecoder(r.Body).Decode(&habit)
 	if err != nil {
 		h.logger.Error("Failed to decode habit", slog.Any("error", err))
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !h.validSchedule(w, habit.Schedule) || !h.validTarget(w, habit.Target) {
+		return
+	}
 
-	createdHabit, err := h.habitsStore.CreateHabit(userId, habit.Name, habit.Colour)
+	createdHabit, err := h.habitsStore.CreateHabit(r.Context(), userId, habit)
+	if h.writeDetailsError(w, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to create habit", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -123,21 +328,212 @@ func (h *HabitController) CreateHabit(w http.ResponseWriter, r *http.Request) {
 	successWithBody(w, createdHabit)
 }
 
-func (h *HabitController) DeleteHabit(w http.ResponseWriter, r *http.Request) {
+// ArchiveHabit hides a habit, keeping its entries so it can be unarchived.
+func (h *HabitController) ArchiveHabit(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *HabitController) UnarchiveHabit(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *HabitController) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
+	if err != nil {
+		h.logger.Error("Failed to parse habitId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	userId := currentUserId(r)
+	if !h.authorizeHabit(w, userId, habitId) {
+		return
+	}
+
+	var habit habitsService.Habit
+	if archived {
+		habit, err = h.habitsStore.ArchiveHabit(r.Context(), userId, habitId)
+	} else {
+		habit, err = h.habitsStore.UnarchiveHabit(r.Context(), userId, habitId)
+	}
+	if h.writeNotFoundError(w, userId, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to archive habit", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Archived habit", slog.Int64("habitId", habitId), slog.Bool("archived", archived))
+	successWithBody(w, habit)
+}
+
+type purgeHabitRequest struct {
+	Confirm bool `json:"confirm"`
+}
+
+// PurgeHabit deletes an archived habit and all of its entries, moving them to
+// the trash until the retention period has passed. The request must confirm
+// the purge.
+func (h *HabitController) PurgeHabit(w http.ResponseWriter, r *http.Request) {
 	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
 	if err != nil {
 		h.logger.Error("Failed to parse habitId", slog.Any("error", err))
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	userId := currentUserId(r)
+	if !h.authorizeHabit(w, userId, habitId) {
+		return
+	}
 
-	deletedHabit, err := h.habitsStore.DeleteHabit(habitId)
+	var request purgeHabitRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil || !request.Confirm {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "Purging deletes the habit and all of its entries, send {\"confirm\": true} to continue")
+		return
+	}
+
+	purgedHabit, err := h.habitsStore.PurgeHabit(r.Context(), userId, habitId)
+	if errors.Is(err, habitsService.ErrHabitNotArchived) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err)
+		return
+	}
 	if err != nil {
-		h.logger.Error("Failed to delete habit", slog.Any("error", err))
+		h.logger.Error("Failed to purge habit", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Info("Deleted habit", slog.Int64("habitId", habitId))
-	successWithBody(w, deletedHabit)
+	h.logger.Info("Purged habit", slog.Int64("habitId", habitId))
+	successWithBody(w, purgedHabit)
+}
+
+// writeDetailsError writes a bad request response and returns true if err is
+// from a habit's description, icon or category not being valid.
+func (h *HabitController) writeDetailsError(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, habitsService.ErrCategoryNotFound) && !errors.Is(err, habitsService.ErrDescriptionTooLong) && !errors.Is(err, habitsService.ErrIconTooLong) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprint(w, err)
+	return true
+}
+
+// writeConflictError writes a conflict response and returns true if err is from
+// a habit having changed since the version that was updated, or a precondition
+// required response if no version was given.
+func (h *HabitController) writeConflictError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, habitsService.ErrVersionRequired):
+		w.WriteHeader(http.StatusPreconditionRequired)
+	case errors.Is(err, habitsService.ErrHabitConflict):
+		h.logger.Warn("Habit changed since it was read", slog.Any("error", err))
+		w.WriteHeader(http.StatusConflict)
+	default:
+		return false
+	}
+
+	fmt.Fprint(w, err)
+	return true
+}
+
+// writeNotFoundError writes a not found response and returns true if err is
+// from a habit that doesn't exist, such as one deleted since it was authorized.
+func (h *HabitController) writeNotFoundError(w http.ResponseWriter, userId int64, err error) bool {
+	if !errors.Is(err, habitsService.ErrHabitNotFound) {
+		return false
+	}
+
+	h.logger.Warn("Habit not found for user", slog.Int64("userId", userId), slog.Any("error", err))
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, err)
+	return true
+}
+
+// parseIfMatch returns the habit version in the request's If-Match header, or
+// 0 if there is none.
+func parseIfMatch(r *http.Request) (int64, bool) {
+	value := r.Header.Get("If-Match")
+	if value == "" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+func (h *HabitController) authorizeHabit(w http.ResponseWriter, userId int64, habitId int64) bool {
+	return authorizeHabit(w, h.logger, h.habitsStore, userId, habitId)
+}
+
+func (h *HabitController) parseDateRange(w http.ResponseWriter, r *http.Request) (models.DateRange, bool) {
+	return parseDateRange(w, r, h.logger)
+}
+
+// parseDateRange reads the optional from and to query parameters, writing a bad
+// request response and returning false if they are not valid dates.
+func parseDateRange(w http.ResponseWriter, r *http.Request, logger logger.Logger) (models.DateRange, bool) {
+	var dateRange models.DateRange
+	var err error
+	query := r.URL.Query()
+	if value := query.Get("from"); value != "" {
+		dateRange.From, err = time.Parse(time.DateOnly, value)
+	}
+	if value := query.Get("to"); value != "" && err == nil {
+		dateRange.To, err = time.Parse(time.DateOnly, value)
+	}
+	if err == nil {
+		err = dateRange.Validate()
+	}
+
+	if err != nil {
+		logger.Error("Invalid date range", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Invalid date range: %v", err)
+		return models.DateRange{}, false
+	}
+
+	return dateRange, true
+}
+
+// validSchedule writes a bad request response and returns false if a schedule
+// was provided but is not valid. An omitted schedule is left to the store to default.
+func (h *HabitController) validSchedule(w http.ResponseWriter, schedule models.Schedule) bool {
+	if schedule.IsZero() {
+		return true
+	}
+
+	if err := schedule.Validate(); err != nil {
+		h.logger.Error("Invalid schedule", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Invalid schedule: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// validTarget writes a bad request response and returns false if a target
+// was provided but is not valid. An omitted target is left to the store to default.
+func (h *HabitController) validTarget(w http.ResponseWriter, target models.Target) bool {
+	if target.IsZero() {
+		return true
+	}
+
+	if err := target.Validate(); err != nil {
+		h.logger.Error("Invalid target", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Invalid target: %v", err)
+		return false
+	}
+
+	return true
 }
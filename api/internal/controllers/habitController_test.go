@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHabitStore owns every habit, but fails to change them with err, as if
+// they had been deleted since they were authorized.
+type mockHabitStore struct {
+	err error
+}
+
+func (m *mockHabitStore) IsHabitOwner(_ int64, _ int64) (bool, error) {
+	return true, nil
+}
+
+func (m *mockHabitStore) GetActiveHabits(_ int64, _ models.DateRange) ([]habitsService.Habit, error) {
+	return nil, nil
+}
+
+func (m *mockHabitStore) GetHabits(_ int64, _ models.DateRange) ([]habitsService.Habit, error) {
+	return nil, nil
+}
+
+func (m *mockHabitStore) GetArchivedHabits(_ int64, _ models.DateRange) ([]habitsService.Habit, error) {
+	return nil, nil
+}
+
+func (m *mockHabitStore) GetHabitEntries(_ int64, _ models.DateRange, _ int64) (models.HabitEntriesPage, error) {
+	return models.HabitEntriesPage{}, nil
+}
+
+func (m *mockHabitStore) UpdateHabits(_ context.Context, _ int64, _ []habitsService.Habit) ([]habitsService.Habit, error) {
+	return nil, m.err
+}
+
+func (m *mockHabitStore) ReorderHabits(_ context.Context, _ int64, _ []int64) ([]habitsService.Habit, error) {
+	return nil, m.err
+}
+
+func (m *mockHabitStore) ArchiveHabit(_ context.Context, _ int64, _ int64) (habitsService.Habit, error) {
+	return habitsService.Habit{}, m.err
+}
+
+func (m *mockHabitStore) UnarchiveHabit(_ context.Context, _ int64, _ int64) (habitsService.Habit, error) {
+	return habitsService.Habit{}, m.err
+}
+
+func (m *mockHabitStore) PurgeHabit(_ context.Context, _ int64, _ int64) (habitsService.Habit, error) {
+	return habitsService.Habit{}, m.err
+}
+
+func (m *mockHabitStore) CreateHabit(_ context.Context, _ int64, _ habitsService.Habit) (habitsService.Habit, error) {
+	return habitsService.Habit{}, m.err
+}
+
+func (m *mockHabitStore) GroupByCategory(_ int64, _ []habitsService.Habit) ([]habitsService.HabitGroup, error) {
+	return nil, nil
+}
+
+func TestHabitNotFound(t *testing.T) {
+	tests := []struct {
+		handler      func(controller *HabitController) http.HandlerFunc
+		name         string
+		method       string
+		body         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name:         "editing a deleted habit",
+			handler:      func(controller *HabitController) http.HandlerFunc { return controller.EditHabit },
+			method:       http.MethodPut,
+			body:         `{"name": "Run", "colour": "red", "version": 1}`,
+			err:          fmt.Errorf("%w: %d", habitsService.ErrHabitNotFound, 1),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "archiving a deleted habit",
+			handler:      func(controller *HabitController) http.HandlerFunc { return controller.ArchiveHabit },
+			method:       http.MethodPost,
+			err:          habitsService.ErrHabitNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unarchiving a deleted habit",
+			handler:      func(controller *HabitController) http.HandlerFunc { return controller.UnarchiveHabit },
+			method:       http.MethodPost,
+			err:          habitsService.ErrHabitNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "failing to archive a habit",
+			handler:      func(controller *HabitController) http.HandlerFunc { return controller.ArchiveHabit },
+			method:       http.MethodPost,
+			err:          errors.New("database is locked"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller := NewHabitController(&logger.MockLogger{}, &mockHabitStore{err: tc.err})
+			request := httptest.NewRequest(tc.method, "/api/habits/1", strings.NewReader(tc.body))
+			request.SetPathValue("habitId", "1")
+			request = request.WithContext(middleware.WithUser(request.Context(), models.User{Id: 7}))
+			recorder := httptest.NewRecorder()
+
+			// Act
+			tc.handler(controller)(recorder, request)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, recorder.Code, recorder.Body.String())
+		})
+	}
+}
@@ -1,30 +1,99 @@
 package controllers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
 	"github.com/ReidMason/habit-tracker/internal/storage"
 )
 
+type habitEntryRequest struct {
+	Date      entryDate          `json:"date"`
+	Value     *float64           `json:"value"`
+	Status    models.EntryStatus `json:"status"`
+	HabitId   int64              `json:"habitId"`
+	Increment bool               `json:"increment"`
+}
+
+// entryDate is the day an entry is for, given either as a date or as the time
+// the habit was checked in at.
+type entryDate struct {
+	time.Time
+	dateOnly bool
+}
+
+func (d *entryDate) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		*d = entryDate{}
+		return nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		*d = entryDate{Time: date, dateOnly: true}
+		return nil
+	}
+
+	checkedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("date must be YYYY-MM-DD or an RFC 3339 time: %w", err)
+	}
+
+	*d = entryDate{Time: checkedAt}
+	return nil
+}
+
+// Day returns the day the entry is for. A time is on the user's date at that
+// instant, which depends on their timezone and when their days start.
+func (d entryDate) Day(settings models.UserSettings) time.Time {
+	if d.dateOnly {
+		return d.Time
+	}
+
+	return settings.Date(d.Time)
+}
+
+type habitEntryNoteRequest struct {
+	Note string `json:"note"`
+}
+
+type habitEntryStatusRequest struct {
+	Status models.EntryStatus `json:"status"`
+}
+
+const maxNoteLength = 500
+
 type HabitEntryController struct {
-	db     *storage.Sqlite
+	db     *storage.Database
 	logger logger.Logger
+	events EventPublisher
 }
 
-func NewHabitEntryController(db *storage.Sqlite, logger logger.Logger) *HabitEntryController {
+func NewHabitEntryController(db *storage.Database, logger logger.Logger, events EventPublisher) *HabitEntryController {
 	return &HabitEntryController{
 		db:     db,
 		logger: logger,
+		events: events,
 	}
 }
 
 func (h *HabitEntryController) CreateHabitEntry(w http.ResponseWriter, r *http.Request) {
-	var habitEntry storage.HabitEntry
+	var habitEntry habitEntryRequest
 	err := json.NewDecoder(r.Body).Decode(&habitEntry)
 	if err != nil {
 		h.logger.Error("Failed to decode habit entry", slog.Any("error", err))
@@ -32,31 +101,161 @@ func (h *HabitEntryController) CreateHabitEntry(w http.ResponseWriter, r *http.R
 		fmt.Fprintf(w, "Failed to decode habit entry: %v", err)
 		return
 	}
+
+	createdEntry, err := h.checkHabit(r.Context(), currentUserId(r), habitEntry)
+	if writeRequestError(w, err) {
+		return
+	}
+
+	successWithBody(w, createdEntry)
+}
+
+// checkHabit records an entry on one of the user's habits, adding to the day's
+// value instead of replacing it when the request is an increment. The day is
+// taken from the date using the settings of the user signed in for ctx.
+func (h *HabitEntryController) checkHabit(ctx context.Context, userId int64, habitEntry habitEntryRequest) (storage.HabitEntry, error) {
 	if habitEntry.Date.IsZero() {
-		h.logger.Error("Date is required", slog.Any("error", err))
+		h.logger.Error("Date is required")
+		return storage.HabitEntry{}, &requestError{status: http.StatusBadRequest, message: "Date is required"}
+	}
+	if habitEntry.HabitId == 0 {
+		h.logger.Error("HabitId is required")
+		return storage.HabitEntry{}, &requestError{status: http.StatusBadRequest, message: "HabitId is required"}
+	}
+
+	if habitEntry.Status == "" {
+		habitEntry.Status = models.EntryDone
+	}
+	if err := h.validateStatus(habitEntry.Status); err != nil {
+		return storage.HabitEntry{}, err
+	}
+	if habitEntry.Increment && habitEntry.Status != models.EntryDone {
+		return storage.HabitEntry{}, &requestError{status: http.StatusBadRequest, message: "Only done entries can be incremented"}
+	}
+	// Increments can be negative to undo one, as long as the day's value stays
+	// at or above zero
+	if !habitEntry.Increment && habitEntry.Value != nil && *habitEntry.Value < 0 {
+		return storage.HabitEntry{}, &requestError{status: http.StatusBadRequest, message: "Value must not be negative"}
+	}
+
+	habitUserId, err := h.db.GetHabitUserId(habitEntry.HabitId)
+	if err := h.checkOwner(userId, habitUserId, err); err != nil {
+		return storage.HabitEntry{}, err
+	}
+
+	// Skipped and failed days have nothing to count unless a value is given
+	value := 1.0
+	if habitEntry.Status != models.EntryDone {
+		value = 0
+	}
+	if habitEntry.Value != nil {
+		value = *habitEntry.Value
+	}
+
+	day := habitEntry.Date.Day(userSettings(ctx))
+	var createdEntry storage.HabitEntry
+	if habitEntry.Increment {
+		createdEntry, err = h.db.IncrementHabitEntry(ctx, habitEntry.HabitId, day, value)
+	} else {
+		createdEntry, err = h.db.CreateHabitEntry(ctx, habitEntry.HabitId, day, value, string(habitEntry.Status))
+	}
+	if errors.Is(err, storage.ErrNegativeValue) {
+		return storage.HabitEntry{}, &requestError{status: http.StatusBadRequest, message: err.Error()}
+	}
+	if errors.Is(err, storage.ErrEntryExists) {
+		return storage.HabitEntry{}, &requestError{status: http.StatusConflict, message: "The habit already has an entry for the day, change its status or increment it instead"}
+	}
+	if err != nil {
+		h.logger.Error("Failed to check habit", slog.Any("error", err))
+		return storage.HabitEntry{}, err
+	}
+
+	h.logger.Info("Checked habit", slog.Int64("habitId", createdEntry.HabitId), slog.Float64("value", createdEntry.Value))
+	h.events.Publish(habitUserId, eventsService.EntryCreated, createdEntry)
+	return createdEntry, nil
+}
+
+func (h *HabitEntryController) DeleteHabitEntry(w http.ResponseWriter, r *http.Request) {
+	entryId, err := strconv.ParseInt(r.PathValue("entryId"), 10, 64)
+	if err != nil {
+		h.logger.Error("Failed to parse entryId", slog.Any("error", err))
 		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "Date is required")
 		return
 	}
-	if habitEntry.HabitId == 0 {
-		h.logger.Error("HabitId is required", slog.Any("error", err))
+
+	habitEntry, err := h.uncheckHabit(r.Context(), currentUserId(r), entryId)
+	if writeRequestError(w, err) {
+		return
+	}
+
+	successWithBody(w, habitEntry)
+}
+
+// uncheckHabit deletes an entry from one of the user's habits.
+func (h *HabitEntryController) uncheckHabit(ctx context.Context, userId int64, entryId int64) (storage.HabitEntry, error) {
+	entryUserId, err := h.db.GetHabitEntryUserId(entryId)
+	if err := h.checkOwner(userId, entryUserId, err); err != nil {
+		return storage.HabitEntry{}, err
+	}
+
+	habitEntry, err := h.db.DeleteHabitEntry(ctx, entryId)
+	if err != nil {
+		h.logger.Error("Failed to uncheck habit", slog.Any("error", err))
+		return storage.HabitEntry{}, err
+	}
+
+	h.logger.Info("Checked habit", slog.Int64("habitEntry", entryId))
+	h.events.Publish(entryUserId, eventsService.EntryDeleted, habitEntry)
+	return habitEntry, nil
+}
+
+// SetNote replaces the note on an entry, an empty note removes it.
+func (h *HabitEntryController) SetNote(w http.ResponseWriter, r *http.Request) {
+	entryId, err := strconv.ParseInt(r.PathValue("entryId"), 10, 64)
+	if err != nil {
+		h.logger.Error("Failed to parse entryId", slog.Any("error", err))
 		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "HabitId is required")
 		return
 	}
 
-	habitEntry, err = h.db.CreateHabitEntry(habitEntry.HabitId, habitEntry.Date)
+	var request habitEntryNoteRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		h.logger.Error("Failed to check habit", slog.Any("error", err))
+		h.logger.Error("Failed to decode note", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Failed to decode note: %v", err)
+		return
+	}
+	note := strings.TrimSpace(request.Note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Note must be at most %d characters", maxNoteLength)
+		return
+	}
+
+	entryUserId, err := h.db.GetHabitEntryUserId(entryId)
+	if !h.authorize(w, r, entryUserId, err) {
+		return
+	}
+
+	habitEntry, err := h.db.SetHabitEntryNote(r.Context(), entryId, note)
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to set note", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Info("Checked habit", slog.Int64("habitId", habitEntry.HabitId))
+	h.logger.Info("Set habit entry note", slog.Int64("habitEntry", entryId))
+	h.events.Publish(entryUserId, eventsService.EntryUpdated, habitEntry)
 	successWithBody(w, habitEntry)
 }
 
-func (h *HabitEntryController) DeleteHabitEntry(w http.ResponseWriter, r *http.Request) {
+// SetStatus marks an entry as done, skipped or failed.
+func (h *HabitEntryController) SetStatus(w http.ResponseWriter, r *http.Request) {
 	entryId, err := strconv.ParseInt(r.PathValue("entryId"), 10, 64)
 	if err != nil {
 		h.logger.Error("Failed to parse entryId", slog.Any("error", err))
@@ -64,13 +263,105 @@ func (h *HabitEntryController) DeleteHabitEntry(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	habitEntry, err := h.db.DeleteHabitEntry(entryId)
+	var request habitEntryStatusRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		h.logger.Error("Failed to uncheck habit", slog.Any("error", err))
+		h.logger.Error("Failed to decode status", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Failed to decode status: %v", err)
+		return
+	}
+	if !h.validStatus(w, request.Status) {
+		return
+	}
+
+	entryUserId, err := h.db.GetHabitEntryUserId(entryId)
+	if !h.authorize(w, r, entryUserId, err) {
+		return
+	}
+
+	habitEntry, err := h.db.SetHabitEntryStatus(r.Context(), entryId, string(request.Status))
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to set status", slog.Any("error", err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.Info("Checked habit", slog.Int64("habitEntry", entryId))
+	h.logger.Info("Set habit entry status", slog.Int64("habitEntry", entryId), slog.String("status", habitEntry.Status))
+	h.events.Publish(entryUserId, eventsService.EntryUpdated, habitEntry)
 	successWithBody(w, habitEntry)
 }
+
+// validStatus writes a bad request response and returns false if the status
+// is not done, skipped or failed.
+func (h *HabitEntryController) validStatus(w http.ResponseWriter, status models.EntryStatus) bool {
+	return !writeRequestError(w, h.validateStatus(status))
+}
+
+func (h *HabitEntryController) validateStatus(status models.EntryStatus) error {
+	if err := status.Validate(); err != nil {
+		h.logger.Error("Invalid status", slog.Any("error", err))
+		return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Invalid status: %v", err)}
+	}
+
+	return nil
+}
+
+// authorize writes a not found response and returns false unless the owner
+// lookup succeeded and the habit belongs to the signed in user.
+func (h *HabitEntryController) authorize(w http.ResponseWriter, r *http.Request, ownerId int64, err error) bool {
+	return !writeRequestError(w, h.checkOwner(currentUserId(r), ownerId, err))
+}
+
+// checkOwner returns a not found requestError unless the owner lookup succeeded
+// and the habit belongs to the user, so other users' IDs are not revealed.
+func (h *HabitEntryController) checkOwner(userId int64, ownerId int64, err error) error {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		h.logger.Error("Failed to check habit owner", slog.Any("error", err))
+		return err
+	}
+
+	if err != nil || ownerId != userId {
+		h.logger.Warn("Habit not found for user", slog.Int64("userId", userId))
+		return &requestError{status: http.StatusNotFound}
+	}
+
+	return nil
+}
+
+// requestError is a refused request, with the status and message it is
+// reported with.
+type requestError struct {
+	message string
+	status  int
+}
+
+func (e *requestError) Error() string {
+	if e.message == "" {
+		return http.StatusText(e.status)
+	}
+
+	return e.message
+}
+
+// writeRequestError writes the response for err and returns true if there was
+// one, using the status of a requestError and internal server error otherwise.
+func writeRequestError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var refused *requestError
+	if !errors.As(err, &refused) {
+		w.WriteHeader(http.StatusInternalServerError)
+		return true
+	}
+
+	w.WriteHeader(refused.status)
+	fmt.Fprint(w, refused.message)
+	return true
+}
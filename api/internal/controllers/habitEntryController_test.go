@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHabitEntryController returns a controller on a new database with a
+// user and one of their habits.
+func newTestHabitEntryController(t *testing.T) (*HabitEntryController, models.User, repository.Habit) {
+	db, err := storage.NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyMigrations())
+
+	ctx := context.Background()
+	user, err := db.Queries.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{
+		UserID:       user.ID,
+		Name:         "Run",
+		Colour:       "red",
+		Active:       true,
+		ScheduleType: string(models.ScheduleDaily),
+	})
+	require.NoError(t, err)
+
+	controller := NewHabitEntryController(db, &logger.MockLogger{}, eventsService.NewHub(&logger.MockLogger{}))
+	return controller, models.NewUserFromStorage(user), habit
+}
+
+// postHabitEntry creates an entry as user, returning the response.
+func postHabitEntry(controller *HabitEntryController, user models.User, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/api/habitEntries", strings.NewReader(body))
+	request = request.WithContext(middleware.WithUser(request.Context(), user))
+	recorder := httptest.NewRecorder()
+	controller.CreateHabitEntry(recorder, request)
+
+	return recorder
+}
+
+func TestCreateHabitEntryDay(t *testing.T) {
+	tests := []struct {
+		name         string
+		date         string
+		settings     models.UserSettings
+		expectedDate string
+	}{
+		{
+			name:         "takes a date as it is",
+			date:         "2024-11-01",
+			settings:     models.NewUserSettings("Asia/Tokyo", 4),
+			expectedDate: "2024-11-01",
+		},
+		{
+			name:         "takes the day of a time in the user's timezone",
+			date:         "2024-10-31T15:00:00Z",
+			settings:     models.NewUserSettings("Asia/Tokyo", 0),
+			expectedDate: "2024-11-01",
+		},
+		{
+			name:         "counts a time before the user's day starts as the day before",
+			date:         "2024-10-31T18:30:00Z",
+			settings:     models.NewUserSettings("Asia/Tokyo", 4),
+			expectedDate: "2024-10-31",
+		},
+		{
+			name:         "counts a time after the user's day starts as that day",
+			date:         "2024-11-01T10:00:00+09:00",
+			settings:     models.NewUserSettings("Asia/Tokyo", 4),
+			expectedDate: "2024-11-01",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, user, habit := newTestHabitEntryController(t)
+			user.Settings = tc.settings
+			body, err := json.Marshal(map[string]any{"habitId": habit.ID, "date": tc.date})
+			require.NoError(t, err)
+
+			// Act
+			response := postHabitEntry(controller, user, string(body))
+
+			// Assert
+			require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+			var entry storage.HabitEntry
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&entry))
+			assert.Equal(t, tc.expectedDate, entry.Date.Format(time.DateOnly))
+		})
+	}
+}
+
+func TestCreateHabitEntryConflict(t *testing.T) {
+	tests := []struct {
+		request      map[string]any
+		name         string
+		expectedCode int
+	}{
+		{
+			name:         "refuses a day that already has an entry",
+			request:      map[string]any{"date": "2024-11-01", "status": "skipped"},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "increments a day that already has an entry",
+			request:      map[string]any{"date": "2024-11-01", "increment": true},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "creates an entry for another day",
+			request:      map[string]any{"date": "2024-11-02"},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, user, habit := newTestHabitEntryController(t)
+			existing, err := json.Marshal(map[string]any{"habitId": habit.ID, "date": "2024-11-01"})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, postHabitEntry(controller, user, string(existing)).Code)
+			tc.request["habitId"] = habit.ID
+			body, err := json.Marshal(tc.request)
+			require.NoError(t, err)
+
+			// Act
+			response := postHabitEntry(controller, user, string(body))
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, response.Code, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/journalService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type JournalStore interface {
+	GetJournal(userId int64, dateRange models.DateRange) ([]journalService.JournalEntry, error)
+	SaveJournalEntry(ctx context.Context, userId int64, date time.Time, text string) (journalService.JournalEntry, error)
+	DeleteJournalEntry(ctx context.Context, userId int64, date time.Time) error
+	Search(userId int64, query string, limit int64) (journalService.SearchResults, error)
+}
+
+type journalEntryRequest struct {
+	Text string `json:"text"`
+}
+
+type JournalController struct {
+	journalStore JournalStore
+	logger       logger.Logger
+}
+
+func NewJournalController(logger logger.Logger, journalStore JournalStore) *JournalController {
+	return &JournalController{
+		logger:       logger,
+		journalStore: journalStore,
+	}
+}
+
+func (j *JournalController) GetJournal(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, j.logger)
+	if !ok {
+		return
+	}
+
+	dateRange, ok := parseDateRange(w, r, j.logger)
+	if !ok {
+		return
+	}
+
+	entries, err := j.journalStore.GetJournal(userId, dateRange)
+	if err != nil {
+		j.logger.Error("Failed to get journal", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, entries)
+}
+
+// SaveJournalEntry creates or replaces the journal entry for the date in the path.
+func (j *JournalController) SaveJournalEntry(w http.ResponseWriter, r *http.Request) {
+	userId, date, ok := j.parseJournalPath(w, r)
+	if !ok {
+		return
+	}
+
+	var request journalEntryRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		j.logger.Error("Failed to decode journal entry", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Failed to decode journal entry: %v", err)
+		return
+	}
+
+	entry, err := j.journalStore.SaveJournalEntry(r.Context(), userId, date, request.Text)
+	if errors.Is(err, journalService.ErrTextRequired) || errors.Is(err, journalService.ErrTextTooLong) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		j.logger.Error("Failed to save journal entry", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, entry)
+}
+
+func (j *JournalController) DeleteJournalEntry(w http.ResponseWriter, r *http.Request) {
+	userId, date, ok := j.parseJournalPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := j.journalStore.DeleteJournalEntry(r.Context(), userId, date)
+	if errors.Is(err, journalService.ErrJournalEntryNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		j.logger.Error("Failed to delete journal entry", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Search returns the user's habit entry notes and journal entries matching
+// the q query parameter, most recent first.
+func (j *JournalController) Search(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, j.logger)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := int64(defaultSearchLimit)
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "limit must be between 1 and %d", maxSearchLimit)
+			return
+		}
+	}
+
+	results, err := j.journalStore.Search(userId, query.Get("q"), limit)
+	if errors.Is(err, journalService.ErrQueryRequired) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+	if err != nil {
+		j.logger.Error("Failed to search", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, results)
+}
+
+func (j *JournalController) parseJournalPath(w http.ResponseWriter, r *http.Request) (int64, time.Time, bool) {
+	userId, ok := authorizeUserPath(w, r, j.logger)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+
+	date, err := time.Parse(time.DateOnly, r.PathValue("date"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "Invalid date, expected YYYY-MM-DD")
+		return 0, time.Time{}, false
+	}
+
+	return userId, date, true
+}
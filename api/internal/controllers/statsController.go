@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/services/statsService"
+)
+
+type StatsStore interface {
+	GetHabitStats(habitId int64, settings models.UserSettings) (statsService.HabitStats, error)
+	GetUserStats(userId int64, settings models.UserSettings) (statsService.UserStats, error)
+	GetTagStats(userId int64, settings models.UserSettings) ([]statsService.TagStats, error)
+}
+
+type StatsController struct {
+	statsStore  StatsStore
+	habitsStore HabitOwnerStore
+	logger      logger.Logger
+}
+
+func NewStatsController(logger logger.Logger, statsStore StatsStore, habitsStore HabitOwnerStore) *StatsController {
+	return &StatsController{
+		logger:      logger,
+		statsStore:  statsStore,
+		habitsStore: habitsStore,
+	}
+}
+
+func (s *StatsController) GetHabitStats(w http.ResponseWriter, r *http.Request) {
+	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
+	if err != nil {
+		s.logger.Error("Failed to parse habitId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !authorizeHabit(w, s.logger, s.habitsStore, currentUserId(r), habitId) {
+		return
+	}
+
+	stats, err := s.statsStore.GetHabitStats(habitId, currentUserSettings(r))
+	if err != nil {
+		s.logger.Error("Failed to get habit stats", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, stats)
+}
+
+func (s *StatsController) GetUserStats(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, s.logger)
+	if !ok {
+		return
+	}
+
+	stats, err := s.statsStore.GetUserStats(userId, currentUserSettings(r))
+	if err != nil {
+		s.logger.Error("Failed to get user stats", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, stats)
+}
+
+// GetTagStats returns the combined statistics of the habits with each of the
+// user's tags.
+func (s *StatsController) GetTagStats(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, s.logger)
+	if !ok {
+		return
+	}
+
+	stats, err := s.statsStore.GetTagStats(userId, currentUserSettings(r))
+	if err != nil {
+		s.logger.Error("Failed to get tag stats", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, stats)
+}
@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/coder/websocket"
+)
+
+// Commands sync clients can send, and the types of the replies to them.
+const (
+	syncCheckEntry    = "entry.check"
+	syncUncheckEntry  = "entry.uncheck"
+	syncUpdateHabits  = "habits.update"
+	syncReorderHabits = "habits.reorder"
+	syncResultType    = "result"
+	syncErrorType     = "error"
+)
+
+const (
+	maxSyncMessageSize = 64 << 10
+	syncWriteTimeout   = 10 * time.Second
+)
+
+// syncCommand is a change sent by a sync client. The client chooses the ID,
+// which is repeated on the reply.
+type syncCommand struct {
+	Data json.RawMessage `json:"data"`
+	Id   string          `json:"id"`
+	Type string          `json:"type"`
+}
+
+type syncUncheckRequest struct {
+	EntryId int64 `json:"entryId"`
+}
+
+// syncMessage is sent to sync clients, either as the reply to a command or as
+// an event for a change from any of the user's devices.
+type syncMessage struct {
+	Data    any        `json:"data,omitempty"`
+	Error   *syncError `json:"error,omitempty"`
+	Id      string     `json:"id,omitempty"`
+	Type    string     `json:"type"`
+	EventId int64      `json:"eventId,omitempty"`
+}
+
+type syncError struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+type SyncController struct {
+	habitsStore   HabitStore
+	entries       *HabitEntryController
+	events        EventSubscriber
+	acceptOptions *websocket.AcceptOptions
+	logger        logger.Logger
+}
+
+// NewSyncController returns a SyncController that accepts connections from
+// browsers on the server's own origin or one of allowedOrigins, and from
+// clients that do not send an origin.
+func NewSyncController(logger logger.Logger, habitStore HabitStore, entries *HabitEntryController, events EventSubscriber, allowedOrigins []string) *SyncController {
+	// Patterns with a scheme are matched against the whole origin. A wildcard
+	// is left out, sync connections are authenticated by the session cookie
+	// so allowing every origin would let any site use it.
+	originPatterns := make([]string, 0, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin != "*" {
+			originPatterns = append(originPatterns, strings.TrimSuffix(origin, "/"))
+		}
+	}
+
+	return &SyncController{
+		logger:        logger,
+		habitsStore:   habitStore,
+		entries:       entries,
+		events:        events,
+		acceptOptions: &websocket.AcceptOptions{OriginPatterns: originPatterns},
+	}
+}
+
+// Sync upgrades to a WebSocket that takes check, uncheck, edit and reorder
+// commands, replying to each in order, and sends the same events as Stream for
+// the changes made from any of the user's devices, including this one. Edits
+// must give each habit's version and are refused with a conflict if the habit
+// has changed since. A lastEventId query parameter resumes like the
+// Last-Event-ID header does for Stream.
+func (c *SyncController) Sync(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	var lastEventId int64
+	if value := r.URL.Query().Get("lastEventId"); value != "" {
+		var err error
+		lastEventId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "lastEventId must be an event ID")
+			return
+		}
+	}
+
+	conn, err := websocket.Accept(w, r, c.acceptOptions)
+	if err != nil {
+		c.logger.Warn("Failed to open sync connection", slog.Int64("userId", userId), slog.Any("error", err))
+		return
+	}
+	conn.SetReadLimit(maxSyncMessageSize)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	subscription, missed := c.events.Subscribe(userId, lastEventId)
+	go c.sendEvents(ctx, conn, subscription, missed)
+	defer subscription.Close()
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	c.logger.Debug("Sync connection opened", slog.Int64("userId", userId), slog.Int("missed", len(missed)))
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			c.logger.Debug("Sync connection closed", slog.Int64("userId", userId), slog.Any("error", err))
+			return
+		}
+
+		if err := c.write(ctx, conn, c.run(ctx, userId, message)); err != nil {
+			return
+		}
+	}
+}
+
+// sendEvents writes the missed events followed by each new one, pinging the
+// client while there are none. The connection is closed when the subscription
+// ends or a ping goes unanswered, so clients reconnect and resume from their
+// last event.
+func (c *SyncController) sendEvents(ctx context.Context, conn *websocket.Conn, subscription *eventsService.Subscription, missed []eventsService.Event) {
+	defer conn.Close(websocket.StatusGoingAway, "")
+
+	for _, event := range missed {
+		if err := c.write(ctx, conn, newSyncEvent(event)); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			if err := c.write(ctx, conn, newSyncEvent(event)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := c.ping(ctx, conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newSyncEvent(event eventsService.Event) syncMessage {
+	return syncMessage{Type: string(event.Type), Data: event.Data, EventId: event.Id}
+}
+
+func (c *SyncController) write(ctx context.Context, conn *websocket.Conn, message syncMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		c.logger.Error("Failed to encode sync message", slog.Any("error", err))
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, syncWriteTimeout)
+	defer cancel()
+	return conn.Write(ctx, websocket.MessageText, data)
+}
+
+// ping waits for the client to answer a ping, giving up after a heartbeat.
+func (c *SyncController) ping(ctx context.Context, conn *websocket.Conn) error {
+	ctx, cancel := context.WithTimeout(ctx, heartbeatInterval)
+	defer cancel()
+	return conn.Ping(ctx)
+}
+
+// run carries out a command, returning the reply to send.
+func (c *SyncController) run(ctx context.Context, userId int64, message []byte) syncMessage {
+	var command syncCommand
+	if err := json.Unmarshal(message, &command); err != nil {
+		return c.failure("", &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Failed to decode command: %v", err)})
+	}
+
+	result, err := c.runCommand(ctx, userId, command)
+	if err != nil {
+		return c.failure(command.Id, err)
+	}
+
+	return syncMessage{Id: command.Id, Type: syncResultType, Data: result}
+}
+
+func (c *SyncController) runCommand(ctx context.Context, userId int64, command syncCommand) (any, error) {
+	switch command.Type {
+	case syncCheckEntry:
+		var request habitEntryRequest
+		if err := decodeCommand(command, &request); err != nil {
+			return nil, err
+		}
+		return c.entries.checkHabit(ctx, userId, request)
+	case syncUncheckEntry:
+		var request syncUncheckRequest
+		if err := decodeCommand(command, &request); err != nil {
+			return nil, err
+		}
+		return c.entries.uncheckHabit(ctx, userId, request.EntryId)
+	case syncUpdateHabits:
+		var habits []habitsService.Habit
+		if err := decodeCommand(command, &habits); err != nil {
+			return nil, err
+		}
+		if err := validateSyncedHabits(habits); err != nil {
+			return nil, err
+		}
+		return c.habitsStore.UpdateHabits(ctx, userId, habits)
+	case syncReorderHabits:
+		var request reorderHabitsRequest
+		if err := decodeCommand(command, &request); err != nil {
+			return nil, err
+		}
+		return c.habitsStore.ReorderHabits(ctx, userId, request.HabitIds)
+	default:
+		return nil, &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Unknown command type %q", command.Type)}
+	}
+}
+
+func decodeCommand(command syncCommand, data any) error {
+	if err := json.Unmarshal(command.Data, data); err != nil {
+		return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Failed to decode %s: %v", command.Type, err)}
+	}
+
+	return nil
+}
+
+// validateSyncedHabits checks any schedule and target of the edited habits are
+// valid.
+func validateSyncedHabits(habits []habitsService.Habit) error {
+	for _, habit := range habits {
+		if !habit.Schedule.IsZero() {
+			if err := habit.Schedule.Validate(); err != nil {
+				return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Invalid schedule: %v", err)}
+			}
+		}
+		if !habit.Target.IsZero() {
+			if err := habit.Target.Validate(); err != nil {
+				return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf("Invalid target: %v", err)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// failure returns the error reply for a command, with the status the same
+// change gets from the HTTP API.
+func (c *SyncController) failure(id string, err error) syncMessage {
+	var refused *requestError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &refused):
+		status = refused.status
+	case errors.Is(err, habitsService.ErrHabitConflict):
+		status = http.StatusConflict
+	case errors.Is(err, habitsService.ErrVersionRequired):
+		status = http.StatusPreconditionRequired
+	case errors.Is(err, habitsService.ErrHabitNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, habitsService.ErrInvalidOrder), errors.Is(err, habitsService.ErrCategoryNotFound),
+		errors.Is(err, habitsService.ErrDescriptionTooLong), errors.Is(err, habitsService.ErrIconTooLong):
+		status = http.StatusBadRequest
+	}
+
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		c.logger.Error("Failed to run sync command", slog.String("id", id), slog.Any("error", err))
+		message = http.StatusText(status)
+	}
+
+	return syncMessage{Id: id, Type: syncErrorType, Error: &syncError{Status: status, Message: message}}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/services/tagsService"
+)
+
+type TagStore interface {
+	GetTags(userId int64) ([]models.Tag, error)
+	CreateTag(ctx context.Context, userId int64, name string) (models.Tag, error)
+	UpdateTag(ctx context.Context, userId int64, tagId int64, name string) (models.Tag, error)
+	DeleteTag(ctx context.Context, userId int64, tagId int64) error
+	AttachTag(ctx context.Context, userId int64, habitId int64, tagId int64) ([]models.Tag, error)
+	DetachTag(ctx context.Context, userId int64, habitId int64, tagId int64) ([]models.Tag, error)
+}
+
+type tagRequest struct {
+	Name string `json:"name"`
+}
+
+type TagController struct {
+	tagStore TagStore
+	logger   logger.Logger
+}
+
+func NewTagController(logger logger.Logger, tagStore TagStore) *TagController {
+	return &TagController{
+		logger:   logger,
+		tagStore: tagStore,
+	}
+}
+
+func (c *TagController) GetTags(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	tags, err := c.tagStore.GetTags(userId)
+	if err != nil {
+		c.logger.Error("Failed to get tags", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, tags)
+}
+
+func (c *TagController) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	request, ok := c.decodeTag(w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := c.tagStore.CreateTag(r.Context(), userId, request.Name)
+	if c.writeTagError(w, err) {
+		return
+	}
+
+	c.logger.Info("Created tag", slog.Int64("tagId", tag.Id))
+	successWithBody(w, tag)
+}
+
+func (c *TagController) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	tagId, ok := c.parseTagId(w, r)
+	if !ok {
+		return
+	}
+
+	request, ok := c.decodeTag(w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := c.tagStore.UpdateTag(r.Context(), userId, tagId, request.Name)
+	if c.writeTagError(w, err) {
+		return
+	}
+
+	c.logger.Info("Updated tag", slog.Int64("tagId", tagId))
+	successWithBody(w, tag)
+}
+
+// DeleteTag deletes a tag, removing it from all of the user's habits.
+func (c *TagController) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	tagId, ok := c.parseTagId(w, r)
+	if !ok {
+		return
+	}
+
+	err := c.tagStore.DeleteTag(r.Context(), userId, tagId)
+	if c.writeTagError(w, err) {
+		return
+	}
+
+	c.logger.Info("Deleted tag", slog.Int64("tagId", tagId))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AttachTag tags a habit, responding with the habit's tags.
+func (c *TagController) AttachTag(w http.ResponseWriter, r *http.Request) {
+	c.setHabitTag(w, r, c.tagStore.AttachTag)
+}
+
+// DetachTag removes a tag from a habit, responding with the habit's tags.
+func (c *TagController) DetachTag(w http.ResponseWriter, r *http.Request) {
+	c.setHabitTag(w, r, c.tagStore.DetachTag)
+}
+
+func (c *TagController) setHabitTag(w http.ResponseWriter, r *http.Request, set func(ctx context.Context, userId int64, habitId int64, tagId int64) ([]models.Tag, error)) {
+	habitId, err := strconv.ParseInt(r.PathValue("habitId"), 10, 64)
+	if err != nil {
+		c.logger.Error("Failed to parse habitId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	tagId, ok := c.parseTagId(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := set(r.Context(), currentUserId(r), habitId, tagId)
+	if c.writeTagError(w, err) {
+		return
+	}
+
+	c.logger.Info("Set habit tags", slog.Int64("habitId", habitId), slog.Any("tags", tags))
+	successWithBody(w, tags)
+}
+
+func (c *TagController) decodeTag(w http.ResponseWriter, r *http.Request) (tagRequest, bool) {
+	var request tagRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		c.logger.Error("Failed to decode tag", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return tagRequest{}, false
+	}
+
+	return request, true
+}
+
+// writeTagError writes the response for an error from the tag store, returning
+// false if there was no error.
+func (c *TagController) writeTagError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, tagsService.ErrTagNotFound), errors.Is(err, tagsService.ErrHabitNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, err)
+	case errors.Is(err, tagsService.ErrTagExists):
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err)
+	case errors.Is(err, tagsService.ErrNameRequired), errors.Is(err, tagsService.ErrNameTooLong):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+	default:
+		c.logger.Error("Failed to save tag", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	return true
+}
+
+func (c *TagController) parseTagId(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	tagId, err := strconv.ParseInt(r.PathValue("tagId"), 10, 64)
+	if err != nil {
+		c.logger.Error("Failed to parse tagId", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
+
+	return tagId, true
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/trashService"
+)
+
+type TrashStore interface {
+	GetTrash(userId int64) (trashService.Trash, error)
+	Restore(userId int64, itemType string, id int64) error
+}
+
+type TrashController struct {
+	trashStore TrashStore
+	logger     logger.Logger
+}
+
+func NewTrashController(logger logger.Logger, trashStore TrashStore) *TrashController {
+	return &TrashController{
+		logger:     logger,
+		trashStore: trashStore,
+	}
+}
+
+func (t *TrashController) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authorizeUserPath(w, r, t.logger)
+	if !ok {
+		return
+	}
+
+	trash, err := t.trashStore.GetTrash(userId)
+	if err != nil {
+		t.logger.Error("Failed to get trash", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	successWithBody(w, trash)
+}
+
+// Restore takes one of the signed in user's habits or entries out of the trash.
+func (t *TrashController) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		t.logger.Error("Failed to parse id", slog.Any("error", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userId := currentUserId(r)
+	itemType := r.PathValue("type")
+	err = t.trashStore.Restore(userId, itemType, id)
+	switch {
+	case errors.Is(err, trashService.ErrInvalidType):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	case errors.Is(err, trashService.ErrNotInTrash):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, err)
+		return
+	case err != nil:
+		t.logger.Error("Failed to restore from trash", slog.Any("error", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	t.logger.Info("Restored from trash", slog.Int64("userId", userId), slog.String("type", itemType), slog.Int64("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
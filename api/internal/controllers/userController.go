@@ -1,44 +1,22 @@
 package controllers
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
 	"github.com/ReidMason/habit-tracker/internal/storage"
 )
 
-func AddUserRoutes(mux *http.ServeMux, db *storage.Sqlite, logger logger.Logger) {
-	mux.HandleFunc("GET /api/user", func(w http.ResponseWriter, r *http.Request) {
-		users, err := db.GetUsers()
-		if err != nil {
-			logger.Error("Failed to get users", slog.Any("error", err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+// AddUserRoutes registers the user routes. Users are created through
+// /api/auth/register, and each user can only see themselves.
+func AddUserRoutes(mux *http.ServeMux, db *storage.Database, logger logger.Logger, requireRead middleware.Middleware) {
+	mux.Handle("GET /api/user", requireRead(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := middleware.CurrentUser(r.Context())
+		users := []storage.User{{Id: user.Id, Name: user.Name}}
 
 		logger.Debug("Got users", slog.Any("users", users))
 		successWithBody(w, users)
-	})
-
-	mux.HandleFunc("POST /api/user", func(w http.ResponseWriter, r *http.Request) {
-		var user storage.User
-		err := json.NewDecoder(r.Body).Decode(&user)
-		if err != nil {
-			logger.Error("Failed to decode user", slog.Any("error", err))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
-		createdUser, err := db.CreateUser(user.Name)
-		if err != nil {
-			logger.Error("Failed to create user", slog.Any("error", err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		logger.Info("Created user", slog.Any("user", createdUser))
-		successWithBody(w, createdUser)
-	})
+	}))
 }
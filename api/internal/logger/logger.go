@@ -1,6 +1,11 @@
 package logger
 
-import "github.com/charmbracelet/log"
+import (
+	"io"
+	"log/slog"
+
+	"github.com/charmbracelet/log"
+)
 
 type Logger interface {
 	Debug(msg string, args ...any)
@@ -26,3 +31,20 @@ func (m MockLogger) Error(msg string, keysAndValues ...interface{}) {
 func (m MockLogger) Warn(msg string, keysAndValues ...interface{}) {
 	log.Warn(msg, keysAndValues...)
 }
+
+// New returns a logger that writes to w at level ("debug", "info", "warn" or
+// "error") as either text or JSON.
+func New(w io.Writer, level string, format string) (*slog.Logger, error) {
+	logLevel, err := log.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := log.New(w)
+	handler.SetLevel(logLevel)
+	if format == "json" {
+		handler.SetFormatter(log.JSONFormatter)
+	}
+
+	return slog.New(handler), nil
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/apiTokensService"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+type SessionAuthenticator interface {
+	Authenticate(token string) (models.User, error)
+}
+
+type TokenAuthenticator interface {
+	Authenticate(token string) (models.User, models.Scope, error)
+}
+
+// Middleware wraps a handler so it only runs for signed in users.
+type Middleware func(http.HandlerFunc) http.Handler
+
+type Auth struct {
+	sessions SessionAuthenticator
+	tokens   TokenAuthenticator
+	logger   logger.Logger
+}
+
+// NewAuth returns the auth middleware. API tokens are rejected when tokens is nil.
+func NewAuth(sessions SessionAuthenticator, tokens TokenAuthenticator, logger logger.Logger) *Auth {
+	return &Auth{
+		sessions: sessions,
+		tokens:   tokens,
+		logger:   logger,
+	}
+}
+
+// Require resolves the current user from an "Authorization: Bearer" API token
+// or the session cookie, rejecting the request with 401 if there is neither
+// and 403 if the API token's scope does not cover the route. Sessions have
+// full access.
+func (a *Auth) Require(scope models.Scope) Middleware {
+	return func(next http.HandlerFunc) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, grantedScope, err := a.authenticate(r)
+			if errors.Is(err, authService.ErrInvalidSession) || errors.Is(err, apiTokensService.ErrInvalidToken) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				a.logger.Error("Failed to authenticate", slog.Any("error", err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			if !grantedScope.Allows(scope) {
+				a.logger.Warn("API token scope does not allow route", slog.Int64("userId", user.Id), slog.String("scope", string(grantedScope)))
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next(w, r.WithContext(WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// RequireAdmin is Require with full access for users that administer the
+// server, rejecting everyone else with 403.
+func (a *Auth) RequireAdmin() Middleware {
+	requireWrite := a.Require(models.ScopeReadWrite)
+	return func(next http.HandlerFunc) http.Handler {
+		return requireWrite(func(w http.ResponseWriter, r *http.Request) {
+			user, _ := CurrentUser(r.Context())
+			if !user.Admin {
+				a.logger.Warn("User is not an admin", slog.Int64("userId", user.Id))
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next(w, r)
+		})
+	}
+}
+
+// RequireSession is Require with full access for routes that must only be used
+// when signed in with a session, such as managing API tokens, so a leaked token
+// cannot be used to issue more. API tokens are rejected with 403.
+func (a *Auth) RequireSession() Middleware {
+	requireWrite := a.Require(models.ScopeReadWrite)
+	return func(next http.HandlerFunc) http.Handler {
+		return requireWrite(func(w http.ResponseWriter, r *http.Request) {
+			// API tokens take precedence over the session cookie, so a request
+			// with one was authenticated by it
+			if r.Header.Get("Authorization") != "" {
+				user, _ := CurrentUser(r.Context())
+				a.logger.Warn("API token used for a session only route", slog.Int64("userId", user.Id))
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next(w, r)
+		})
+	}
+}
+
+func (a *Auth) authenticate(r *http.Request) (models.User, models.Scope, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || a.tokens == nil {
+			return models.User{}, "", apiTokensService.ErrInvalidToken
+		}
+
+		return a.tokens.Authenticate(strings.TrimSpace(token))
+	}
+
+	cookie, err := r.Cookie(authService.SessionCookieName)
+	if err != nil {
+		return models.User{}, "", authService.ErrInvalidSession
+	}
+
+	user, err := a.sessions.Authenticate(cookie.Value)
+	return user, models.ScopeReadWrite, err
+}
+
+// WithUser returns a context with user signed in, so changes made with it are
+// audited as made by them.
+func WithUser(ctx context.Context, user models.User) context.Context {
+	ctx = auditService.WithActor(ctx, user.Id)
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// CurrentUser returns the signed in user set by Auth.Require.
+func CurrentUser(ctx context.Context) (models.User, bool) {
+	user, ok := ctx.Value(userContextKey).(models.User)
+	return user, ok
+}
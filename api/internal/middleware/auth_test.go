@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/apiTokensService"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSessions struct {
+	users map[string]models.User
+}
+
+func (m mockSessions) Authenticate(token string) (models.User, error) {
+	user, ok := m.users[token]
+	if !ok {
+		return models.User{}, authService.ErrInvalidSession
+	}
+
+	return user, nil
+}
+
+type mockTokens struct {
+	users  map[string]models.User
+	scopes map[string]models.Scope
+}
+
+func (m mockTokens) Authenticate(token string) (models.User, models.Scope, error) {
+	user, ok := m.users[token]
+	if !ok {
+		return models.User{}, "", apiTokensService.ErrInvalidToken
+	}
+
+	return user, m.scopes[token], nil
+}
+
+func newTestAuth() *Auth {
+	sessions := mockSessions{users: map[string]models.User{"session": models.NewUser(1, "alice")}}
+	tokens := mockTokens{
+		users:  map[string]models.User{"ht_read": models.NewUser(2, "bob"), "ht_write": models.NewUser(2, "bob")},
+		scopes: map[string]models.Scope{"ht_read": models.ScopeRead, "ht_write": models.ScopeReadWrite},
+	}
+
+	return NewAuth(sessions, tokens, &logger.MockLogger{})
+}
+
+// serve runs a request with the session cookie and bearer token, when given,
+// through middleware, returning the status and the user the handler saw.
+func serve(middleware Middleware, session string, token string) (int, int64) {
+	var userId int64
+	handler := middleware(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := CurrentUser(r.Context())
+		userId = user.Id
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/api", nil)
+	if session != "" {
+		request.AddCookie(&http.Cookie{Name: authService.SessionCookieName, Value: session})
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder.Code, userId
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name           string
+		scope          models.Scope
+		session        string
+		token          string
+		expectedStatus int
+		expectedUserId int64
+	}{
+		{
+			name:           "allows a session on a write route",
+			scope:          models.ScopeReadWrite,
+			session:        "session",
+			expectedStatus: http.StatusOK,
+			expectedUserId: 1,
+		},
+		{
+			name:           "allows a read API token on a read route",
+			scope:          models.ScopeRead,
+			token:          "ht_read",
+			expectedStatus: http.StatusOK,
+			expectedUserId: 2,
+		},
+		{
+			name:           "rejects a read API token on a write route",
+			scope:          models.ScopeReadWrite,
+			token:          "ht_read",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "allows a read write API token on a write route",
+			scope:          models.ScopeReadWrite,
+			token:          "ht_write",
+			expectedStatus: http.StatusOK,
+			expectedUserId: 2,
+		},
+		{
+			name:           "rejects a revoked API token",
+			scope:          models.ScopeRead,
+			token:          "ht_revoked",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "uses the API token over the session cookie",
+			scope:          models.ScopeRead,
+			session:        "session",
+			token:          "ht_read",
+			expectedStatus: http.StatusOK,
+			expectedUserId: 2,
+		},
+		{
+			name:           "uses the API token's scope over the session cookie",
+			scope:          models.ScopeReadWrite,
+			session:        "session",
+			token:          "ht_read",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "does not fall back to the session cookie for an invalid API token",
+			scope:          models.ScopeRead,
+			session:        "session",
+			token:          "ht_revoked",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects an expired session",
+			scope:          models.ScopeRead,
+			session:        "expired",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects a request that is not signed in",
+			scope:          models.ScopeRead,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			auth := newTestAuth()
+
+			// Act
+			status, userId := serve(auth.Require(tc.scope), tc.session, tc.token)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedUserId, userId)
+		})
+	}
+}
+
+func TestRequireWithApiTokensDisabled(t *testing.T) {
+	// Arrange
+	sessions := mockSessions{users: map[string]models.User{"session": models.NewUser(1, "alice")}}
+	auth := NewAuth(sessions, nil, &logger.MockLogger{})
+
+	// Act
+	tokenStatus, _ := serve(auth.Require(models.ScopeRead), "", "ht_read")
+	sessionStatus, userId := serve(auth.Require(models.ScopeRead), "session", "")
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, tokenStatus)
+	assert.Equal(t, http.StatusOK, sessionStatus)
+	assert.Equal(t, int64(1), userId)
+}
+
+func TestRequireSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		session        string
+		token          string
+		expectedStatus int
+		expectedUserId int64
+	}{
+		{
+			name:           "allows a session",
+			session:        "session",
+			expectedStatus: http.StatusOK,
+			expectedUserId: 1,
+		},
+		{
+			name:           "rejects a read write API token",
+			token:          "ht_write",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "rejects an API token sent with a session",
+			session:        "session",
+			token:          "ht_write",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "rejects a request that is not signed in",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			auth := newTestAuth()
+
+			// Act
+			status, userId := serve(auth.RequireSession(), tc.session, tc.token)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedUserId, userId)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+)
+
+const RequestIdHeader = "X-Request-Id"
+
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestId gives every request an ID, recorded with any changes it makes and
+// returned in the X-Request-Id header. A valid ID sent by the client is kept
+// so requests can be traced from a proxy.
+func RequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = newRequestId()
+		}
+		if requestId == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(RequestIdHeader, requestId)
+		next.ServeHTTP(w, r.WithContext(auditService.WithRequestId(r.Context(), requestId)))
+	})
+}
+
+// newRequestId returns a random request ID, or "" if one could not be generated.
+func newRequestId() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
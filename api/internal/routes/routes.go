@@ -3,40 +3,183 @@ package routes
 import (
 	"net/http"
 
+	"github.com/ReidMason/habit-tracker/internal/config"
 	"github.com/ReidMason/habit-tracker/internal/controllers"
 	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
+	"github.com/ReidMason/habit-tracker/internal/services/apiTokensService"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/backupService"
+	"github.com/ReidMason/habit-tracker/internal/services/categoriesService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/exportService"
 	"github.com/ReidMason/habit-tracker/internal/services/habitEntriesService"
 	habitService "github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/journalService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/services/statsService"
+	"github.com/ReidMason/habit-tracker/internal/services/tagsService"
+	"github.com/ReidMason/habit-tracker/internal/services/trashService"
 	"github.com/ReidMason/habit-tracker/internal/storage"
 )
 
-func Setup(db *storage.Sqlite, logger logger.Logger) *http.ServeMux {
+func Setup(db *storage.Database, logger logger.Logger, cfg *config.Config, backupStore *backupService.BackupService, eventHub *eventsService.Hub) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	mux.Handle("/", http.FileServer(http.Dir("./static")))
+	mux.Handle("/", http.FileServer(http.Dir(cfg.StaticDir)))
 
+	authStore := authService.NewAuthService(db.Queries, logger)
+	apiTokenStore := apiTokensService.NewApiTokenService(db.Queries, logger)
 	habitEntryStore := habitEntriesService.NewHabitEntriesService(db.Queries, logger)
-	habitStore := habitService.NewHabitService(db.Queries, logger, habitEntryStore)
+	habitStore := habitService.NewHabitService(db.Queries, db, logger, habitEntryStore, eventHub)
+	statsStore := statsService.NewStatsService(db.Queries, logger, habitEntryStore)
+	exportStore := exportService.NewExportService(db.Queries, db, logger, habitEntryStore, eventHub)
+	trashStore := trashService.NewTrashService(db.Queries, db, logger, eventHub, cfg.Trash.Retention)
+	auditStore := auditService.NewAuditService(db.Queries, logger)
+	journalStore := journalService.NewJournalService(db.Queries, db, db, logger)
+	categoryStore := categoriesService.NewCategoryService(db.Queries, db, logger, eventHub)
+	tagStore := tagsService.NewTagService(db.Queries, db, logger, eventHub)
 
+	var tokenAuthenticator middleware.TokenAuthenticator
+	if cfg.Features.ApiTokens {
+		tokenAuthenticator = apiTokenStore
+	}
+	auth := middleware.NewAuth(authStore, tokenAuthenticator, logger)
+	requireRead := auth.Require(models.ScopeRead)
+	requireWrite := auth.Require(models.ScopeReadWrite)
+	requireAdmin := auth.RequireAdmin()
+	requireSession := auth.RequireSession()
+
+	authController := controllers.NewAuthController(logger, authStore)
+	apiTokenController := controllers.NewApiTokenController(logger, apiTokenStore)
 	habitController := controllers.NewHabitController(logger, habitStore)
-	habitEntryController := controllers.NewHabitEntryController(db, logger)
+	habitEntryController := controllers.NewHabitEntryController(db, logger, eventHub)
+	statsController := controllers.NewStatsController(logger, statsStore, habitStore)
+	exportController := controllers.NewExportController(logger, exportStore)
+	trashController := controllers.NewTrashController(logger, trashStore)
+	auditController := controllers.NewAuditController(logger, auditStore)
+	journalController := controllers.NewJournalController(logger, journalStore)
+	categoryController := controllers.NewCategoryController(logger, categoryStore)
+	tagController := controllers.NewTagController(logger, tagStore)
+	eventController := controllers.NewEventController(logger, eventHub)
+	syncController := controllers.NewSyncController(logger, habitStore, habitEntryController, eventHub, cfg.AllowedOrigins)
 
-	setupHabitRoutes(mux, habitController)
-	setupHabitEntryRoutes(mux, habitEntryController)
-	controllers.AddUserRoutes(mux, db, logger)
+	setupAuthRoutes(mux, authController, requireRead, requireWrite, cfg.Features)
+	if cfg.Features.ApiTokens {
+		setupApiTokenRoutes(mux, apiTokenController, requireRead, requireSession)
+	}
+	setupHabitRoutes(mux, habitController, requireRead, requireWrite)
+	setupHabitEntryRoutes(mux, habitEntryController, requireWrite)
+	setupStatsRoutes(mux, statsController, requireRead)
+	setupExportRoutes(mux, exportController, requireRead, requireWrite, cfg.Features)
+	setupTrashRoutes(mux, trashController, requireRead, requireWrite)
+	setupAuditRoutes(mux, auditController, requireRead)
+	setupJournalRoutes(mux, journalController, requireRead, requireWrite)
+	setupCategoryRoutes(mux, categoryController, requireRead, requireWrite)
+	setupTagRoutes(mux, tagController, requireRead, requireWrite)
+	setupEventRoutes(mux, eventController, syncController, requireRead, requireWrite)
+	if backupStore != nil {
+		setupBackupRoutes(mux, controllers.NewBackupController(logger, backupStore), requireAdmin)
+	}
+	controllers.AddUserRoutes(mux, db, logger, requireRead)
 
 	return mux
 }
 
-func setupHabitRoutes(mux *http.ServeMux, habitController *controllers.HabitController) {
-	mux.HandleFunc("GET /api/users/{userId}/habits", habitController.GetHabits)
-	mux.HandleFunc("POST /api/users/{userId}/habits", habitController.CreateHabit)
-	mux.HandleFunc("PUT /api/users/{userId}/habits", habitController.EditHabits)
-	mux.HandleFunc("DELETE /api/habits/{habitId}", habitController.DeleteHabit)
-	mux.HandleFunc("PUT /api/habits/{habitId}", habitController.EditHabit)
+func setupAuthRoutes(mux *http.ServeMux, authController *controllers.AuthController, requireRead, requireWrite middleware.Middleware, features config.Features) {
+	if features.Registration {
+		mux.HandleFunc("POST /api/auth/register", authController.Register)
+	}
+	mux.HandleFunc("POST /api/auth/login", authController.Login)
+	mux.HandleFunc("POST /api/auth/logout", authController.Logout)
+	mux.Handle("GET /api/auth/me", requireRead(authController.Me))
+	mux.Handle("PUT /api/users/{userId}/settings", requireWrite(authController.UpdateSettings))
+}
+
+func setupApiTokenRoutes(mux *http.ServeMux, apiTokenController *controllers.ApiTokenController, requireRead, requireSession middleware.Middleware) {
+	mux.Handle("GET /api/tokens", requireRead(apiTokenController.GetTokens))
+	mux.Handle("POST /api/tokens", requireSession(apiTokenController.CreateToken))
+	mux.Handle("DELETE /api/tokens/{tokenId}", requireSession(apiTokenController.RevokeToken))
+}
+
+func setupHabitRoutes(mux *http.ServeMux, habitController *controllers.HabitController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/habits", requireRead(habitController.GetHabits))
+	mux.Handle("POST /api/users/{userId}/habits", requireWrite(habitController.CreateHabit))
+	mux.Handle("PUT /api/users/{userId}/habits", requireWrite(habitController.EditHabits))
+	mux.Handle("POST /api/users/{userId}/habits/reorder", requireWrite(habitController.ReorderHabits))
+	mux.Handle("GET /api/habits/{habitId}/entries", requireRead(habitController.GetHabitEntries))
+	mux.Handle("DELETE /api/habits/{habitId}", requireWrite(habitController.ArchiveHabit))
+	mux.Handle("POST /api/habits/{habitId}/archive", requireWrite(habitController.ArchiveHabit))
+	mux.Handle("POST /api/habits/{habitId}/unarchive", requireWrite(habitController.UnarchiveHabit))
+	mux.Handle("POST /api/habits/{habitId}/purge", requireWrite(habitController.PurgeHabit))
+	mux.Handle("PUT /api/habits/{habitId}", requireWrite(habitController.EditHabit))
+}
+
+func setupHabitEntryRoutes(mux *http.ServeMux, habitEntryController *controllers.HabitEntryController, requireWrite middleware.Middleware) {
+	mux.Handle("POST /api/habitEntries", requireWrite(habitEntryController.CreateHabitEntry))
+	mux.Handle("DELETE /api/habitEntries/{entryId}", requireWrite(habitEntryController.DeleteHabitEntry))
+	mux.Handle("PUT /api/habitEntries/{entryId}/note", requireWrite(habitEntryController.SetNote))
+	mux.Handle("PUT /api/habitEntries/{entryId}/status", requireWrite(habitEntryController.SetStatus))
+}
+
+func setupStatsRoutes(mux *http.ServeMux, statsController *controllers.StatsController, requireRead middleware.Middleware) {
+	mux.Handle("GET /api/habits/{habitId}/stats", requireRead(statsController.GetHabitStats))
+	mux.Handle("GET /api/users/{userId}/stats", requireRead(statsController.GetUserStats))
+	mux.Handle("GET /api/users/{userId}/stats/tags", requireRead(statsController.GetTagStats))
+}
+
+func setupExportRoutes(mux *http.ServeMux, exportController *controllers.ExportController, requireRead, requireWrite middleware.Middleware, features config.Features) {
+	mux.Handle("GET /api/users/{userId}/export", requireRead(exportController.Export))
+	mux.Handle("GET /api/users/{userId}/export/csv", requireRead(exportController.ExportCSV))
+	if !features.Imports {
+		return
+	}
+
+	mux.Handle("POST /api/users/{userId}/import", requireWrite(exportController.Import))
+	mux.Handle("POST /api/users/{userId}/import/csv", requireWrite(exportController.ImportCSV))
+	mux.Handle("POST /api/users/{userId}/import/loop", requireWrite(exportController.ImportLoop))
+}
+
+func setupTrashRoutes(mux *http.ServeMux, trashController *controllers.TrashController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/trash", requireRead(trashController.GetTrash))
+	mux.Handle("POST /api/trash/{type}/{id}/restore", requireWrite(trashController.Restore))
+}
+
+func setupAuditRoutes(mux *http.ServeMux, auditController *controllers.AuditController, requireRead middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/audit", requireRead(auditController.GetEvents))
+}
+
+func setupJournalRoutes(mux *http.ServeMux, journalController *controllers.JournalController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/journal", requireRead(journalController.GetJournal))
+	mux.Handle("PUT /api/users/{userId}/journal/{date}", requireWrite(journalController.SaveJournalEntry))
+	mux.Handle("DELETE /api/users/{userId}/journal/{date}", requireWrite(journalController.DeleteJournalEntry))
+	mux.Handle("GET /api/users/{userId}/search", requireRead(journalController.Search))
+}
+
+func setupCategoryRoutes(mux *http.ServeMux, categoryController *controllers.CategoryController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/categories", requireRead(categoryController.GetCategories))
+	mux.Handle("POST /api/users/{userId}/categories", requireWrite(categoryController.CreateCategory))
+	mux.Handle("PUT /api/users/{userId}/categories/{categoryId}", requireWrite(categoryController.UpdateCategory))
+	mux.Handle("DELETE /api/users/{userId}/categories/{categoryId}", requireWrite(categoryController.DeleteCategory))
+}
+
+func setupTagRoutes(mux *http.ServeMux, tagController *controllers.TagController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/tags", requireRead(tagController.GetTags))
+	mux.Handle("POST /api/users/{userId}/tags", requireWrite(tagController.CreateTag))
+	mux.Handle("PUT /api/users/{userId}/tags/{tagId}", requireWrite(tagController.UpdateTag))
+	mux.Handle("DELETE /api/users/{userId}/tags/{tagId}", requireWrite(tagController.DeleteTag))
+	mux.Handle("PUT /api/habits/{habitId}/tags/{tagId}", requireWrite(tagController.AttachTag))
+	mux.Handle("DELETE /api/habits/{habitId}/tags/{tagId}", requireWrite(tagController.DetachTag))
+}
+
+func setupBackupRoutes(mux *http.ServeMux, backupController *controllers.BackupController, requireAdmin middleware.Middleware) {
+	mux.Handle("POST /api/admin/backups", requireAdmin(backupController.CreateBackup))
+	mux.Handle("GET /api/admin/backups", requireAdmin(backupController.GetBackups))
+	mux.Handle("GET /api/admin/backups/{name}", requireAdmin(backupController.DownloadBackup))
 }
 
-func setupHabitEntryRoutes(mux *http.ServeMux, habitEntryController *controllers.HabitEntryController) {
-	mux.HandleFunc("POST /api/habitEntries", habitEntryController.CreateHabitEntry)
-	mux.HandleFunc("DELETE /api/habitEntries/{entryId}", habitEntryController.DeleteHabitEntry)
+func setupEventRoutes(mux *http.ServeMux, eventController *controllers.EventController, syncController *controllers.SyncController, requireRead, requireWrite middleware.Middleware) {
+	mux.Handle("GET /api/users/{userId}/events", requireRead(eventController.Stream))
+	mux.Handle("GET /api/users/{userId}/sync", requireWrite(syncController.Sync))
 }
@@ -4,23 +4,32 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/ReidMason/habit-tracker/internal/config"
 	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/middleware"
 	"github.com/ReidMason/habit-tracker/internal/routes"
+	"github.com/ReidMason/habit-tracker/internal/services/backupService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/trashService"
 	"github.com/ReidMason/habit-tracker/internal/storage"
 	"github.com/rs/cors"
 )
 
+// trashPurgeInterval is how often the trash is checked for habits and entries
+// that have been there longer than the retention period.
+const trashPurgeInterval = time.Hour
+
 type Server struct {
 	cfg    *config.Config
 	logger logger.Logger
-	db     *storage.Sqlite
+	db     *storage.Database
 	srv    *http.Server
 }
 
 func New(cfg *config.Config, logger logger.Logger) (*Server, error) {
-	db, err := storage.NewSqliteStorage(cfg.DBPath, logger)
+	db, err := storage.Open(cfg.DBDriver, cfg.DataSource(), logger)
 	if err != nil {
 		return nil, err
 	}
@@ -37,17 +46,40 @@ func New(cfg *config.Config, logger logger.Logger) (*Server, error) {
 }
 
 func (s *Server) Start(ctx context.Context) error {
-	router := routes.Setup(s.db, s.logger)
+	var backupStore *backupService.BackupService
+	backups := s.cfg.Backups
+	if s.db.SupportsBackups() {
+		backupStore = backupService.NewBackupService(s.db, s.logger, backups.Dir, backups.KeepDaily, backups.KeepWeekly)
+		if backups.Interval > 0 {
+			go backupStore.Run(ctx, backups.Interval)
+		}
+	} else {
+		s.logger.Info("Backups are turned off, they are only supported for SQLite")
+	}
+
+	eventHub := eventsService.NewHub(s.logger)
+	if s.cfg.Trash.Retention > 0 {
+		trashStore := trashService.NewTrashService(s.db.Queries, s.db, s.logger, eventHub, s.cfg.Trash.Retention)
+		go trashStore.Run(ctx, trashPurgeInterval)
+	}
+
+	router := routes.Setup(s.db, s.logger, s.cfg, backupStore, eventHub)
 
 	corsHandler := cors.New(cors.Options{
-		AllowedOrigins: s.cfg.AllowedOrigins,
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-	}).Handler(router)
+		AllowedOrigins:   s.cfg.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "Last-Event-ID", middleware.RequestIdHeader},
+		ExposedHeaders:   []string{middleware.RequestIdHeader},
+		AllowCredentials: true,
+	}).Handler(middleware.RequestId(router))
 
 	s.srv = &http.Server{
 		Addr:    s.cfg.ListenAddr,
 		Handler: corsHandler,
 	}
+	// Event streams stay open until the client leaves, so end them for the
+	// shutdown to finish
+	s.srv.RegisterOnShutdown(eventHub.Close)
 
 	go func() {
 		s.logger.Info("server started", slog.String("addr", s.cfg.ListenAddr))
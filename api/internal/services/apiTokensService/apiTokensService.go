@@ -0,0 +1,169 @@
+package apiTokensService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+// tokenPrefix makes API tokens easy to recognise, e.g. in secret scanners.
+const tokenPrefix = "ht_"
+
+var (
+	ErrInvalidToken  = errors.New("invalid API token")
+	ErrTokenNotFound = errors.New("API token not found")
+	ErrNameRequired  = errors.New("name is required")
+	ErrInvalidScope  = errors.New("scope must be read or readWrite")
+)
+
+type ApiTokenStorage interface {
+	CreateApiToken(ctx context.Context, arg repository.CreateApiTokenParams) (repository.ApiToken, error)
+	GetApiTokens(ctx context.Context, userID int64) ([]repository.ApiToken, error)
+	DeleteApiToken(ctx context.Context, arg repository.DeleteApiTokenParams) (int64, error)
+	GetApiTokenUser(ctx context.Context, tokenHash string) (repository.GetApiTokenUserRow, error)
+	UpdateApiTokenLastUsed(ctx context.Context, arg repository.UpdateApiTokenLastUsedParams) error
+}
+
+type ApiTokenService struct {
+	storage ApiTokenStorage
+	logger  logger.Logger
+}
+
+func NewApiTokenService(storage ApiTokenStorage, logger logger.Logger) *ApiTokenService {
+	return &ApiTokenService{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// CreateToken issues a new token. The returned token is the only time the
+// plain text value is available, only its hash is stored.
+func (s ApiTokenService) CreateToken(userId int64, name string, scope models.Scope) (models.ApiToken, error) {
+	ctx := context.Background()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.ApiToken{}, ErrNameRequired
+	}
+	if !scope.IsValid() {
+		return models.ApiToken{}, ErrInvalidScope
+	}
+
+	token, err := authService.NewToken()
+	if err != nil {
+		return models.ApiToken{}, err
+	}
+	token = tokenPrefix + token
+
+	apiToken, err := s.storage.CreateApiToken(ctx, repository.CreateApiTokenParams{
+		UserID:    userId,
+		Name:      name,
+		TokenHash: authService.HashToken(token),
+		Scope:     string(scope),
+	})
+	if err != nil {
+		return models.ApiToken{}, err
+	}
+
+	createdToken, err := newApiTokenFromStorage(apiToken)
+	if err != nil {
+		return models.ApiToken{}, err
+	}
+
+	createdToken.Token = token
+	return createdToken, nil
+}
+
+func (s ApiTokenService) GetTokens(userId int64) ([]models.ApiToken, error) {
+	ctx := context.Background()
+	rawTokens, err := s.storage.GetApiTokens(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]models.ApiToken, len(rawTokens))
+	for i, token := range rawTokens {
+		tokens[i], err = newApiTokenFromStorage(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+func (s ApiTokenService) RevokeToken(userId int64, tokenId int64) error {
+	ctx := context.Background()
+	deleted, err := s.storage.DeleteApiToken(ctx, repository.DeleteApiTokenParams{
+		ID:     tokenId,
+		UserID: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// Authenticate returns the user and scope an API token grants.
+func (s ApiTokenService) Authenticate(token string) (models.User, models.Scope, error) {
+	ctx := context.Background()
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return models.User{}, "", ErrInvalidToken
+	}
+
+	apiToken, err := s.storage.GetApiTokenUser(ctx, authService.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, "", ErrInvalidToken
+	}
+	if err != nil {
+		return models.User{}, "", err
+	}
+
+	err = s.storage.UpdateApiTokenLastUsed(ctx, repository.UpdateApiTokenLastUsedParams{
+		LastUsedAt: sql.NullString{String: time.Now().UTC().Format(time.DateTime), Valid: true},
+		ID:         apiToken.ID,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to update API token last used", slog.Any("error", err))
+	}
+
+	user := models.NewAdminUser(apiToken.UserID, apiToken.Name, apiToken.Admin)
+	user.Settings = models.NewUserSettings(apiToken.Timezone, apiToken.DayStartHour)
+	return user, models.Scope(apiToken.Scope), nil
+}
+
+func newApiTokenFromStorage(apiToken repository.ApiToken) (models.ApiToken, error) {
+	createdAt, err := time.Parse(time.DateTime, apiToken.CreatedAt)
+	if err != nil {
+		return models.ApiToken{}, err
+	}
+
+	var lastUsedAt *time.Time
+	if apiToken.LastUsedAt.Valid {
+		parsed, err := time.Parse(time.DateTime, apiToken.LastUsedAt.String)
+		if err != nil {
+			return models.ApiToken{}, err
+		}
+		lastUsedAt = &parsed
+	}
+
+	return models.ApiToken{
+		CreatedAt:  createdAt,
+		LastUsedAt: lastUsedAt,
+		Name:       apiToken.Name,
+		Scope:      models.Scope(apiToken.Scope),
+		Id:         apiToken.ID,
+	}, nil
+}
@@ -0,0 +1,239 @@
+package apiTokensService
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/authService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockApiTokenStorage struct {
+	tokens map[int64]repository.ApiToken
+	users  map[int64]repository.User
+}
+
+func newMockApiTokenStorage() *mockApiTokenStorage {
+	return &mockApiTokenStorage{
+		tokens: map[int64]repository.ApiToken{},
+		users: map[int64]repository.User{
+			1: {ID: 1, Name: "alice", Timezone: "Europe/London"},
+			2: {ID: 2, Name: "bob"},
+		},
+	}
+}
+
+func (m *mockApiTokenStorage) CreateApiToken(_ context.Context, arg repository.CreateApiTokenParams) (repository.ApiToken, error) {
+	token := repository.ApiToken{
+		ID:        int64(len(m.tokens) + 1),
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+		TokenHash: arg.TokenHash,
+		Scope:     arg.Scope,
+		CreatedAt: "2024-12-16 10:00:00",
+	}
+	m.tokens[token.ID] = token
+	return token, nil
+}
+
+func (m *mockApiTokenStorage) GetApiTokens(_ context.Context, userId int64) ([]repository.ApiToken, error) {
+	tokens := make([]repository.ApiToken, 0)
+	for _, token := range m.tokens {
+		if token.UserID == userId {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens, nil
+}
+
+func (m *mockApiTokenStorage) DeleteApiToken(_ context.Context, arg repository.DeleteApiTokenParams) (int64, error) {
+	token, ok := m.tokens[arg.ID]
+	if !ok || token.UserID != arg.UserID {
+		return 0, nil
+	}
+
+	delete(m.tokens, arg.ID)
+	return 1, nil
+}
+
+func (m *mockApiTokenStorage) GetApiTokenUser(_ context.Context, tokenHash string) (repository.GetApiTokenUserRow, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			user := m.users[token.UserID]
+			return repository.GetApiTokenUserRow{ID: token.ID, Scope: token.Scope, UserID: user.ID, Name: user.Name, Timezone: user.Timezone}, nil
+		}
+	}
+
+	return repository.GetApiTokenUserRow{}, sql.ErrNoRows
+}
+
+func (m *mockApiTokenStorage) UpdateApiTokenLastUsed(_ context.Context, arg repository.UpdateApiTokenLastUsedParams) error {
+	token := m.tokens[arg.ID]
+	token.LastUsedAt = arg.LastUsedAt
+	m.tokens[arg.ID] = token
+	return nil
+}
+
+func TestCreateToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenName   string
+		scope       models.Scope
+		expectedErr error
+	}{
+		{
+			name:      "creates a read token",
+			tokenName: " Shortcuts ",
+			scope:     models.ScopeRead,
+		},
+		{
+			name:      "creates a read write token",
+			tokenName: "Shortcuts",
+			scope:     models.ScopeReadWrite,
+		},
+		{
+			name:        "rejects a token without a name",
+			tokenName:   " ",
+			scope:       models.ScopeRead,
+			expectedErr: ErrNameRequired,
+		},
+		{
+			name:        "rejects an unknown scope",
+			tokenName:   "Shortcuts",
+			scope:       "admin",
+			expectedErr: ErrInvalidScope,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockApiTokenStorage()
+			service := NewApiTokenService(storage, &logger.MockLogger{})
+
+			// Act
+			token, err := service.CreateToken(1, tc.tokenName, tc.scope)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				assert.Empty(t, storage.tokens)
+				return
+			}
+			assert.Equal(t, "Shortcuts", token.Name)
+			assert.Equal(t, tc.scope, token.Scope)
+			assert.True(t, strings.HasPrefix(token.Token, tokenPrefix))
+			stored := storage.tokens[token.Id]
+			assert.Equal(t, authService.HashToken(token.Token), stored.TokenHash)
+			assert.NotContains(t, stored.TokenHash, strings.TrimPrefix(token.Token, tokenPrefix))
+		})
+	}
+}
+
+func TestCreateTokenIsUnique(t *testing.T) {
+	// Arrange
+	service := NewApiTokenService(newMockApiTokenStorage(), &logger.MockLogger{})
+
+	// Act
+	first, firstErr := service.CreateToken(1, "Shortcuts", models.ScopeRead)
+	second, secondErr := service.CreateToken(1, "Shortcuts", models.ScopeRead)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NotEqual(t, first.Token, second.Token)
+}
+
+func TestGetTokensHidesTheToken(t *testing.T) {
+	// Arrange
+	service := NewApiTokenService(newMockApiTokenStorage(), &logger.MockLogger{})
+	_, err := service.CreateToken(1, "Shortcuts", models.ScopeRead)
+	require.NoError(t, err)
+	_, err = service.CreateToken(2, "Widgets", models.ScopeRead)
+	require.NoError(t, err)
+
+	// Act
+	tokens, err := service.GetTokens(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.Equal(t, "Shortcuts", tokens[0].Name)
+	assert.Empty(t, tokens[0].Token)
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         func(created string) string
+		expectedErr   error
+		expectedScope models.Scope
+	}{
+		{
+			name:          "returns the user and scope of a token",
+			token:         func(created string) string { return created },
+			expectedScope: models.ScopeRead,
+		},
+		{
+			name:        "rejects a token that was not issued",
+			token:       func(created string) string { return created + "x" },
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name:        "rejects a token without the prefix",
+			token:       func(created string) string { return strings.TrimPrefix(created, tokenPrefix) },
+			expectedErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockApiTokenStorage()
+			service := NewApiTokenService(storage, &logger.MockLogger{})
+			created, err := service.CreateToken(1, "Shortcuts", models.ScopeRead)
+			require.NoError(t, err)
+
+			// Act
+			user, scope, err := service.Authenticate(tc.token(created.Token))
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedScope, scope)
+			if tc.expectedErr != nil {
+				assert.False(t, storage.tokens[created.Id].LastUsedAt.Valid)
+				return
+			}
+			assert.Equal(t, int64(1), user.Id)
+			assert.Equal(t, "alice", user.Name)
+			assert.Equal(t, "Europe/London", user.Settings.Timezone)
+			assert.True(t, storage.tokens[created.Id].LastUsedAt.Valid)
+		})
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	// Arrange
+	service := NewApiTokenService(newMockApiTokenStorage(), &logger.MockLogger{})
+	created, err := service.CreateToken(1, "Shortcuts", models.ScopeReadWrite)
+	require.NoError(t, err)
+
+	// Act
+	otherUserErr := service.RevokeToken(2, created.Id)
+	err = service.RevokeToken(1, created.Id)
+	_, _, authErr := service.Authenticate(created.Token)
+	notFoundErr := service.RevokeToken(1, created.Id)
+
+	// Assert
+	assert.ErrorIs(t, otherUserErr, ErrTokenNotFound)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, authErr, ErrInvalidToken)
+	assert.ErrorIs(t, notFoundErr, ErrTokenNotFound)
+}
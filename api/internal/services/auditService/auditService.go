@@ -0,0 +1,215 @@
+package auditService
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const (
+	EntityHabit        = "habit"
+	EntityHabitEntry   = "habitEntry"
+	EntityUser         = "user"
+	EntityJournalEntry = "journalEntry"
+	EntityCategory     = "category"
+	EntityTag          = "tag"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type contextKey string
+
+const (
+	actorContextKey     contextKey = "auditActor"
+	requestIdContextKey contextKey = "requestId"
+)
+
+// WithActor returns a context where changes are recorded as made by userId.
+func WithActor(ctx context.Context, userId int64) context.Context {
+	return context.WithValue(ctx, actorContextKey, userId)
+}
+
+// WithRequestId returns a context where changes are recorded as part of the
+// request with requestId.
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdContextKey, requestId)
+}
+
+// RequestId returns the ID of the request set by WithRequestId, or "" if there isn't one.
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdContextKey).(string)
+	return requestId
+}
+
+type AuditWriter interface {
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Change is a create, update or delete of an entity belonging to UserId.
+// Before and After are stored as JSON, nil for creates and deletes.
+type Change struct {
+	Before     any
+	After      any
+	EntityType string
+	Action     string
+	UserId     int64
+	EntityId   int64
+}
+
+// Record writes an audit event for change with the actor and request ID from
+// ctx. Pass the queries of a transaction to only record the change if the
+// transaction commits.
+func Record(ctx context.Context, storage AuditWriter, change Change) error {
+	before, err := marshal(change.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := marshal(change.After)
+	if err != nil {
+		return err
+	}
+
+	params := repository.CreateAuditEventParams{
+		UserID:     change.UserId,
+		EntityType: change.EntityType,
+		EntityID:   change.EntityId,
+		Action:     change.Action,
+		DataBefore: before,
+		DataAfter:  after,
+		CreatedAt:  time.Now().UTC().Format(time.DateTime),
+	}
+	if actorId, ok := ctx.Value(actorContextKey).(int64); ok {
+		params.ActorID = sql.NullInt64{Int64: actorId, Valid: true}
+	}
+	if requestId := RequestId(ctx); requestId != "" {
+		params.RequestID = sql.NullString{String: requestId, Valid: true}
+	}
+
+	_, err = storage.CreateAuditEvent(ctx, params)
+	return err
+}
+
+func marshal(value any) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal audit data: %w", err)
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+type AuditStorage interface {
+	GetAuditEvents(ctx context.Context, arg repository.GetAuditEventsParams) ([]repository.AuditEvent, error)
+}
+
+type AuditService struct {
+	storage AuditStorage
+	logger  logger.Logger
+}
+
+func NewAuditService(storage AuditStorage, logger logger.Logger) *AuditService {
+	return &AuditService{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// GetEvents returns up to filter.Limit of a user's audit events matching the
+// filter, newest first, with a cursor to continue from if there are more.
+func (s *AuditService) GetEvents(userId int64, filter Filter) (EventsPage, error) {
+	ctx := context.Background()
+	params := repository.GetAuditEventsParams{
+		UserID: userId,
+		Limit:  filter.Limit + 1,
+	}
+	if filter.Cursor != "" {
+		beforeId, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil {
+			return EventsPage{}, ErrInvalidCursor
+		}
+		params.BeforeID = sql.NullInt64{Int64: beforeId, Valid: true}
+	}
+	if filter.EntityType != "" {
+		params.EntityType = sql.NullString{String: filter.EntityType, Valid: true}
+	}
+	if filter.EntityId != 0 {
+		params.EntityID = sql.NullInt64{Int64: filter.EntityId, Valid: true}
+	}
+	if filter.Action != "" {
+		params.Action = sql.NullString{String: filter.Action, Valid: true}
+	}
+	if !filter.From.IsZero() {
+		params.FromTime = sql.NullString{String: filter.From.UTC().Format(time.DateTime), Valid: true}
+	}
+	if !filter.To.IsZero() {
+		params.ToTime = sql.NullString{String: filter.To.UTC().Format(time.DateTime), Valid: true}
+	}
+
+	rawEvents, err := s.storage.GetAuditEvents(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to get audit events", slog.Any("error", err))
+		return EventsPage{}, err
+	}
+
+	page := EventsPage{Events: make([]Event, 0, len(rawEvents))}
+	if int64(len(rawEvents)) > filter.Limit {
+		rawEvents = rawEvents[:filter.Limit]
+		page.NextCursor = strconv.FormatInt(rawEvents[filter.Limit-1].ID, 10)
+	}
+
+	for _, rawEvent := range rawEvents {
+		event, err := newEventFromStorage(rawEvent)
+		if err != nil {
+			return EventsPage{}, err
+		}
+		page.Events = append(page.Events, event)
+	}
+
+	return page, nil
+}
+
+func newEventFromStorage(event repository.AuditEvent) (Event, error) {
+	createdAt, err := time.Parse(time.DateTime, event.CreatedAt)
+	if err != nil {
+		return Event{}, err
+	}
+
+	result := Event{
+		CreatedAt:  createdAt,
+		EntityType: event.EntityType,
+		Action:     event.Action,
+		RequestId:  event.RequestID.String,
+		Id:         event.ID,
+		EntityId:   event.EntityID,
+	}
+	if event.ActorID.Valid {
+		result.ActorId = &event.ActorID.Int64
+	}
+	if event.DataBefore.Valid {
+		result.Before = json.RawMessage(event.DataBefore.String)
+	}
+	if event.DataAfter.Valid {
+		result.After = json.RawMessage(event.DataAfter.String)
+	}
+
+	return result, nil
+}
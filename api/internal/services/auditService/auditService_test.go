@@ -0,0 +1,146 @@
+package auditService
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuditStorage struct {
+	params  repository.GetAuditEventsParams
+	created []repository.CreateAuditEventParams
+	events  []repository.AuditEvent
+}
+
+func (m *mockAuditStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.created = append(m.created, arg)
+	return repository.AuditEvent{}, nil
+}
+
+func (m *mockAuditStorage) GetAuditEvents(_ context.Context, arg repository.GetAuditEventsParams) ([]repository.AuditEvent, error) {
+	m.params = arg
+	if int64(len(m.events)) > arg.Limit {
+		return m.events[:arg.Limit], nil
+	}
+
+	return m.events, nil
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		ctx               context.Context
+		name              string
+		expectedActor     sql.NullInt64
+		expectedRequestId sql.NullString
+	}{
+		{
+			name:              "records the actor and request",
+			ctx:               WithActor(WithRequestId(context.Background(), "abc123"), 7),
+			expectedActor:     sql.NullInt64{Int64: 7, Valid: true},
+			expectedRequestId: sql.NullString{String: "abc123", Valid: true},
+		},
+		{
+			name: "records changes made outside a request without an actor",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockAuditStorage{}
+
+			// Act
+			err := Record(tc.ctx, storage, Change{
+				After:      map[string]string{"name": "Run"},
+				EntityType: EntityHabit,
+				Action:     ActionCreate,
+				UserId:     7,
+				EntityId:   3,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, storage.created, 1)
+			assert.Equal(t, tc.expectedActor, storage.created[0].ActorID)
+			assert.Equal(t, tc.expectedRequestId, storage.created[0].RequestID)
+			assert.False(t, storage.created[0].DataBefore.Valid)
+			assert.Equal(t, `{"name":"Run"}`, storage.created[0].DataAfter.String)
+		})
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	events := []repository.AuditEvent{
+		{ID: 9, EntityType: EntityHabit, EntityID: 1, Action: ActionUpdate, CreatedAt: "2024-12-22 10:00:00", ActorID: sql.NullInt64{Int64: 7, Valid: true}, DataBefore: sql.NullString{String: `{"name":"Run"}`, Valid: true}, DataAfter: sql.NullString{String: `{"name":"Jog"}`, Valid: true}},
+		{ID: 5, EntityType: EntityHabit, EntityID: 1, Action: ActionCreate, CreatedAt: "2024-12-21 10:00:00", DataAfter: sql.NullString{String: `{"name":"Run"}`, Valid: true}},
+		{ID: 2, EntityType: EntityUser, EntityID: 7, Action: ActionCreate, CreatedAt: "2024-12-20 10:00:00"},
+	}
+	tests := []struct {
+		expectedErr    error
+		name           string
+		filter         Filter
+		expectedCursor string
+		expectedIds    []int64
+		expectedParams repository.GetAuditEventsParams
+	}{
+		{
+			name:           "returns a cursor when there are more events",
+			filter:         Filter{Limit: 2},
+			expectedIds:    []int64{9, 5},
+			expectedCursor: "5",
+			expectedParams: repository.GetAuditEventsParams{UserID: 7, Limit: 3},
+		},
+		{
+			name:        "passes the filters and cursor to storage",
+			filter:      Filter{EntityType: EntityHabit, EntityId: 1, Action: ActionUpdate, From: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), Cursor: "10", Limit: 5},
+			expectedIds: []int64{9, 5, 2},
+			expectedParams: repository.GetAuditEventsParams{
+				UserID:     7,
+				BeforeID:   sql.NullInt64{Int64: 10, Valid: true},
+				EntityType: sql.NullString{String: EntityHabit, Valid: true},
+				EntityID:   sql.NullInt64{Int64: 1, Valid: true},
+				Action:     sql.NullString{String: ActionUpdate, Valid: true},
+				FromTime:   sql.NullString{String: "2024-12-01 00:00:00", Valid: true},
+				Limit:      6,
+			},
+		},
+		{
+			name:        "rejects an invalid cursor",
+			filter:      Filter{Cursor: "abc", Limit: 5},
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockAuditStorage{events: events}
+			service := NewAuditService(storage, &logger.MockLogger{})
+
+			// Act
+			page, err := service.GetEvents(7, tc.filter)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+			ids := make([]int64, len(page.Events))
+			for i, event := range page.Events {
+				ids[i] = event.Id
+			}
+			assert.Equal(t, tc.expectedIds, ids)
+			assert.Equal(t, tc.expectedCursor, page.NextCursor)
+			assert.Equal(t, tc.expectedParams, storage.params)
+			assert.Equal(t, int64(7), *page.Events[0].ActorId)
+			assert.JSONEq(t, `{"name":"Run"}`, string(page.Events[0].Before))
+			assert.Nil(t, page.Events[1].ActorId)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package auditService
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is a change to one of a user's habits, entries or their account.
+// Before is left out for creates and After for deletes. ActorId is the user
+// that made the change and is left out for changes made from the CLI.
+type Event struct {
+	CreatedAt  time.Time       `json:"createdAt"`
+	ActorId    *int64          `json:"actorId,omitempty"`
+	EntityType string          `json:"entityType"`
+	Action     string          `json:"action"`
+	RequestId  string          `json:"requestId,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Id         int64           `json:"id"`
+	EntityId   int64           `json:"entityId"`
+}
+
+// EventsPage is a page of audit events, newest first. NextCursor is set when
+// there are older events to fetch.
+type EventsPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// Filter narrows the audit events returned. Zero fields are not filtered on.
+// Cursor is the NextCursor of the previous page.
+type Filter struct {
+	From       time.Time
+	To         time.Time
+	EntityType string
+	Action     string
+	Cursor     string
+	EntityId   int64
+	Limit      int64
+}
@@ -0,0 +1,375 @@
+package authService
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	SessionCookieName = "session"
+	SessionDuration   = 30 * 24 * time.Hour
+	minPasswordLength = 8
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid name or password")
+	ErrUserExists         = errors.New("a user with that name already exists")
+	ErrInvalidSession     = errors.New("invalid or expired session")
+	ErrPasswordTooShort   = errors.New("password must be at least 8 characters")
+	ErrNameRequired       = errors.New("name is required")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidSettings    = errors.New("invalid settings")
+)
+
+type AuthStorage interface {
+	GetUserByID(ctx context.Context, id int64) (repository.User, error)
+	GetUserByName(ctx context.Context, name string) (repository.User, error)
+	GetUsers(ctx context.Context) ([]repository.User, error)
+	DeleteUser(ctx context.Context, id int64) (int64, error)
+	SetUserAdmin(ctx context.Context, arg repository.SetUserAdminParams) (int64, error)
+	CreateUserWithPassword(ctx context.Context, arg repository.CreateUserWithPasswordParams) (repository.User, error)
+	UpdateUserPassword(ctx context.Context, arg repository.UpdateUserPasswordParams) (repository.User, error)
+	UpdateUserSettings(ctx context.Context, arg repository.UpdateUserSettingsParams) (repository.User, error)
+	CreateSession(ctx context.Context, arg repository.CreateSessionParams) (repository.Session, error)
+	GetSessionUser(ctx context.Context, arg repository.GetSessionUserParams) (repository.GetSessionUserRow, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteExpiredSessions(ctx context.Context, expiresAt string) error
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Session is a newly issued session. The token is only ever available here,
+// only its hash is stored.
+type Session struct {
+	ExpiresAt time.Time
+	Token     string
+	User      models.User
+}
+
+type AuthService struct {
+	storage AuthStorage
+	logger  logger.Logger
+}
+
+func NewAuthService(storage AuthStorage, logger logger.Logger) *AuthService {
+	return &AuthService{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// Register creates a user with a password and signs them in.
+func (s AuthService) Register(ctx context.Context, name string, password string) (Session, error) {
+	user, err := s.CreateUser(ctx, name, password)
+	if err != nil {
+		return Session{}, err
+	}
+
+	return s.createSession(ctx, user)
+}
+
+// CreateUser creates a user with a password. Names are never reused, users
+// created before accounts existed are given a password with SetPassword.
+func (s AuthService) CreateUser(ctx context.Context, name string, password string) (models.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.User{}, ErrNameRequired
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	_, err = s.storage.GetUserByName(ctx, name)
+	if err == nil {
+		return models.User{}, ErrUserExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, err
+	}
+
+	user, err := s.storage.CreateUserWithPassword(ctx, repository.CreateUserWithPasswordParams{
+		Name:         name,
+		PasswordHash: passwordHash,
+	})
+	if repository.IsUniqueViolation(err) {
+		// Someone else registered the name since it was checked
+		return models.User{}, ErrUserExists
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	createdUser := models.NewUserFromStorage(user)
+	err = auditService.Record(ctx, s.storage, auditService.Change{
+		After:      createdUser,
+		EntityType: auditService.EntityUser,
+		Action:     auditService.ActionCreate,
+		UserId:     user.ID,
+		EntityId:   user.ID,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return createdUser, nil
+}
+
+// SetPassword replaces a user's password, which is how users created before
+// accounts existed are given one so they can sign in.
+func (s AuthService) SetPassword(ctx context.Context, userId int64, password string) (models.User, error) {
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	existingUser, err := s.storage.GetUserByID(ctx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.storage.UpdateUserPassword(ctx, repository.UpdateUserPasswordParams{
+		PasswordHash: passwordHash,
+		UpdatedAt:    time.Now().UTC().Format(time.DateTime),
+		ID:           userId,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	updatedUser := models.NewUserFromStorage(user)
+	err = auditService.Record(ctx, s.storage, auditService.Change{
+		Before:     models.NewUserFromStorage(existingUser),
+		After:      updatedUser,
+		EntityType: auditService.EntityUser,
+		Action:     auditService.ActionUpdate,
+		UserId:     userId,
+		EntityId:   userId,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+func hashPassword(password string) (sql.NullString, error) {
+	if len(password) < minPasswordLength {
+		return sql.NullString{}, ErrPasswordTooShort
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(passwordHash), Valid: true}, nil
+}
+
+func (s AuthService) Login(name string, password string) (Session, error) {
+	ctx := context.Background()
+	user, err := s.storage.GetUserByName(ctx, strings.TrimSpace(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Session{}, err
+	}
+
+	if !user.PasswordHash.Valid {
+		return Session{}, ErrInvalidCredentials
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(password))
+	if err != nil {
+		return Session{}, ErrInvalidCredentials
+	}
+
+	err = s.storage.DeleteExpiredSessions(ctx, time.Now().UTC().Format(time.DateTime))
+	if err != nil {
+		s.logger.Warn("Failed to delete expired sessions", slog.Any("error", err))
+	}
+
+	return s.createSession(ctx, models.NewUserFromStorage(user))
+}
+
+func (s AuthService) Logout(token string) error {
+	ctx := context.Background()
+	return s.storage.DeleteSession(ctx, HashToken(token))
+}
+
+// Authenticate returns the user a session token belongs to.
+func (s AuthService) Authenticate(token string) (models.User, error) {
+	ctx := context.Background()
+	user, err := s.storage.GetSessionUser(ctx, repository.GetSessionUserParams{
+		TokenHash: HashToken(token),
+		ExpiresAt: time.Now().UTC().Format(time.DateTime),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, ErrInvalidSession
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	authenticated := models.NewAdminUser(user.ID, user.Name, user.Admin)
+	authenticated.Settings = models.NewUserSettings(user.Timezone, user.DayStartHour)
+	return authenticated, nil
+}
+
+func (s AuthService) GetUser(userId int64) (models.User, error) {
+	ctx := context.Background()
+	user, err := s.storage.GetUserByID(ctx, userId)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return models.NewUserFromStorage(user), nil
+}
+
+func (s AuthService) GetUsers() ([]models.User, error) {
+	ctx := context.Background()
+	rawUsers, err := s.storage.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.User, len(rawUsers))
+	for i, user := range rawUsers {
+		users[i] = models.NewUserFromStorage(user)
+	}
+
+	return users, nil
+}
+
+// DeleteUser deletes a user along with all of their data.
+func (s AuthService) DeleteUser(userId int64) error {
+	ctx := context.Background()
+	deleted, err := s.storage.DeleteUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetAdmin grants or revokes a user's access to the administration endpoints.
+func (s AuthService) SetAdmin(userId int64, admin bool) error {
+	ctx := context.Background()
+	updated, err := s.storage.SetUserAdmin(ctx, repository.SetUserAdminParams{
+		Admin:     admin,
+		UpdatedAt: time.Now().UTC().Format(time.DateTime),
+		ID:        userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateSettings sets when a user's days start, recording the change in the
+// audit log.
+func (s AuthService) UpdateSettings(ctx context.Context, userId int64, settings models.UserSettings) (models.User, error) {
+	if err := settings.Validate(); err != nil {
+		return models.User{}, fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+	}
+
+	existingUser, err := s.storage.GetUserByID(ctx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.storage.UpdateUserSettings(ctx, repository.UpdateUserSettingsParams{
+		Timezone:     settings.Timezone,
+		DayStartHour: settings.DayStartHour,
+		UpdatedAt:    time.Now().UTC().Format(time.DateTime),
+		ID:           userId,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	updatedUser := models.NewUserFromStorage(user)
+	err = auditService.Record(ctx, s.storage, auditService.Change{
+		Before:     models.NewUserFromStorage(existingUser),
+		After:      updatedUser,
+		EntityType: auditService.EntityUser,
+		Action:     auditService.ActionUpdate,
+		UserId:     userId,
+		EntityId:   userId,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+func (s AuthService) createSession(ctx context.Context, user models.User) (Session, error) {
+	token, err := NewToken()
+	if err != nil {
+		return Session{}, err
+	}
+
+	expiresAt := time.Now().UTC().Add(SessionDuration)
+	_, err = s.storage.CreateSession(ctx, repository.CreateSessionParams{
+		UserID:    user.Id,
+		TokenHash: HashToken(token),
+		ExpiresAt: expiresAt.Format(time.DateTime),
+	})
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{
+		ExpiresAt: expiresAt,
+		Token:     token,
+		User:      user,
+	}, nil
+}
+
+// NewToken returns a random URL safe token for sessions and API tokens.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hash tokens are stored and looked up by.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
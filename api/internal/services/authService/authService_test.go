@@ -0,0 +1,420 @@
+package authService
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuthStorage struct {
+	users       map[string]repository.User
+	sessions    map[string]int64
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func newMockAuthStorage(users ...repository.User) *mockAuthStorage {
+	m := &mockAuthStorage{
+		users:    map[string]repository.User{},
+		sessions: map[string]int64{},
+	}
+	for _, user := range users {
+		m.users[user.Name] = user
+	}
+
+	return m
+}
+
+func (m *mockAuthStorage) GetUserByID(_ context.Context, id int64) (repository.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+
+	return repository.User{}, sql.ErrNoRows
+}
+
+func (m *mockAuthStorage) GetUserByName(_ context.Context, name string) (repository.User, error) {
+	user, ok := m.users[name]
+	if !ok {
+		return repository.User{}, sql.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (m *mockAuthStorage) GetUsers(_ context.Context) ([]repository.User, error) {
+	users := make([]repository.User, 0, len(m.users))
+	for _, user := range m.users {
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (m *mockAuthStorage) DeleteUser(ctx context.Context, id int64) (int64, error) {
+	user, err := m.GetUserByID(ctx, id)
+	if err != nil {
+		return 0, nil
+	}
+
+	delete(m.users, user.Name)
+	return 1, nil
+}
+
+func (m *mockAuthStorage) SetUserAdmin(ctx context.Context, arg repository.SetUserAdminParams) (int64, error) {
+	user, err := m.GetUserByID(ctx, arg.ID)
+	if err != nil {
+		return 0, nil
+	}
+
+	user.Admin = arg.Admin
+	m.users[user.Name] = user
+	return 1, nil
+}
+
+func (m *mockAuthStorage) CreateUserWithPassword(_ context.Context, arg repository.CreateUserWithPasswordParams) (repository.User, error) {
+	user := repository.User{ID: int64(len(m.users) + 1), Name: arg.Name, PasswordHash: arg.PasswordHash}
+	m.users[user.Name] = user
+	return user, nil
+}
+
+func (m *mockAuthStorage) UpdateUserPassword(ctx context.Context, arg repository.UpdateUserPasswordParams) (repository.User, error) {
+	user, err := m.GetUserByID(ctx, arg.ID)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	user.PasswordHash = arg.PasswordHash
+	m.users[user.Name] = user
+	return user, nil
+}
+
+func (m *mockAuthStorage) UpdateUserSettings(ctx context.Context, arg repository.UpdateUserSettingsParams) (repository.User, error) {
+	user, err := m.GetUserByID(ctx, arg.ID)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	user.Timezone = arg.Timezone
+	user.DayStartHour = arg.DayStartHour
+	m.users[user.Name] = user
+	return user, nil
+}
+
+func (m *mockAuthStorage) CreateSession(_ context.Context, arg repository.CreateSessionParams) (repository.Session, error) {
+	m.sessions[arg.TokenHash] = arg.UserID
+	return repository.Session{UserID: arg.UserID, TokenHash: arg.TokenHash, ExpiresAt: arg.ExpiresAt}, nil
+}
+
+func (m *mockAuthStorage) GetSessionUser(ctx context.Context, arg repository.GetSessionUserParams) (repository.GetSessionUserRow, error) {
+	userId, ok := m.sessions[arg.TokenHash]
+	if !ok {
+		return repository.GetSessionUserRow{}, sql.ErrNoRows
+	}
+
+	user, err := m.GetUserByID(ctx, userId)
+	return repository.GetSessionUserRow{ID: user.ID, Name: user.Name, Admin: user.Admin, Timezone: user.Timezone, DayStartHour: user.DayStartHour}, err
+}
+
+func (m *mockAuthStorage) DeleteSession(_ context.Context, tokenHash string) error {
+	delete(m.sessions, tokenHash)
+	return nil
+}
+
+func (m *mockAuthStorage) DeleteExpiredSessions(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *mockAuthStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name          string
+		existingUsers []repository.User
+		userName      string
+		password      string
+		expectedErr    error
+		expectedAction string
+		expectedId     int64
+	}{
+		{
+			name:           "creates a new user",
+			userName:       "alice",
+			password:       "correct horse",
+			expectedAction: auditService.ActionCreate,
+			expectedId:     1,
+		},
+		{
+			name:          "rejects the name of a user without a password",
+			existingUsers: []repository.User{{ID: 7, Name: "alice"}},
+			userName:      " alice ",
+			password:      "correct horse",
+			expectedErr:   ErrUserExists,
+		},
+		{
+			name: "rejects a name that is already registered",
+			existingUsers: []repository.User{
+				{ID: 7, Name: "alice", PasswordHash: sql.NullString{String: "hash", Valid: true}},
+			},
+			userName:    "alice",
+			password:    "correct horse",
+			expectedErr: ErrUserExists,
+		},
+		{
+			name:        "rejects a short password",
+			userName:    "alice",
+			password:    "short",
+			expectedErr: ErrPasswordTooShort,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockAuthStorage(tc.existingUsers...)
+			service := NewAuthService(storage, &logger.MockLogger{})
+
+			// Act
+			session, err := service.Register(context.Background(), tc.userName, tc.password)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedId, session.User.Id)
+			if tc.expectedAction == "" {
+				assert.Empty(t, storage.auditEvents)
+				return
+			}
+			assert.Len(t, storage.auditEvents, 1)
+			assert.Equal(t, tc.expectedAction, storage.auditEvents[0].Action)
+			assert.Equal(t, tc.expectedId, storage.auditEvents[0].EntityID)
+			assert.NotContains(t, storage.auditEvents[0].DataAfter.String, "correct horse")
+		})
+	}
+}
+
+// racingStorage misses every existing user when checking a name, as if they
+// were created between the check and the insert.
+type racingStorage struct {
+	repository.Querier
+}
+
+func (s racingStorage) GetUserByName(_ context.Context, _ string) (repository.User, error) {
+	return repository.User{}, sql.ErrNoRows
+}
+
+func TestRegisterTakenNameRace(t *testing.T) {
+	tests := []struct {
+		name       string
+		createUser func(ctx context.Context, queries repository.Querier) error
+	}{
+		{
+			name: "user with a password",
+			createUser: func(ctx context.Context, queries repository.Querier) error {
+				_, err := queries.CreateUserWithPassword(ctx, repository.CreateUserWithPasswordParams{Name: "alice", PasswordHash: sql.NullString{String: "hash", Valid: true}})
+				return err
+			},
+		},
+		{
+			name: "user without a password",
+			createUser: func(ctx context.Context, queries repository.Querier) error {
+				_, err := queries.CreateUser(ctx, "alice")
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			db, err := storage.NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+			require.NoError(t, err)
+			defer db.Close()
+			require.NoError(t, db.ApplyMigrations())
+			ctx := context.Background()
+			require.NoError(t, tc.createUser(ctx, db.Queries))
+			service := NewAuthService(racingStorage{Querier: db.Queries}, &logger.MockLogger{})
+
+			// Act
+			_, err = service.Register(ctx, "alice", "correct horse")
+
+			// Assert
+			assert.ErrorIs(t, err, ErrUserExists)
+		})
+	}
+}
+
+func TestSetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		expectedErr error
+		userId      int64
+	}{
+		{
+			name:     "gives a user without a password one",
+			userId:   7,
+			password: "correct horse",
+		},
+		{
+			name:        "rejects a user that does not exist",
+			userId:      8,
+			password:    "correct horse",
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "rejects a short password",
+			userId:      7,
+			password:    "short",
+			expectedErr: ErrPasswordTooShort,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockAuthStorage(repository.User{ID: 7, Name: "alice"})
+			service := NewAuthService(storage, &logger.MockLogger{})
+
+			// Act
+			_, err := service.SetPassword(context.Background(), tc.userId, tc.password)
+			_, loginErr := service.Login("alice", tc.password)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, loginErr, ErrInvalidCredentials)
+				assert.Empty(t, storage.auditEvents)
+				return
+			}
+			assert.NoError(t, loginErr)
+			assert.Len(t, storage.auditEvents, 1)
+			assert.Equal(t, auditService.ActionUpdate, storage.auditEvents[0].Action)
+			assert.NotContains(t, storage.auditEvents[0].DataAfter.String, "correct horse")
+		})
+	}
+}
+
+func TestLoginAndAuthenticate(t *testing.T) {
+	// Arrange
+	service := NewAuthService(newMockAuthStorage(), &logger.MockLogger{})
+	_, err := service.Register(context.Background(), "alice", "correct horse")
+	assert.NoError(t, err)
+
+	// Act
+	_, wrongPasswordErr := service.Login("alice", "battery staple")
+	session, err := service.Login("alice", "correct horse")
+	assert.NoError(t, err)
+	user, authErr := service.Authenticate(session.Token)
+	logoutErr := service.Logout(session.Token)
+	_, loggedOutErr := service.Authenticate(session.Token)
+
+	// Assert
+	assert.ErrorIs(t, wrongPasswordErr, ErrInvalidCredentials)
+	assert.NoError(t, authErr)
+	assert.Equal(t, "alice", user.Name)
+	assert.NoError(t, logoutErr)
+	assert.ErrorIs(t, loggedOutErr, ErrInvalidSession)
+}
+
+func TestDeleteUser(t *testing.T) {
+	// Arrange
+	service := NewAuthService(newMockAuthStorage(repository.User{ID: 7, Name: "alice"}), &logger.MockLogger{})
+
+	// Act
+	err := service.DeleteUser(7)
+	notFoundErr := service.DeleteUser(7)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, notFoundErr, ErrUserNotFound)
+}
+
+func TestSetAdmin(t *testing.T) {
+	// Arrange
+	storage := newMockAuthStorage(repository.User{ID: 7, Name: "alice"})
+	service := NewAuthService(storage, &logger.MockLogger{})
+	_, _ = service.SetPassword(context.Background(), 7, "password1")
+	session, _ := service.Login("alice", "password1")
+
+	// Act
+	err := service.SetAdmin(7, true)
+	user, authErr := service.Authenticate(session.Token)
+	notFoundErr := service.SetAdmin(8, true)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, authErr)
+	assert.True(t, user.Admin)
+	assert.ErrorIs(t, notFoundErr, ErrUserNotFound)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    models.UserSettings
+		userId      int64
+		expectedErr error
+	}{
+		{
+			name:     "sets the timezone and day start hour",
+			settings: models.NewUserSettings("Europe/London", 4),
+			userId:   7,
+		},
+		{
+			name:        "rejects an unknown timezone",
+			settings:    models.NewUserSettings("Mars/Olympus_Mons", 0),
+			userId:      7,
+			expectedErr: ErrInvalidSettings,
+		},
+		{
+			name:        "rejects a day start hour past the end of the day",
+			settings:    models.NewUserSettings("UTC", 24),
+			userId:      7,
+			expectedErr: ErrInvalidSettings,
+		},
+		{
+			name:        "rejects an unknown user",
+			settings:    models.NewDefaultUserSettings(),
+			userId:      8,
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockAuthStorage(repository.User{ID: 7, Name: "alice", Timezone: "UTC"})
+			service := NewAuthService(storage, &logger.MockLogger{})
+			_, _ = service.SetPassword(context.Background(), 7, "password1")
+			session, _ := service.Login("alice", "password1")
+
+			// Act
+			user, err := service.UpdateSettings(context.Background(), tc.userId, tc.settings)
+			authenticated, authErr := service.Authenticate(session.Token)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.NoError(t, authErr)
+			if tc.expectedErr != nil {
+				assert.Equal(t, models.NewDefaultUserSettings(), authenticated.Settings)
+				return
+			}
+			assert.Equal(t, tc.settings, user.Settings)
+			assert.Equal(t, tc.settings, authenticated.Settings)
+			assert.Equal(t, auditService.ActionUpdate, storage.auditEvents[len(storage.auditEvents)-1].Action)
+		})
+	}
+}
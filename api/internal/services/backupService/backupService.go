@@ -0,0 +1,250 @@
+package backupService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+)
+
+// backupTimeFormat is the creation time in a backup's name, e.g.
+// habits-20241214T183052Z.db.
+const backupTimeFormat = "20060102T150405Z"
+
+var backupName = regexp.MustCompile(`^habits-(\d{8}T\d{6}Z)\.db$`)
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+type BackupStorage interface {
+	Backup(path string) error
+	VerifyBackup(path string) error
+}
+
+// BackupService writes backups of the database to a directory and removes old
+// ones. Files in the directory that are not named like its backups are left alone.
+type BackupService struct {
+	storage    BackupStorage
+	logger     logger.Logger
+	now        func() time.Time
+	dir        string
+	keepDaily  int
+	keepWeekly int
+	mu         sync.Mutex
+}
+
+func NewBackupService(storage BackupStorage, logger logger.Logger, dir string, keepDaily int, keepWeekly int) *BackupService {
+	return &BackupService{
+		storage:    storage,
+		logger:     logger,
+		now:        time.Now,
+		dir:        dir,
+		keepDaily:  keepDaily,
+		keepWeekly: keepWeekly,
+	}
+}
+
+// CreateBackup backs up the database, only keeping the backup if it passes
+// an integrity check.
+func (s *BackupService) CreateBackup() (Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	createdAt := s.now().UTC().Truncate(time.Second)
+	name := fmt.Sprintf("habits-%s.db", createdAt.Format(backupTimeFormat))
+	path := filepath.Join(s.dir, name)
+	tempPath := filepath.Join(s.dir, "."+name+".tmp")
+
+	err := s.storage.Backup(tempPath)
+	if err != nil {
+		os.Remove(tempPath)
+		return Backup{}, err
+	}
+
+	err = s.storage.VerifyBackup(tempPath)
+	if err != nil {
+		os.Remove(tempPath)
+		return Backup{}, fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		os.Remove(tempPath)
+		return Backup{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	s.logger.Info("Created backup", slog.String("name", name), slog.Int64("size", info.Size()))
+	return Backup{CreatedAt: createdAt, Name: name, Size: info.Size()}, nil
+}
+
+// GetBackups returns the backups in the backup directory, newest first.
+func (s *BackupService) GetBackups() ([]Backup, error) {
+	files, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Backup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0)
+	for _, file := range files {
+		createdAt, ok := parseBackupName(file.Name())
+		if !ok || !file.Type().IsRegular() {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, Backup{CreatedAt: createdAt, Name: file.Name(), Size: info.Size()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// OpenBackup opens a backup by name to be downloaded.
+func (s *BackupService) OpenBackup(name string) (*os.File, Backup, error) {
+	createdAt, ok := parseBackupName(name)
+	if !ok {
+		return nil, Backup{}, ErrBackupNotFound
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, Backup{}, ErrBackupNotFound
+	}
+	if err != nil {
+		return nil, Backup{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, Backup{}, err
+	}
+
+	return file, Backup{CreatedAt: createdAt, Name: name, Size: info.Size()}, nil
+}
+
+// PruneBackups removes backups that are no longer kept, returning their names.
+func (s *BackupService) PruneBackups() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backups, err := s.GetBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := backupsToKeep(backups, s.keepDaily, s.keepWeekly)
+	removed := make([]string, 0)
+	for _, backup := range backups {
+		if keep[backup.Name] {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(s.dir, backup.Name))
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, backup.Name)
+	}
+
+	if len(removed) > 0 {
+		s.logger.Info("Removed old backups", slog.Any("names", removed))
+	}
+
+	return removed, nil
+}
+
+// Run backs up the database every interval until ctx is done, pruning old
+// backups after each one. The first backup is made straight away if the
+// newest backup is already older than interval, so restarts do not delay it.
+func (s *BackupService) Run(ctx context.Context, interval time.Duration) {
+	wait := interval
+	backups, err := s.GetBackups()
+	if err != nil {
+		s.logger.Error("Failed to get backups", slog.Any("error", err))
+	} else if len(backups) == 0 {
+		wait = 0
+	} else {
+		wait = max(interval-s.now().Sub(backups[0].CreatedAt), 0)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		_, err := s.CreateBackup()
+		if err != nil {
+			s.logger.Error("Failed to create scheduled backup", slog.Any("error", err))
+		} else if _, err := s.PruneBackups(); err != nil {
+			s.logger.Error("Failed to remove old backups", slog.Any("error", err))
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+func parseBackupName(name string) (time.Time, bool) {
+	match := backupName.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	createdAt, err := time.Parse(backupTimeFormat, match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return createdAt, true
+}
+
+// backupsToKeep returns the names of the newest backup on each of the last
+// keepDaily days and in each of the last keepWeekly weeks that have a backup.
+// Backups must be ordered newest first.
+func backupsToKeep(backups []Backup, keepDaily int, keepWeekly int) map[string]bool {
+	keep := make(map[string]bool)
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, backup := range backups {
+		day := backup.CreatedAt.Format(time.DateOnly)
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep[backup.Name] = true
+		}
+
+		year, week := backup.CreatedAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%02d", year, week)
+		if !weeks[weekKey] && len(weeks) < keepWeekly {
+			weeks[weekKey] = true
+			keep[backup.Name] = true
+		}
+	}
+
+	return keep
+}
@@ -0,0 +1,142 @@
+package backupService
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBackupStorage struct {
+	verifyErr error
+}
+
+func (m mockBackupStorage) Backup(path string) error {
+	return os.WriteFile(path, []byte("backup"), 0644)
+}
+
+func (m mockBackupStorage) VerifyBackup(_ string) error {
+	return m.verifyErr
+}
+
+func backupAt(value string) Backup {
+	createdAt, _ := time.Parse(time.DateTime, value)
+	return Backup{CreatedAt: createdAt, Name: "habits-" + createdAt.Format(backupTimeFormat) + ".db"}
+}
+
+func TestBackupsToKeep(t *testing.T) {
+	tests := []struct {
+		name       string
+		backups    []Backup
+		expected   []string
+		keepDaily  int
+		keepWeekly int
+	}{
+		{
+			name: "keeps the newest backup of each day",
+			backups: []Backup{
+				backupAt("2024-12-14 18:00:00"),
+				backupAt("2024-12-14 06:00:00"),
+				backupAt("2024-12-13 18:00:00"),
+				backupAt("2024-12-12 18:00:00"),
+			},
+			keepDaily: 2,
+			expected:  []string{"habits-20241214T180000Z.db", "habits-20241213T180000Z.db"},
+		},
+		{
+			name: "keeps the newest backup of each week after the daily backups",
+			// The 14th and 8th are Saturday and Sunday of the weeks starting the 9th and 2nd
+			backups: []Backup{
+				backupAt("2024-12-14 18:00:00"),
+				backupAt("2024-12-13 18:00:00"),
+				backupAt("2024-12-08 18:00:00"),
+				backupAt("2024-12-07 18:00:00"),
+				backupAt("2024-12-01 18:00:00"),
+			},
+			keepDaily:  1,
+			keepWeekly: 2,
+			expected:   []string{"habits-20241214T180000Z.db", "habits-20241208T180000Z.db"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			keep := backupsToKeep(tc.backups, tc.keepDaily, tc.keepWeekly)
+
+			// Assert
+			kept := make([]string, 0)
+			for _, backup := range tc.backups {
+				if keep[backup.Name] {
+					kept = append(kept, backup.Name)
+				}
+			}
+			assert.Equal(t, tc.expected, kept)
+		})
+	}
+}
+
+func TestCreateBackup(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	service := NewBackupService(mockBackupStorage{}, &logger.MockLogger{}, dir, 7, 4)
+	service.now = func() time.Time { return time.Date(2024, 12, 14, 18, 30, 52, 0, time.UTC) }
+
+	// Act
+	backup, err := service.CreateBackup()
+	backups, listErr := service.GetBackups()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, listErr)
+	assert.Equal(t, "habits-20241214T183052Z.db", backup.Name)
+	assert.Equal(t, int64(len("backup")), backup.Size)
+	assert.Equal(t, []Backup{backup}, backups)
+}
+
+func TestCreateBackupFailsVerification(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	service := NewBackupService(mockBackupStorage{verifyErr: errors.New("corrupt")}, &logger.MockLogger{}, dir, 7, 4)
+
+	// Act
+	_, err := service.CreateBackup()
+
+	// Assert
+	assert.Error(t, err)
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+func TestPruneBackups(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, name := range []string{"habits-20241214T180000Z.db", "habits-20241214T060000Z.db", "manual.db"} {
+		os.WriteFile(filepath.Join(dir, name), []byte("backup"), 0644)
+	}
+	service := NewBackupService(mockBackupStorage{}, &logger.MockLogger{}, dir, 7, 4)
+
+	// Act
+	removed, err := service.PruneBackups()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"habits-20241214T060000Z.db"}, removed)
+	_, manualErr := os.Stat(filepath.Join(dir, "manual.db"))
+	assert.NoError(t, manualErr)
+}
+
+func TestOpenBackupRejectsOtherFiles(t *testing.T) {
+	// Arrange
+	service := NewBackupService(mockBackupStorage{}, &logger.MockLogger{}, t.TempDir(), 7, 4)
+
+	// Act
+	_, _, err := service.OpenBackup("../data.db")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+}
@@ -0,0 +1,9 @@
+package backupService
+
+import "time"
+
+type Backup struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+}
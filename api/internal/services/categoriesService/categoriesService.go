@@ -0,0 +1,255 @@
+package categoriesService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const MaxNameLength = 100
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("a category with that name already exists")
+	ErrNameRequired     = errors.New("name is required")
+	ErrNameTooLong      = errors.New("name must be at most 100 characters")
+)
+
+type CategoryStorage interface {
+	GetCategories(ctx context.Context, userID int64) ([]repository.Category, error)
+	GetCategory(ctx context.Context, arg repository.GetCategoryParams) (repository.Category, error)
+	CreateCategory(ctx context.Context, arg repository.CreateCategoryParams) (repository.Category, error)
+	UpdateCategory(ctx context.Context, arg repository.UpdateCategoryParams) (repository.Category, error)
+	DeleteCategory(ctx context.Context, arg repository.DeleteCategoryParams) (repository.Category, error)
+	ClearHabitCategory(ctx context.Context, arg repository.ClearHabitCategoryParams) ([]repository.Habit, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Transactor runs fn with queries inside a transaction, rolling back if fn
+// returns an error.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
+}
+
+// EventPublisher tells a user's other devices about changes to their habits.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
+}
+
+type CategoryService struct {
+	storage    CategoryStorage
+	transactor Transactor
+	logger     logger.Logger
+	events     EventPublisher
+}
+
+func NewCategoryService(storage CategoryStorage, transactor Transactor, logger logger.Logger, events EventPublisher) *CategoryService {
+	return &CategoryService{
+		storage:    storage,
+		transactor: transactor,
+		logger:     logger,
+		events:     events,
+	}
+}
+
+// GetCategories returns a user's categories in name order.
+func (s *CategoryService) GetCategories(userId int64) ([]models.Category, error) {
+	ctx := context.Background()
+	rawCategories, err := s.storage.GetCategories(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.Category, len(rawCategories))
+	for i, category := range rawCategories {
+		categories[i] = models.NewCategoryFromStorage(category)
+	}
+
+	return categories, nil
+}
+
+// CreateCategory adds a category for a user. Names are unique per user,
+// ignoring case.
+func (s *CategoryService) CreateCategory(ctx context.Context, userId int64, name string, colour string) (models.Category, error) {
+	var category models.Category
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		category, err = createCategory(ctx, queries, userId, name, colour)
+		return err
+	})
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+func createCategory(ctx context.Context, storage CategoryStorage, userId int64, name string, colour string) (models.Category, error) {
+	name, colour, err := validateCategory(ctx, storage, userId, 0, name, colour)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	created, err := storage.CreateCategory(ctx, repository.CreateCategoryParams{
+		UserID: userId,
+		Name:   name,
+		Colour: colour,
+	})
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	category := models.NewCategoryFromStorage(created)
+	err = recordChange(ctx, storage, auditService.ActionCreate, userId, category.Id, nil, category)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+// UpdateCategory renames or recolours a user's category.
+func (s *CategoryService) UpdateCategory(ctx context.Context, userId int64, categoryId int64, name string, colour string) (models.Category, error) {
+	var category models.Category
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		category, err = updateCategory(ctx, queries, userId, categoryId, name, colour)
+		return err
+	})
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+func updateCategory(ctx context.Context, storage CategoryStorage, userId int64, categoryId int64, name string, colour string) (models.Category, error) {
+	existing, err := storage.GetCategory(ctx, repository.GetCategoryParams{ID: categoryId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Category{}, ErrCategoryNotFound
+	}
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	name, colour, err = validateCategory(ctx, storage, userId, categoryId, name, colour)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	updated, err := storage.UpdateCategory(ctx, repository.UpdateCategoryParams{
+		Name:      name,
+		Colour:    colour,
+		UpdatedAt: time.Now().UTC().Format(time.DateTime),
+		ID:        categoryId,
+		UserID:    userId,
+	})
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	category := models.NewCategoryFromStorage(updated)
+	err = recordChange(ctx, storage, auditService.ActionUpdate, userId, categoryId, models.NewCategoryFromStorage(existing), category)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory deletes a user's category, leaving its habits uncategorised.
+func (s *CategoryService) DeleteCategory(ctx context.Context, userId int64, categoryId int64) error {
+	var habits []repository.Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habits, err = deleteCategory(ctx, queries, userId, categoryId)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, habit := range habits {
+		s.events.Publish(userId, eventsService.HabitUpdated, habitsService.NewHabitFromStorage(habit, nil))
+	}
+	return nil
+}
+
+// deleteCategory deletes a category, returning the habits that were in it.
+func deleteCategory(ctx context.Context, storage CategoryStorage, userId int64, categoryId int64) ([]repository.Habit, error) {
+	habits, err := storage.ClearHabitCategory(ctx, repository.ClearHabitCategoryParams{
+		UpdatedAt:  time.Now().UTC().Format(time.DateTime),
+		UserID:     userId,
+		CategoryID: sql.NullInt64{Int64: categoryId, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := storage.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: categoryId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordChange(ctx, storage, auditService.ActionDelete, userId, categoryId, models.NewCategoryFromStorage(deleted), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return habits, nil
+}
+
+// validateCategory returns the trimmed name and colour, defaulting the colour,
+// and checks no other of the user's categories has the name.
+func validateCategory(ctx context.Context, storage CategoryStorage, userId int64, categoryId int64, name string, colour string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", "", ErrNameTooLong
+	}
+
+	colour = strings.TrimSpace(colour)
+	if colour == "" {
+		colour = models.DefaultColour
+	}
+
+	categories, err := storage.GetCategories(ctx, userId)
+	if err != nil {
+		return "", "", err
+	}
+	for _, category := range categories {
+		if category.ID != categoryId && strings.EqualFold(category.Name, name) {
+			return "", "", ErrCategoryExists
+		}
+	}
+
+	return name, colour, nil
+}
+
+// recordChange records a change to a category in the audit log. before is nil
+// for creates and after is nil for deletes.
+func recordChange(ctx context.Context, storage CategoryStorage, action string, userId int64, categoryId int64, before any, after any) error {
+	return auditService.Record(ctx, storage, auditService.Change{
+		Before:     before,
+		After:      after,
+		EntityType: auditService.EntityCategory,
+		Action:     action,
+		UserId:     userId,
+		EntityId:   categoryId,
+	})
+}
@@ -0,0 +1,212 @@
+package categoriesService
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCategoryStorage struct {
+	categories  []repository.Category
+	habits      []repository.Habit
+	clearedFrom int64
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func (m *mockCategoryStorage) GetCategories(_ context.Context, userId int64) ([]repository.Category, error) {
+	var categories []repository.Category
+	for _, category := range m.categories {
+		if category.UserID == userId {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories, nil
+}
+
+func (m *mockCategoryStorage) GetCategory(_ context.Context, arg repository.GetCategoryParams) (repository.Category, error) {
+	for _, category := range m.categories {
+		if category.ID == arg.ID && category.UserID == arg.UserID {
+			return category, nil
+		}
+	}
+
+	return repository.Category{}, sql.ErrNoRows
+}
+
+func (m *mockCategoryStorage) CreateCategory(_ context.Context, arg repository.CreateCategoryParams) (repository.Category, error) {
+	category := repository.Category{ID: int64(len(m.categories) + 1), UserID: arg.UserID, Name: arg.Name, Colour: arg.Colour}
+	m.categories = append(m.categories, category)
+	return category, nil
+}
+
+func (m *mockCategoryStorage) UpdateCategory(ctx context.Context, arg repository.UpdateCategoryParams) (repository.Category, error) {
+	for i, category := range m.categories {
+		if category.ID == arg.ID && category.UserID == arg.UserID {
+			m.categories[i].Name = arg.Name
+			m.categories[i].Colour = arg.Colour
+			return m.categories[i], nil
+		}
+	}
+
+	return repository.Category{}, sql.ErrNoRows
+}
+
+func (m *mockCategoryStorage) DeleteCategory(_ context.Context, arg repository.DeleteCategoryParams) (repository.Category, error) {
+	for i, category := range m.categories {
+		if category.ID == arg.ID && category.UserID == arg.UserID {
+			m.categories = append(m.categories[:i], m.categories[i+1:]...)
+			return category, nil
+		}
+	}
+
+	return repository.Category{}, sql.ErrNoRows
+}
+
+func (m *mockCategoryStorage) ClearHabitCategory(_ context.Context, arg repository.ClearHabitCategoryParams) ([]repository.Habit, error) {
+	m.clearedFrom = arg.CategoryID.Int64
+	var cleared []repository.Habit
+	for i, habit := range m.habits {
+		if habit.UserID == arg.UserID && habit.CategoryID == arg.CategoryID {
+			m.habits[i].CategoryID = sql.NullInt64{}
+			cleared = append(cleared, m.habits[i])
+		}
+	}
+
+	return cleared, nil
+}
+
+func (m *mockCategoryStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+func existingCategories() []repository.Category {
+	return []repository.Category{
+		{ID: 1, UserID: 1, Name: "Health", Colour: "#16a34a"},
+		{ID: 2, UserID: 2, Name: "Work", Colour: "#dc2626"},
+	}
+}
+
+func TestCreateCategory(t *testing.T) {
+	tests := []struct {
+		name             string
+		categoryName     string
+		colour           string
+		expectedErr      error
+		expectedCategory models.Category
+	}{
+		{
+			name:             "creates a category with a default colour",
+			categoryName:     " Learning ",
+			expectedCategory: models.Category{Id: 3, Name: "Learning", Colour: models.DefaultColour},
+		},
+		{
+			name:             "allows a name another user has",
+			categoryName:     "Work",
+			colour:           "#dc2626",
+			expectedCategory: models.Category{Id: 3, Name: "Work", Colour: "#dc2626"},
+		},
+		{
+			name:         "rejects a name the user already has",
+			categoryName: "health",
+			expectedErr:  ErrCategoryExists,
+		},
+		{
+			name:         "rejects a missing name",
+			categoryName: "  ",
+			expectedErr:  ErrNameRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockCategoryStorage{categories: existingCategories()}
+
+			// Act
+			category, err := createCategory(context.Background(), storage, 1, tc.categoryName, tc.colour)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedCategory, category)
+			if tc.expectedErr == nil {
+				assert.Len(t, storage.auditEvents, 1)
+				assert.Equal(t, auditService.EntityCategory, storage.auditEvents[0].EntityType)
+			}
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		categoryId   int64
+		categoryName string
+		expectedErr  error
+	}{
+		{
+			name:         "renames a category",
+			categoryId:   1,
+			categoryName: "Fitness",
+		},
+		{
+			name:         "keeps the name of a category with a new colour",
+			categoryId:   1,
+			categoryName: "Health",
+		},
+		{
+			name:         "rejects another user's category",
+			categoryId:   2,
+			categoryName: "Fitness",
+			expectedErr:  ErrCategoryNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockCategoryStorage{categories: existingCategories()}
+
+			// Act
+			category, err := updateCategory(context.Background(), storage, 1, tc.categoryId, tc.categoryName, "#000000")
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, models.Category{Id: tc.categoryId, Name: tc.categoryName, Colour: "#000000"}, category)
+				assert.Equal(t, auditService.ActionUpdate, storage.auditEvents[0].Action)
+			}
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	// Arrange
+	storage := &mockCategoryStorage{
+		categories: existingCategories(),
+		habits: []repository.Habit{
+			{ID: 1, UserID: 1, CategoryID: sql.NullInt64{Int64: 1, Valid: true}},
+			{ID: 2, UserID: 1},
+		},
+	}
+
+	// Act
+	habits, err := deleteCategory(context.Background(), storage, 1, 1)
+	clearedFrom := storage.clearedFrom
+	_, notFoundErr := deleteCategory(context.Background(), storage, 1, 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), clearedFrom)
+	assert.Equal(t, []repository.Habit{{ID: 1, UserID: 1}}, habits)
+	assert.ErrorIs(t, notFoundErr, ErrCategoryNotFound)
+	assert.Len(t, storage.categories, 1)
+	assert.Len(t, storage.auditEvents, 1)
+	assert.Equal(t, auditService.ActionDelete, storage.auditEvents[0].Action)
+}
@@ -0,0 +1,178 @@
+package eventsService
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+)
+
+const (
+	// subscriberBuffer is how many events a subscriber can fall behind by
+	// before it is disconnected, to resume from its last event.
+	subscriberBuffer = 32
+	// historySize is how many of each user's latest events are kept for
+	// subscribers resuming after reconnecting.
+	historySize = 100
+)
+
+// Subscription receives a user's events until it is closed, or until the
+// subscriber falls too far behind and its events channel is closed.
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+	userId int64
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+type userEvents struct {
+	subscribers map[*Subscription]struct{}
+	history     []Event
+	// evicted is the ID of the newest event no longer in history, subscribers
+	// resuming from before it have missed events.
+	evicted int64
+}
+
+// Hub fans out each user's events to all of their subscribers, such as the
+// event streams of their other devices.
+type Hub struct {
+	logger  logger.Logger
+	users   map[int64]*userEvents
+	lastId  int64
+	startId int64
+	mu      sync.Mutex
+	closed  bool
+}
+
+func NewHub(logger logger.Logger) *Hub {
+	// IDs carry on from the time the hub started, so the IDs of events from
+	// before a restart are always older than any it has.
+	startId := time.Now().UnixMicro()
+	return &Hub{
+		logger:  logger,
+		users:   make(map[int64]*userEvents),
+		lastId:  startId,
+		startId: startId,
+	}
+}
+
+// Publish sends an event to all of a user's subscribers. Subscribers that
+// can't keep up are disconnected.
+func (h *Hub) Publish(userId int64, eventType EventType, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error("Failed to encode event", slog.String("type", string(eventType)), slog.Any("error", err))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+
+	h.lastId++
+	event := Event{Type: eventType, Data: encoded, Id: h.lastId}
+	user := h.user(userId)
+	user.history = append(user.history, event)
+	if len(user.history) > historySize {
+		user.evicted = user.history[0].Id
+		user.history = user.history[1:]
+	}
+
+	for subscription := range user.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			h.logger.Warn("Disconnecting slow event subscriber", slog.Int64("userId", userId))
+			delete(user.subscribers, subscription)
+			close(subscription.events)
+		}
+	}
+}
+
+// Subscribe starts receiving a user's events. When lastEventId is set the
+// events since it are returned to be sent first, or a resync event if some of
+// them are no longer known.
+func (h *Hub) Subscribe(userId int64, lastEventId int64) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscription := &Subscription{
+		hub:    h,
+		events: make(chan Event, subscriberBuffer),
+		userId: userId,
+	}
+	if h.closed {
+		close(subscription.events)
+		return subscription, nil
+	}
+
+	user := h.user(userId)
+	user.subscribers[subscription] = struct{}{}
+	if lastEventId == 0 {
+		return subscription, nil
+	}
+	if lastEventId < user.evicted || lastEventId > h.lastId {
+		return subscription, []Event{{Type: Resync, Data: json.RawMessage("{}"), Id: h.lastId}}
+	}
+
+	missed := make([]Event, 0)
+	for _, event := range user.history {
+		if event.Id > lastEventId {
+			missed = append(missed, event)
+		}
+	}
+
+	return subscription, missed
+}
+
+// Close ends every subscription, for shutting down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, user := range h.users {
+		for subscription := range user.subscribers {
+			delete(user.subscribers, subscription)
+			close(subscription.events)
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	user := h.user(subscription.userId)
+	if _, ok := user.subscribers[subscription]; ok {
+		delete(user.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+// user returns a user's events, the hub must be locked.
+func (h *Hub) user(userId int64) *userEvents {
+	user, ok := h.users[userId]
+	if !ok {
+		user = &userEvents{
+			subscribers: make(map[*Subscription]struct{}),
+			evicted:     h.startId,
+		}
+		h.users[userId] = user
+	}
+
+	return user
+}
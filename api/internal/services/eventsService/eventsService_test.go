@@ -0,0 +1,136 @@
+package eventsService
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	// Arrange
+	hub := NewHub(&logger.MockLogger{})
+	first, _ := hub.Subscribe(1, 0)
+	second, _ := hub.Subscribe(1, 0)
+	other, _ := hub.Subscribe(2, 0)
+
+	// Act
+	hub.Publish(1, HabitCreated, DeletedHabit{Id: 3})
+
+	// Assert
+	for _, subscription := range []*Subscription{first, second} {
+		event := <-subscription.Events()
+		assert.Equal(t, HabitCreated, event.Type)
+		assert.JSONEq(t, `{"id":3}`, string(event.Data))
+	}
+	assert.Empty(t, other.Events())
+}
+
+func TestSubscribeResumes(t *testing.T) {
+	hub := NewHub(&logger.MockLogger{})
+	hub.Publish(1, HabitCreated, DeletedHabit{Id: 1})
+	hub.Publish(2, HabitCreated, DeletedHabit{Id: 2})
+	hub.Publish(1, HabitUpdated, DeletedHabit{Id: 1})
+	hub.Publish(1, HabitDeleted, DeletedHabit{Id: 1})
+	firstId := hub.startId + 1
+
+	tests := []struct {
+		name          string
+		lastEventId   int64
+		expectedTypes []EventType
+	}{
+		{
+			name:          "sends nothing without a last event",
+			expectedTypes: []EventType{},
+		},
+		{
+			name:          "sends the user's events since the last event",
+			lastEventId:   firstId,
+			expectedTypes: []EventType{HabitUpdated, HabitDeleted},
+		},
+		{
+			name:          "sends nothing when up to date",
+			lastEventId:   hub.lastId,
+			expectedTypes: []EventType{},
+		},
+		{
+			name:          "resyncs after events from before the hub started",
+			lastEventId:   hub.startId - 10,
+			expectedTypes: []EventType{Resync},
+		},
+		{
+			name:          "resyncs after an unknown event",
+			lastEventId:   hub.lastId + 10,
+			expectedTypes: []EventType{Resync},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			subscription, missed := hub.Subscribe(1, tc.lastEventId)
+			defer subscription.Close()
+
+			// Assert
+			types := make([]EventType, 0)
+			for _, event := range missed {
+				types = append(types, event.Type)
+			}
+			assert.Equal(t, tc.expectedTypes, types)
+		})
+	}
+}
+
+func TestSubscribeAfterHistoryIsEvicted(t *testing.T) {
+	// Arrange
+	hub := NewHub(&logger.MockLogger{})
+	for i := range historySize + 1 {
+		hub.Publish(1, HabitUpdated, DeletedHabit{Id: int64(i)})
+	}
+
+	// Act
+	_, evicted := hub.Subscribe(1, hub.startId)
+	_, missed := hub.Subscribe(1, hub.startId+1)
+
+	// Assert
+	assert.Equal(t, []Event{{Type: Resync, Data: json.RawMessage("{}"), Id: hub.lastId}}, evicted)
+	assert.Len(t, missed, historySize)
+	assert.Equal(t, hub.startId+2, missed[0].Id)
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	// Arrange
+	hub := NewHub(&logger.MockLogger{})
+	subscription, _ := hub.Subscribe(1, 0)
+
+	// Act
+	for range subscriberBuffer + 1 {
+		hub.Publish(1, HabitUpdated, DeletedHabit{Id: 1})
+	}
+
+	// Assert
+	received := 0
+	for range subscription.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	subscription.Close()
+}
+
+func TestClose(t *testing.T) {
+	// Arrange
+	hub := NewHub(&logger.MockLogger{})
+	subscription, _ := hub.Subscribe(1, 0)
+
+	// Act
+	hub.Close()
+	hub.Publish(1, HabitUpdated, DeletedHabit{Id: 1})
+	late, _ := hub.Subscribe(1, 0)
+
+	// Assert
+	_, open := <-subscription.Events()
+	assert.False(t, open)
+	_, open = <-late.Events()
+	assert.False(t, open)
+}
@@ -0,0 +1,30 @@
+package eventsService
+
+import "encoding/json"
+
+type EventType string
+
+const (
+	HabitCreated EventType = "habit.created"
+	HabitUpdated EventType = "habit.updated"
+	HabitDeleted EventType = "habit.deleted"
+	EntryCreated EventType = "entry.created"
+	EntryUpdated EventType = "entry.updated"
+	EntryDeleted EventType = "entry.deleted"
+	// Resync tells a subscriber it has missed events, so anything it is showing
+	// has to be loaded again.
+	Resync EventType = "resync"
+)
+
+// Event is a change to one of a user's habits or entries. Data is the changed
+// habit or entry as JSON.
+type Event struct {
+	Type EventType       `json:"type"`
+	Data json.RawMessage `json:"data"`
+	Id   int64           `json:"id"`
+}
+
+// DeletedHabit is the data of a habit deleted event.
+type DeletedHabit struct {
+	Id int64 `json:"id"`
+}
@@ -0,0 +1,208 @@
+package exportService
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+type CSVLayout string
+
+const (
+	// CSVEntries has a row for each entry with the habit name, date and value.
+	CSVEntries CSVLayout = "entries"
+	// CSVCalendar has a row for each date and a column for each habit.
+	CSVCalendar CSVLayout = "calendar"
+)
+
+var csvEntriesHeader = []string{"habit", "date", "value"}
+
+// formulaPrefixes are the characters spreadsheets run a cell starting with as
+// a formula. Exported cells starting with one are escaped with a quote.
+const formulaPrefixes = "=+-@\t\r"
+
+// ExportCSV returns a user's entries as CSV records in the given layout,
+// starting with a header row. Cells that spreadsheets would run as a formula
+// are escaped.
+func (s ExportService) ExportCSV(userId int64, layout CSVLayout) ([][]string, error) {
+	if layout != CSVEntries && layout != CSVCalendar {
+		return nil, ErrInvalidLayout
+	}
+
+	export, err := s.Export(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if layout == CSVCalendar {
+		return escapeRecords(calendarRecords(export)), nil
+	}
+
+	return escapeRecords(entriesRecords(export)), nil
+}
+
+// ImportCSV imports CSV records in either layout, detected from the header
+// row. Habits are matched to existing habits by name or created.
+func (s ExportService) ImportCSV(userId int64, records [][]string, mode ImportMode, dryRun bool) (ImportResult, error) {
+	export, err := exportFromRecords(records)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return s.Import(userId, export, mode, dryRun)
+}
+
+func entriesRecords(export Export) [][]string {
+	records := [][]string{csvEntriesHeader}
+	for _, habit := range export.Habits {
+		for _, entry := range doneEntries(habit.Entries) {
+			records = append(records, []string{habit.Name, entry.Date, formatValue(entry.Value)})
+		}
+	}
+
+	return records
+}
+
+func calendarRecords(export Export) [][]string {
+	header := []string{"date"}
+	values := make(map[string][]string)
+	for i, habit := range export.Habits {
+		header = append(header, habit.Name)
+		for _, entry := range doneEntries(habit.Entries) {
+			if _, ok := values[entry.Date]; !ok {
+				values[entry.Date] = make([]string, len(export.Habits))
+			}
+			values[entry.Date][i] = formatValue(entry.Value)
+		}
+	}
+
+	dates := make([]string, 0, len(values))
+	for date := range values {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+
+	records := [][]string{header}
+	for _, date := range dates {
+		records = append(records, append([]string{date}, values[date]...))
+	}
+
+	return records
+}
+
+// exportFromRecords converts CSV records in either layout to an export of
+// active habits with the default schedule and target. Empty values are skipped
+// and cells escaped by ExportCSV are unescaped.
+func exportFromRecords(records [][]string) (Export, error) {
+	if len(records) == 0 {
+		return Export{}, fmt.Errorf("%w: missing header row", ErrInvalidExport)
+	}
+
+	header := make([]string, len(records[0]))
+	for i, column := range records[0] {
+		header[i] = strings.TrimSpace(unescapeCell(column))
+	}
+
+	export := Export{Version: ExportVersion, ExportedAt: time.Now().UTC()}
+	habitIndexes := make(map[string]int)
+	addEntry := func(line int, name string, date string, value string) error {
+		name = strings.TrimSpace(unescapeCell(name))
+		date = strings.TrimSpace(unescapeCell(date))
+		value = strings.TrimSpace(unescapeCell(value))
+		if value == "" {
+			return nil
+		}
+
+		parsedValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%w: row %d has invalid value %q", ErrInvalidExport, line, value)
+		}
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("%w: row %d has invalid date %q", ErrInvalidExport, line, date)
+		}
+
+		i, ok := habitIndexes[strings.ToLower(name)]
+		if !ok {
+			i = len(export.Habits)
+			habitIndexes[strings.ToLower(name)] = i
+			export.Habits = append(export.Habits, ExportedHabit{Name: name, Index: int64(i + 1), Active: true})
+		}
+		export.Habits[i].Entries = append(export.Habits[i].Entries, ExportedEntry{Date: date, Value: parsedValue})
+
+		return nil
+	}
+
+	if slices.EqualFunc(header, csvEntriesHeader, strings.EqualFold) {
+		for row, record := range records[1:] {
+			if len(record) != len(header) {
+				return Export{}, fmt.Errorf("%w: row %d has %d columns", ErrInvalidExport, row+2, len(record))
+			}
+			if err := addEntry(row+2, record[0], record[1], record[2]); err != nil {
+				return Export{}, err
+			}
+		}
+
+		return export, nil
+	}
+
+	if len(header) < 2 || !strings.EqualFold(header[0], "date") {
+		return Export{}, fmt.Errorf("%w: header must be habit,date,value or date followed by habit names", ErrInvalidExport)
+	}
+
+	for row, record := range records[1:] {
+		if len(record) != len(header) {
+			return Export{}, fmt.Errorf("%w: row %d has %d columns", ErrInvalidExport, row+2, len(record))
+		}
+		for column, value := range record[1:] {
+			if err := addEntry(row+2, header[column+1], record[0], value); err != nil {
+				return Export{}, err
+			}
+		}
+	}
+
+	return export, nil
+}
+
+// doneEntries returns the entries that are not skipped or failed, as CSV
+// only has room for values.
+func doneEntries(entries []ExportedEntry) []ExportedEntry {
+	done := make([]ExportedEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Status == "" || entry.Status == models.EntryDone {
+			done = append(done, entry)
+		}
+	}
+
+	return done
+}
+
+// escapeRecords quotes the cells of records that start like a formula, so
+// spreadsheets opening the CSV show them as text rather than running them.
+func escapeRecords(records [][]string) [][]string {
+	for _, record := range records {
+		for i, cell := range record {
+			if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+				record[i] = "'" + cell
+			}
+		}
+	}
+
+	return records
+}
+
+// unescapeCell removes the quote escapeRecords adds to a cell.
+func unescapeCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+
+	return cell
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
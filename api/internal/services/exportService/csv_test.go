@@ -0,0 +1,128 @@
+package exportService
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarRecords(t *testing.T) {
+	// Arrange
+	export := Export{Habits: []ExportedHabit{
+		{Name: "Read", Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}, {Date: "2024-11-03", Value: 1}}},
+		{Name: "Run", Entries: []ExportedEntry{{Date: "2024-11-01", Value: 2.5}}},
+	}}
+
+	// Act
+	records := calendarRecords(export)
+
+	// Assert
+	assert.Equal(t, [][]string{
+		{"date", "Read", "Run"},
+		{"2024-11-01", "1", "2.5"},
+		{"2024-11-03", "1", ""},
+	}, records)
+}
+
+func TestEscapeRecords(t *testing.T) {
+	// Arrange
+	export := Export{Habits: []ExportedHabit{
+		{Name: "=HYPERLINK(\"http://example.com\")", Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}}},
+		{Name: "@Run", Entries: []ExportedEntry{{Date: "2024-11-01", Value: 2}}},
+		{Name: "Read 'books'", Entries: []ExportedEntry{{Date: "2024-11-01", Value: 3}}},
+	}}
+
+	// Act
+	records := escapeRecords(entriesRecords(export))
+	imported, err := exportFromRecords(records)
+
+	// Assert
+	assert.Equal(t, [][]string{
+		{"habit", "date", "value"},
+		{"'=HYPERLINK(\"http://example.com\")", "2024-11-01", "1"},
+		{"'@Run", "2024-11-01", "2"},
+		{"Read 'books'", "2024-11-01", "3"},
+	}, records)
+	assert.NoError(t, err)
+	names := make([]string, len(imported.Habits))
+	for i, habit := range imported.Habits {
+		names[i] = habit.Name
+	}
+	assert.Equal(t, []string{"=HYPERLINK(\"http://example.com\")", "@Run", "Read 'books'"}, names)
+}
+
+func TestExportFromRecords(t *testing.T) {
+	tests := []struct {
+		name            string
+		records         [][]string
+		expectedHabits  []ExportedHabit
+		expectedInvalid bool
+	}{
+		{
+			name: "reads the entries layout",
+			records: [][]string{
+				{"Habit", "Date", "Value"},
+				{"Read", "2024-11-01", "1"},
+				{"read", "2024-11-02", "2"},
+			},
+			expectedHabits: []ExportedHabit{
+				{Name: "Read", Index: 1, Active: true, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}, {Date: "2024-11-02", Value: 2}}},
+			},
+		},
+		{
+			name: "reads the calendar layout skipping empty cells",
+			records: [][]string{
+				{"date", "Read", "Run"},
+				{"2024-11-01", "1", ""},
+				{"2024-11-02", "", "3"},
+			},
+			expectedHabits: []ExportedHabit{
+				{Name: "Read", Index: 1, Active: true, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}}},
+				{Name: "Run", Index: 2, Active: true, Entries: []ExportedEntry{{Date: "2024-11-02", Value: 3}}},
+			},
+		},
+		{
+			name: "unescapes habit names starting like a formula",
+			records: [][]string{
+				{"date", "'+Read", "'Run"},
+				{"2024-11-01", "1", "2"},
+			},
+			expectedHabits: []ExportedHabit{
+				{Name: "+Read", Index: 1, Active: true, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}}},
+				{Name: "'Run", Index: 2, Active: true, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 2}}},
+			},
+		},
+		{
+			name: "rejects an invalid value",
+			records: [][]string{
+				{"date", "Read"},
+				{"2024-11-01", "yes"},
+			},
+			expectedInvalid: true,
+		},
+		{
+			name: "rejects an unknown header",
+			records: [][]string{
+				{"when", "Read"},
+			},
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			export, err := exportFromRecords(tc.records)
+
+			// Assert
+			if tc.expectedInvalid {
+				assert.ErrorIs(t, err, ErrInvalidExport)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, ExportVersion, export.Version)
+			assert.Equal(t, tc.expectedHabits, export.Habits)
+		})
+	}
+}
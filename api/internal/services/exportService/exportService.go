@@ -0,0 +1,500 @@
+package exportService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported export version")
+	ErrInvalidMode        = errors.New("mode must be merge or replace")
+	ErrInvalidExport      = errors.New("invalid export")
+	ErrInvalidLayout      = errors.New("layout must be entries or calendar")
+
+	// errDryRun rolls back the transaction of a dry run import.
+	errDryRun = errors.New("dry run")
+)
+
+type ExportStorage interface {
+	GetUserByID(ctx context.Context, id int64) (repository.User, error)
+	GetHabits(ctx context.Context, userID int64) ([]repository.Habit, error)
+	GetCategories(ctx context.Context, userID int64) ([]repository.Category, error)
+	GetUserHabitTags(ctx context.Context, userID int64) ([]repository.GetUserHabitTagsRow, error)
+}
+
+// ImportStorage is the storage an import writes to, within a transaction.
+type ImportStorage interface {
+	GetHabits(ctx context.Context, userID int64) ([]repository.Habit, error)
+	CreateHabit(ctx context.Context, arg repository.CreateHabitParams) (repository.Habit, error)
+	TrashHabit(ctx context.Context, arg repository.TrashHabitParams) (repository.Habit, error)
+	GetCategories(ctx context.Context, userID int64) ([]repository.Category, error)
+	CreateCategory(ctx context.Context, arg repository.CreateCategoryParams) (repository.Category, error)
+	GetTags(ctx context.Context, userID int64) ([]repository.Tag, error)
+	CreateTag(ctx context.Context, arg repository.CreateTagParams) (repository.Tag, error)
+	AttachHabitTag(ctx context.Context, arg repository.AttachHabitTagParams) (int64, error)
+	ImportHabitEntry(ctx context.Context, arg repository.ImportHabitEntryParams) (repository.HabitEntry, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
+}
+
+type HabitEntryStore interface {
+	GetUserHabitEntries(userId int64, dateRange models.DateRange) (map[int64][]models.HabitEntry, error)
+}
+
+// EventPublisher tells a user's other devices about changes to their habits.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
+}
+
+type ExportService struct {
+	storage         ExportStorage
+	transactor      Transactor
+	logger          logger.Logger
+	habitEntryStore HabitEntryStore
+	events          EventPublisher
+}
+
+func NewExportService(storage ExportStorage, transactor Transactor, logger logger.Logger, habitEntryStore HabitEntryStore, events EventPublisher) *ExportService {
+	return &ExportService{
+		storage:         storage,
+		transactor:      transactor,
+		logger:          logger,
+		habitEntryStore: habitEntryStore,
+		events:          events,
+	}
+}
+
+func (s ExportService) Export(userId int64) (Export, error) {
+	ctx := context.Background()
+	user, err := s.storage.GetUserByID(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	habits, err := s.storage.GetHabits(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+	sort.Slice(habits, func(i, j int) bool {
+		return habits[i].Index < habits[j].Index
+	})
+
+	habitEntries, err := s.habitEntryStore.GetUserHabitEntries(userId, models.DateRange{})
+	if err != nil {
+		return Export{}, err
+	}
+
+	categories, err := s.storage.GetCategories(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	rawHabitTags, err := s.storage.GetUserHabitTags(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+	habitTags := make(map[int64][]string)
+	for _, habitTag := range rawHabitTags {
+		habitTags[habitTag.HabitID] = append(habitTags[habitTag.HabitID], habitTag.Name)
+	}
+
+	export := Export{
+		ExportedAt: time.Now().UTC(),
+		User:       ExportedUser{Name: user.Name},
+		Categories: make([]ExportedCategory, len(categories)),
+		Habits:     make([]ExportedHabit, len(habits)),
+		Version:    ExportVersion,
+	}
+	categoryNames := make(map[int64]string, len(categories))
+	for i, category := range categories {
+		export.Categories[i] = ExportedCategory{Name: category.Name, Colour: category.Colour}
+		categoryNames[category.ID] = category.Name
+	}
+	for i, habit := range habits {
+		entries := make([]ExportedEntry, len(habitEntries[habit.ID]))
+		for j, entry := range habitEntries[habit.ID] {
+			entries[j] = ExportedEntry{
+				Date:  entry.Date.Format(time.DateOnly),
+				Note:  entry.Note,
+				Value: entry.Value,
+			}
+			if entry.Status != models.EntryDone {
+				entries[j].Status = entry.Status
+			}
+		}
+
+		export.Habits[i] = ExportedHabit{
+			Name:        habit.Name,
+			Description: habit.Description.String,
+			Icon:        habit.Icon,
+			Category:    categoryNames[habit.CategoryID.Int64],
+			Colour:      habit.Colour,
+			Tags:        habitTags[habit.ID],
+			Entries:     entries,
+			Schedule:    models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes),
+			Target:      models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison),
+			Index:       habit.Index,
+			Active:      habit.Active,
+			Archived:    habit.ArchivedAt.Valid,
+		}
+	}
+
+	return export, nil
+}
+
+// Import adds the habits and entries of an export to a user's habits in a
+// single transaction, so nothing is imported if any of it fails. Entries for
+// days a habit already has an entry for are skipped and reported as conflicts.
+// A dry run reports what would change and then rolls back. Anything else tells
+// the user's other devices to load their habits again.
+func (s ExportService) Import(userId int64, export Export, mode ImportMode, dryRun bool) (ImportResult, error) {
+	ctx := context.Background()
+	if mode != ImportMerge && mode != ImportReplace {
+		return ImportResult{}, ErrInvalidMode
+	}
+
+	err := validateExport(&export)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var result ImportResult
+	err = s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		result, err = importHabits(ctx, queries, userId, export, mode)
+		if err == nil && dryRun {
+			return errDryRun
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		s.logger.Error("Failed to import habits", slog.Any("error", err))
+		return ImportResult{}, err
+	}
+
+	result.DryRun = dryRun
+	if !dryRun {
+		s.events.Publish(userId, eventsService.Resync, struct{}{})
+	}
+	return result, nil
+}
+
+// validateExport checks the export can be imported, defaulting any habits
+// without a schedule or target.
+func validateExport(export *Export) error {
+	if export.Version != ExportVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, export.Version)
+	}
+
+	for i := range export.Habits {
+		habit := &export.Habits[i]
+		if strings.TrimSpace(habit.Name) == "" {
+			return fmt.Errorf("%w: habit %d has no name", ErrInvalidExport, i+1)
+		}
+
+		if habit.Schedule.IsZero() {
+			habit.Schedule = models.NewDailySchedule()
+		}
+		if err := habit.Schedule.Validate(); err != nil {
+			return fmt.Errorf("%w: habit %q schedule: %v", ErrInvalidExport, habit.Name, err)
+		}
+
+		if habit.Target.IsZero() {
+			habit.Target = models.NewDefaultTarget()
+		}
+		if err := habit.Target.Validate(); err != nil {
+			return fmt.Errorf("%w: habit %q target: %v", ErrInvalidExport, habit.Name, err)
+		}
+
+		for j := range habit.Entries {
+			entry := &habit.Entries[j]
+			if _, err := time.Parse(time.DateOnly, entry.Date); err != nil {
+				return fmt.Errorf("%w: habit %q entry date %q", ErrInvalidExport, habit.Name, entry.Date)
+			}
+			if entry.Value < 0 {
+				return fmt.Errorf("%w: habit %q entry %s: value must not be negative", ErrInvalidExport, habit.Name, entry.Date)
+			}
+
+			if entry.Status == "" {
+				entry.Status = models.EntryDone
+			}
+			if err := entry.Status.Validate(); err != nil {
+				return fmt.Errorf("%w: habit %q entry %s: %v", ErrInvalidExport, habit.Name, entry.Date, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func importHabits(ctx context.Context, storage ImportStorage, userId int64, export Export, mode ImportMode) (ImportResult, error) {
+	result := ImportResult{Conflicts: make([]ImportConflict, 0)}
+	if mode == ImportReplace {
+		err := trashHabits(ctx, storage, userId)
+		if err != nil {
+			return ImportResult{}, err
+		}
+	}
+
+	existingHabits, err := storage.GetHabits(ctx, userId)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	categoryIds, err := importCategories(ctx, storage, userId, export, &result)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	tagIds, err := importTags(ctx, storage, userId, export, &result)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	habitIds := make(map[string]int64, len(existingHabits))
+	var highestIndex int64 = 0
+	for _, habit := range existingHabits {
+		habitIds[strings.ToLower(habit.Name)] = habit.ID
+		highestIndex = max(highestIndex, habit.Index)
+	}
+
+	for _, habit := range export.Habits {
+		name := strings.TrimSpace(habit.Name)
+		habitId, exists := habitIds[strings.ToLower(name)]
+		if exists {
+			result.HabitsMerged++
+		} else {
+			index := habit.Index
+			if mode == ImportMerge {
+				highestIndex++
+				index = highestIndex
+			}
+			var archivedAt sql.NullString
+			if habit.Archived {
+				archivedAt = sql.NullString{String: time.Now().UTC().Format(time.DateTime), Valid: true}
+			}
+
+			params := repository.CreateHabitParams{
+				UserID:           userId,
+				Name:             name,
+				Description:      sql.NullString{String: habit.Description, Valid: habit.Description != ""},
+				Icon:             strings.TrimSpace(habit.Icon),
+				Colour:           habit.Colour,
+				Index:            index,
+				Active:           habit.Active,
+				ScheduleType:     string(habit.Schedule.Type),
+				ScheduleCount:    habit.Schedule.Count,
+				ScheduleWeekdays: habit.Schedule.WeekdayMask(),
+				ScheduleFreezes:  habit.Schedule.Freezes(),
+				TargetValue:      habit.Target.Value,
+				TargetUnit:       habit.Target.Unit,
+				TargetComparison: string(habit.Target.Comparison),
+				ArchivedAt:       archivedAt,
+			}
+			if categoryId, ok := categoryIds[strings.ToLower(strings.TrimSpace(habit.Category))]; ok {
+				params.CategoryID = sql.NullInt64{Int64: categoryId, Valid: true}
+			}
+			createdHabit, err := storage.CreateHabit(ctx, params)
+			if err != nil {
+				return ImportResult{}, err
+			}
+
+			err = recordHabitChange(ctx, storage, auditService.ActionCreate, userId, createdHabit.ID, nil, habitsService.NewHabitFromStorage(createdHabit, nil))
+			if err != nil {
+				return ImportResult{}, err
+			}
+
+			habitId = createdHabit.ID
+			habitIds[strings.ToLower(name)] = habitId
+			result.HabitsCreated++
+		}
+
+		for _, tag := range habit.Tags {
+			tagId, ok := tagIds[strings.ToLower(strings.TrimSpace(tag))]
+			if !ok {
+				continue
+			}
+
+			_, err := storage.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: habitId, TagID: tagId})
+			if err != nil {
+				return ImportResult{}, err
+			}
+		}
+
+		for _, entry := range habit.Entries {
+			imported, err := storage.ImportHabitEntry(ctx, repository.ImportHabitEntryParams{
+				HabitID: habitId,
+				Date:    entry.Date,
+				Value:   entry.Value,
+				Note:    strings.TrimSpace(entry.Note),
+				Status:  string(entry.Status),
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				result.Conflicts = append(result.Conflicts, ImportConflict{
+					Habit: name,
+					Date:  entry.Date,
+					Value: entry.Value,
+				})
+				continue
+			}
+			if err != nil {
+				return ImportResult{}, err
+			}
+
+			err = recordImportedEntry(ctx, storage, userId, imported)
+			if err != nil {
+				return ImportResult{}, err
+			}
+			result.EntriesImported++
+		}
+	}
+
+	return result, nil
+}
+
+// trashHabits moves all of a user's habits and their entries to the trash,
+// where they can be restored from until the trash is purged.
+func trashHabits(ctx context.Context, storage ImportStorage, userId int64) error {
+	habits, err := storage.GetHabits(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now().UTC().Format(time.DateTime)
+	for _, habit := range habits {
+		_, err := storage.TrashHabit(ctx, repository.TrashHabitParams{
+			DeletedAt: sql.NullString{String: deletedAt, Valid: true},
+			UpdatedAt: deletedAt,
+			ID:        habit.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordHabitChange(ctx, storage, auditService.ActionDelete, userId, habit.ID, habitsService.NewHabitFromStorage(habit, nil), nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordHabitChange records a habit created or trashed by an import in the
+// audit log. before is nil for creates and after is nil for deletes.
+func recordHabitChange(ctx context.Context, storage ImportStorage, action string, userId int64, habitId int64, before any, after any) error {
+	return auditService.Record(ctx, storage, auditService.Change{
+		Before:     before,
+		After:      after,
+		EntityType: auditService.EntityHabit,
+		Action:     action,
+		UserId:     userId,
+		EntityId:   habitId,
+	})
+}
+
+// recordImportedEntry records a habit entry created by an import in the audit log.
+func recordImportedEntry(ctx context.Context, writer auditService.AuditWriter, userId int64, imported repository.HabitEntry) error {
+	habitEntry, err := storage.NewHabitEntryFromStorage(imported)
+	if err != nil {
+		return err
+	}
+
+	return auditService.Record(ctx, writer, auditService.Change{
+		After:      habitEntry,
+		EntityType: auditService.EntityHabitEntry,
+		Action:     auditService.ActionCreate,
+		UserId:     userId,
+		EntityId:   habitEntry.Id,
+	})
+}
+
+// importCategories creates the export's categories the user doesn't already
+// have, including any only named by habits, returning the IDs of the user's
+// categories by lower case name.
+func importCategories(ctx context.Context, storage ImportStorage, userId int64, export Export, result *ImportResult) (map[string]int64, error) {
+	existingCategories, err := storage.GetCategories(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIds := make(map[string]int64, len(existingCategories))
+	for _, category := range existingCategories {
+		categoryIds[strings.ToLower(category.Name)] = category.ID
+	}
+
+	categories := append([]ExportedCategory{}, export.Categories...)
+	for _, habit := range export.Habits {
+		categories = append(categories, ExportedCategory{Name: habit.Category, Colour: models.DefaultColour})
+	}
+
+	for _, category := range categories {
+		name := strings.TrimSpace(category.Name)
+		if _, exists := categoryIds[strings.ToLower(name)]; exists || name == "" {
+			continue
+		}
+
+		colour := strings.TrimSpace(category.Colour)
+		if colour == "" {
+			colour = models.DefaultColour
+		}
+		created, err := storage.CreateCategory(ctx, repository.CreateCategoryParams{UserID: userId, Name: name, Colour: colour})
+		if err != nil {
+			return nil, err
+		}
+
+		categoryIds[strings.ToLower(name)] = created.ID
+		result.CategoriesCreated++
+	}
+
+	return categoryIds, nil
+}
+
+// importTags creates the tags named by the export's habits that the user
+// doesn't already have, returning the IDs of the user's tags by lower case name.
+func importTags(ctx context.Context, storage ImportStorage, userId int64, export Export, result *ImportResult) (map[string]int64, error) {
+	existingTags, err := storage.GetTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make(map[string]int64, len(existingTags))
+	for _, tag := range existingTags {
+		tagIds[strings.ToLower(tag.Name)] = tag.ID
+	}
+
+	for _, habit := range export.Habits {
+		for _, tag := range habit.Tags {
+			name := strings.TrimSpace(tag)
+			if _, exists := tagIds[strings.ToLower(name)]; exists || name == "" {
+				continue
+			}
+
+			created, err := storage.CreateTag(ctx, repository.CreateTagParams{UserID: userId, Name: name})
+			if err != nil {
+				return nil, err
+			}
+
+			tagIds[strings.ToLower(name)] = created.ID
+			result.TagsCreated++
+		}
+	}
+
+	return tagIds, nil
+}
@@ -0,0 +1,338 @@
+package exportService
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockImportStorage struct {
+	habits      []repository.Habit
+	categories  []repository.Category
+	tags        []repository.Tag
+	habitTags   []repository.HabitTag
+	entries     map[int64]map[string]float64
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func newMockImportStorage(habits ...repository.Habit) *mockImportStorage {
+	m := &mockImportStorage{habits: habits, entries: map[int64]map[string]float64{}}
+	for _, habit := range habits {
+		m.entries[habit.ID] = map[string]float64{}
+	}
+
+	return m
+}
+
+func (m *mockImportStorage) GetHabits(_ context.Context, _ int64) ([]repository.Habit, error) {
+	var habits []repository.Habit
+	for _, habit := range m.habits {
+		if !habit.DeletedAt.Valid {
+			habits = append(habits, habit)
+		}
+	}
+
+	return habits, nil
+}
+
+func (m *mockImportStorage) CreateHabit(_ context.Context, arg repository.CreateHabitParams) (repository.Habit, error) {
+	habit := repository.Habit{ID: int64(len(m.habits) + 100), UserID: arg.UserID, Name: arg.Name, Index: arg.Index, Active: arg.Active, CategoryID: arg.CategoryID}
+	m.habits = append(m.habits, habit)
+	m.entries[habit.ID] = map[string]float64{}
+	return habit, nil
+}
+
+func (m *mockImportStorage) TrashHabit(_ context.Context, arg repository.TrashHabitParams) (repository.Habit, error) {
+	for i, habit := range m.habits {
+		if habit.ID == arg.ID && !habit.DeletedAt.Valid {
+			m.habits[i].DeletedAt = arg.DeletedAt
+			return m.habits[i], nil
+		}
+	}
+
+	return repository.Habit{}, sql.ErrNoRows
+}
+
+func (m *mockImportStorage) GetCategories(_ context.Context, _ int64) ([]repository.Category, error) {
+	return m.categories, nil
+}
+
+func (m *mockImportStorage) CreateCategory(_ context.Context, arg repository.CreateCategoryParams) (repository.Category, error) {
+	category := repository.Category{ID: int64(len(m.categories) + 1), UserID: arg.UserID, Name: arg.Name, Colour: arg.Colour}
+	m.categories = append(m.categories, category)
+	return category, nil
+}
+
+func (m *mockImportStorage) GetTags(_ context.Context, _ int64) ([]repository.Tag, error) {
+	return m.tags, nil
+}
+
+func (m *mockImportStorage) CreateTag(_ context.Context, arg repository.CreateTagParams) (repository.Tag, error) {
+	tag := repository.Tag{ID: int64(len(m.tags) + 1), UserID: arg.UserID, Name: arg.Name}
+	m.tags = append(m.tags, tag)
+	return tag, nil
+}
+
+func (m *mockImportStorage) AttachHabitTag(_ context.Context, arg repository.AttachHabitTagParams) (int64, error) {
+	for _, habitTag := range m.habitTags {
+		if habitTag.HabitID == arg.HabitID && habitTag.TagID == arg.TagID {
+			return 0, nil
+		}
+	}
+
+	m.habitTags = append(m.habitTags, repository.HabitTag{HabitID: arg.HabitID, TagID: arg.TagID})
+	return 1, nil
+}
+
+func (m *mockImportStorage) ImportHabitEntry(_ context.Context, arg repository.ImportHabitEntryParams) (repository.HabitEntry, error) {
+	if _, exists := m.entries[arg.HabitID][arg.Date]; exists {
+		return repository.HabitEntry{}, sql.ErrNoRows
+	}
+
+	m.entries[arg.HabitID][arg.Date] = arg.Value
+	return repository.HabitEntry{ID: arg.HabitID*1000 + int64(len(m.entries[arg.HabitID])), HabitID: arg.HabitID, Date: arg.Date, Value: arg.Value, Status: arg.Status}, nil
+}
+
+func (m *mockImportStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+func TestImportHabits(t *testing.T) {
+	export := Export{
+		Version: ExportVersion,
+		Habits: []ExportedHabit{
+			{Name: "Read", Index: 1, Active: true, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 1}, {Date: "2024-11-02", Value: 1}}},
+			{Name: "Run", Index: 2, Active: false, Entries: []ExportedEntry{{Date: "2024-11-01", Value: 5}}},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		existingHabits    []repository.Habit
+		existingEntries   map[string]float64
+		mode              ImportMode
+		expectedCreated   int
+		expectedMerged    int
+		expectedImported  int
+		expectedConflicts []ImportConflict
+		expectedAudited   []string
+		expectedHabits    int
+		expectedTrashed   int
+	}{
+		{
+			name:              "merges entries into a habit with the same name",
+			existingHabits:    []repository.Habit{{ID: 1, Name: "read", Index: 4}},
+			existingEntries:   map[string]float64{"2024-11-02": 1},
+			mode:              ImportMerge,
+			expectedCreated:   1,
+			expectedMerged:    1,
+			expectedImported:  2,
+			expectedConflicts: []ImportConflict{{Habit: "Read", Date: "2024-11-02", Value: 1}},
+			expectedAudited:   []string{"habitEntry create", "habit create", "habitEntry create"},
+			expectedHabits:    2,
+		},
+		{
+			name:              "replaces existing habits",
+			existingHabits:    []repository.Habit{{ID: 1, Name: "Read", Index: 4}},
+			existingEntries:   map[string]float64{"2024-11-02": 1},
+			mode:              ImportReplace,
+			expectedCreated:   2,
+			expectedImported:  3,
+			expectedConflicts: []ImportConflict{},
+			expectedAudited:   []string{"habit delete", "habit create", "habitEntry create", "habitEntry create", "habit create", "habitEntry create"},
+			expectedHabits:    2,
+			expectedTrashed:   1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockImportStorage(tc.existingHabits...)
+			for date, value := range tc.existingEntries {
+				storage.entries[1][date] = value
+			}
+
+			// Act
+			result, err := importHabits(context.Background(), storage, 1, export, tc.mode)
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+
+			assert.Equal(t, tc.expectedCreated, result.HabitsCreated)
+			assert.Equal(t, tc.expectedMerged, result.HabitsMerged)
+			assert.Equal(t, tc.expectedImported, result.EntriesImported)
+			assert.Equal(t, tc.expectedConflicts, result.Conflicts)
+			habits, _ := storage.GetHabits(context.Background(), 1)
+			assert.Len(t, habits, tc.expectedHabits)
+			assert.Len(t, storage.habits, tc.expectedHabits+tc.expectedTrashed)
+			audited := make([]string, len(storage.auditEvents))
+			for i, event := range storage.auditEvents {
+				audited[i] = event.EntityType + " " + event.Action
+			}
+			assert.Equal(t, tc.expectedAudited, audited)
+		})
+	}
+}
+
+func TestReplacedHabitsCanBeRestored(t *testing.T) {
+	// Arrange
+	db, err := storage.NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.ApplyMigrations())
+
+	ctx := context.Background()
+	user, err := db.Queries.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{UserID: user.ID, Name: "Read", Colour: "red", Active: true, ScheduleType: "daily"})
+	require.NoError(t, err)
+	_, err = db.Queries.ImportHabitEntry(ctx, repository.ImportHabitEntryParams{HabitID: habit.ID, Date: "2024-11-01", Value: 1, Status: "done"})
+	require.NoError(t, err)
+	export := Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Run", Active: true}}}
+
+	// Act
+	err = db.Transaction(ctx, func(queries repository.Querier) error {
+		_, err := importHabits(ctx, queries, user.ID, export, ImportReplace)
+		return err
+	})
+	require.NoError(t, err)
+	audited, auditedErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.ID, Limit: 10})
+	replaced, replacedErr := db.Queries.GetHabits(ctx, user.ID)
+	_, restoreErr := db.Queries.RestoreHabit(ctx, repository.RestoreHabitParams{UpdatedAt: "2024-12-30 10:00:00", ID: habit.ID, UserID: user.ID})
+	restored, restoredErr := db.Queries.GetHabits(ctx, user.ID)
+	entries, entriesErr := db.Queries.GetHabitEntries(ctx, habit.ID)
+
+	// Assert
+	assert.NoError(t, replacedErr)
+	require.Len(t, replaced, 1)
+	assert.Equal(t, "Run", replaced[0].Name)
+	assert.NoError(t, auditedErr)
+	require.Len(t, audited, 2)
+	assert.Equal(t, auditService.ActionCreate, audited[0].Action)
+	assert.Equal(t, replaced[0].ID, audited[0].EntityID)
+	assert.Equal(t, auditService.ActionDelete, audited[1].Action)
+	assert.Equal(t, habit.ID, audited[1].EntityID)
+	assert.NoError(t, restoreErr)
+	assert.NoError(t, restoredErr)
+	assert.Len(t, restored, 2)
+	assert.NoError(t, entriesErr)
+	assert.Len(t, entries, 1)
+}
+
+func TestImportCategories(t *testing.T) {
+	// Arrange
+	storage := newMockImportStorage()
+	storage.categories = []repository.Category{{ID: 1, UserID: 1, Name: "Health"}}
+	export := Export{
+		Version:    ExportVersion,
+		Categories: []ExportedCategory{{Name: "health", Colour: "#16a34a"}, {Name: "Work", Colour: "#dc2626"}},
+		Habits: []ExportedHabit{
+			{Name: "Run", Category: "Health"},
+			{Name: "Read", Category: "Learning"},
+			{Name: "Nap"},
+		},
+	}
+
+	// Act
+	result, err := importHabits(context.Background(), storage, 1, export, ImportMerge)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.CategoriesCreated)
+	assert.Equal(t, []repository.Category{
+		{ID: 1, UserID: 1, Name: "Health"},
+		{ID: 2, UserID: 1, Name: "Work", Colour: "#dc2626"},
+		{ID: 3, UserID: 1, Name: "Learning", Colour: models.DefaultColour},
+	}, storage.categories)
+	assert.Equal(t, int64(1), storage.habits[0].CategoryID.Int64)
+	assert.Equal(t, int64(3), storage.habits[1].CategoryID.Int64)
+	assert.False(t, storage.habits[2].CategoryID.Valid)
+}
+
+func TestImportTags(t *testing.T) {
+	// Arrange
+	storage := newMockImportStorage(repository.Habit{ID: 1, UserID: 1, Name: "Run"})
+	storage.tags = []repository.Tag{{ID: 1, UserID: 1, Name: "morning"}}
+	storage.habitTags = []repository.HabitTag{{HabitID: 1, TagID: 1}}
+	export := Export{
+		Version: ExportVersion,
+		Habits: []ExportedHabit{
+			{Name: "Run", Tags: []string{"Morning", "health"}},
+			{Name: "Stretch", Tags: []string{"health", " "}},
+		},
+	}
+
+	// Act
+	result, err := importHabits(context.Background(), storage, 1, export, ImportMerge)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.TagsCreated)
+	assert.Equal(t, []repository.Tag{{ID: 1, UserID: 1, Name: "morning"}, {ID: 2, UserID: 1, Name: "health"}}, storage.tags)
+	assert.Equal(t, []repository.HabitTag{{HabitID: 1, TagID: 1}, {HabitID: 1, TagID: 2}, {HabitID: 101, TagID: 2}}, storage.habitTags)
+}
+
+func TestValidateExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		export      Export
+		expectedErr error
+	}{
+		{
+			name:   "defaults a missing schedule and target",
+			export: Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Read"}}},
+		},
+		{
+			name:        "rejects an unsupported version",
+			export:      Export{Version: ExportVersion + 1},
+			expectedErr: ErrUnsupportedVersion,
+		},
+		{
+			name:        "rejects an invalid entry date",
+			export:      Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Read", Entries: []ExportedEntry{{Date: "01/11/2024"}}}}},
+			expectedErr: ErrInvalidExport,
+		},
+		{
+			name:        "rejects a negative entry value",
+			export:      Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Read", Entries: []ExportedEntry{{Date: "2024-11-01", Value: -1}}}}},
+			expectedErr: ErrInvalidExport,
+		},
+		{
+			name:        "rejects an unknown entry status",
+			export:      Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Read", Entries: []ExportedEntry{{Date: "2024-11-01", Status: "paused"}}}}},
+			expectedErr: ErrInvalidExport,
+		},
+		{
+			name:        "rejects an invalid schedule",
+			export:      Export{Version: ExportVersion, Habits: []ExportedHabit{{Name: "Read", Schedule: models.Schedule{Type: "hourly"}}}},
+			expectedErr: ErrInvalidExport,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := validateExport(&tc.export)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, models.NewDailySchedule(), tc.export.Habits[0].Schedule)
+				assert.Equal(t, models.NewDefaultTarget(), tc.export.Habits[0].Target)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package exportService
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+var ErrInvalidLoopBackup = errors.New("not a Loop Habit Tracker backup")
+
+// loopColours are the hex colours of Loop Habit Tracker's palette, indexed by
+// the colour number it stores.
+var loopColours = []string{
+	"#D32F2F", "#E64A19", "#F57C00", "#FF8F00", "#F9A825",
+	"#AFB42B", "#7CB342", "#388E3C", "#00897B", "#00ACC1",
+	"#039BE5", "#1976D2", "#303F9F", "#5E35B1", "#8E24AA",
+	"#D81B60", "#5D4037", "#303030", "#757575", "#AAAAAA",
+}
+
+// Loop Habit Tracker repetition values for yes or no habits. Numerical habits
+// store the value multiplied by 1000.
+const (
+	loopUnknown   = -1
+	loopNo        = 0
+	loopYesAuto   = 1
+	loopYesManual = 2
+	loopSkip      = 3
+
+	loopNumerical  = 1
+	loopTargetMost = 1
+)
+
+// LoopRecord is a record in a Loop Habit Tracker backup that was skipped or
+// could not be mapped exactly.
+type LoopRecord struct {
+	Table  string `json:"table"`
+	Reason string `json:"reason"`
+	Id     int64  `json:"id"`
+}
+
+type LoopImportResult struct {
+	ImportResult
+	Skipped  []LoopRecord `json:"skipped"`
+	Unmapped []LoopRecord `json:"unmapped"`
+}
+
+type loopHabit struct {
+	Name        string
+	Description string
+	Unit        string
+	TargetValue float64
+	Id          int64
+	Colour      int64
+	FreqNum     int64
+	FreqDen     int64
+	Position    int64
+	Type        int64
+	TargetType  int64
+	Archived    bool
+}
+
+type loopRepetition struct {
+	Id        int64
+	HabitId   int64
+	Timestamp int64
+	Value     int64
+}
+
+// ImportLoop imports the habits and repetitions of a Loop Habit Tracker
+// backup file.
+func (s ExportService) ImportLoop(userId int64, path string, mode ImportMode, dryRun bool) (LoopImportResult, error) {
+	habits, repetitions, err := readLoopBackup(path)
+	if err != nil {
+		return LoopImportResult{}, err
+	}
+
+	export, skipped, unmapped := exportFromLoop(habits, repetitions)
+	result, err := s.Import(userId, export, mode, dryRun)
+	if err != nil {
+		return LoopImportResult{}, err
+	}
+
+	return LoopImportResult{
+		ImportResult: result,
+		Skipped:      skipped,
+		Unmapped:     unmapped,
+	}, nil
+}
+
+func readLoopBackup(path string) ([]loopHabit, []loopRepetition, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+
+	var tables int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('Habits', 'Repetitions')").Scan(&tables)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidLoopBackup, err)
+	}
+	if tables != 2 {
+		return nil, nil, ErrInvalidLoopBackup
+	}
+
+	// Older versions of Loop do not have every column, so rows are read by
+	// column name with defaults for any that are missing.
+	habitRows, err := readRows(db, "SELECT * FROM Habits ORDER BY position")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	habits := make([]loopHabit, len(habitRows))
+	for i, row := range habitRows {
+		habits[i] = loopHabit{
+			Name:        rowString(row, "name"),
+			Description: rowString(row, "description"),
+			Unit:        rowString(row, "unit"),
+			TargetValue: rowFloat(row, "target_value"),
+			Id:          rowInt(row, "id"),
+			Colour:      rowInt(row, "color"),
+			FreqNum:     rowInt(row, "freq_num"),
+			FreqDen:     rowInt(row, "freq_den"),
+			Position:    rowInt(row, "position"),
+			Type:        rowInt(row, "type"),
+			TargetType:  rowInt(row, "target_type"),
+			Archived:    rowInt(row, "archived") != 0,
+		}
+	}
+
+	repetitionRows, err := readRows(db, "SELECT * FROM Repetitions ORDER BY habit, timestamp")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repetitions := make([]loopRepetition, len(repetitionRows))
+	for i, row := range repetitionRows {
+		repetitions[i] = loopRepetition{
+			Id:        rowInt(row, "id"),
+			HabitId:   rowInt(row, "habit"),
+			Timestamp: rowInt(row, "timestamp"),
+			Value:     rowInt(row, "value"),
+		}
+	}
+
+	return habits, repetitions, nil
+}
+
+// exportFromLoop maps Loop habits and repetitions to an export, returning the
+// records that were skipped and those that were imported with changes.
+func exportFromLoop(habits []loopHabit, repetitions []loopRepetition) (Export, []LoopRecord, []LoopRecord) {
+	skipped := make([]LoopRecord, 0)
+	unmapped := make([]LoopRecord, 0)
+	export := Export{Version: ExportVersion, ExportedAt: time.Now().UTC(), Habits: make([]ExportedHabit, 0, len(habits))}
+
+	habitIndexes := make(map[int64]int)
+	numerical := make(map[int64]bool)
+	for _, habit := range habits {
+		if habit.Name == "" {
+			skipped = append(skipped, LoopRecord{Table: "Habits", Id: habit.Id, Reason: "habit has no name"})
+			continue
+		}
+
+		schedule, ok := loopSchedule(habit.FreqNum, habit.FreqDen)
+		if !ok {
+			unmapped = append(unmapped, LoopRecord{Table: "Habits", Id: habit.Id, Reason: fmt.Sprintf("frequency %d/%d is not supported, imported as daily", habit.FreqNum, habit.FreqDen)})
+		}
+
+		colour := ""
+		if habit.Colour >= 0 && habit.Colour < int64(len(loopColours)) {
+			colour = loopColours[habit.Colour]
+		} else {
+			unmapped = append(unmapped, LoopRecord{Table: "Habits", Id: habit.Id, Reason: fmt.Sprintf("colour %d is not in the palette", habit.Colour)})
+		}
+
+		target := models.NewDefaultTarget()
+		if habit.Type == loopNumerical {
+			numerical[habit.Id] = true
+			target = models.NewTarget(habit.TargetValue, habit.Unit, string(models.TargetAtLeast))
+			if habit.TargetType == loopTargetMost {
+				target.Comparison = models.TargetAtMost
+			}
+			if target.Validate() != nil {
+				unmapped = append(unmapped, LoopRecord{Table: "Habits", Id: habit.Id, Reason: fmt.Sprintf("target %v is not valid, imported as at least 1", habit.TargetValue)})
+				target = models.NewDefaultTarget()
+				target.Unit = habit.Unit
+			}
+		}
+
+		habitIndexes[habit.Id] = len(export.Habits)
+		export.Habits = append(export.Habits, ExportedHabit{
+			Name:        habit.Name,
+			Description: habit.Description,
+			Colour:      colour,
+			Entries:     make([]ExportedEntry, 0),
+			Schedule:    schedule,
+			Target:      target,
+			Index:       int64(len(export.Habits) + 1),
+			Active:      true,
+			Archived:    habit.Archived,
+		})
+	}
+
+	for _, repetition := range repetitions {
+		i, ok := habitIndexes[repetition.HabitId]
+		if !ok {
+			skipped = append(skipped, LoopRecord{Table: "Repetitions", Id: repetition.Id, Reason: "habit was not imported"})
+			continue
+		}
+
+		entry := ExportedEntry{Value: 1}
+		if numerical[repetition.HabitId] {
+			if repetition.Value < 0 {
+				skipped = append(skipped, LoopRecord{Table: "Repetitions", Id: repetition.Id, Reason: "value is unknown"})
+				continue
+			}
+			entry.Value = float64(repetition.Value) / 1000
+		} else if repetition.Value == loopSkip {
+			entry.Value = 0
+			entry.Status = models.EntrySkipped
+		} else if reason := loopSkipReason(repetition.Value); reason != "" {
+			skipped = append(skipped, LoopRecord{Table: "Repetitions", Id: repetition.Id, Reason: reason})
+			continue
+		}
+
+		entry.Date = time.UnixMilli(repetition.Timestamp).UTC().Format(time.DateOnly)
+		export.Habits[i].Entries = append(export.Habits[i].Entries, entry)
+	}
+
+	return export, skipped, unmapped
+}
+
+// loopSchedule maps a Loop frequency of num times every den days to a
+// schedule, returning false and a daily schedule if there is no equivalent.
+func loopSchedule(num int64, den int64) (models.Schedule, bool) {
+	switch {
+	case num < 1 || den < 1:
+		return models.NewDailySchedule(), false
+	case num >= den:
+		return models.NewDailySchedule(), true
+	case den == 7:
+		return models.Schedule{Type: models.ScheduleTimesPerWeek, Weekdays: []time.Weekday{}, Count: num}, true
+	case den == 30 || den == 31:
+		return models.Schedule{Type: models.ScheduleTimesPerMonth, Weekdays: []time.Weekday{}, Count: num}, true
+	case num == 1:
+		return models.Schedule{Type: models.ScheduleEveryNDays, Weekdays: []time.Weekday{}, Count: den}, true
+	}
+
+	return models.NewDailySchedule(), false
+}
+
+// loopSkipReason returns why a yes or no repetition is not imported, or an
+// empty string if it is a completion.
+func loopSkipReason(value int64) string {
+	switch value {
+	case loopYesManual:
+		return ""
+	case loopYesAuto:
+		return "automatic check marks are not imported"
+	case loopNo:
+		return "marked as not done"
+	case loopUnknown:
+		return "value is unknown"
+	}
+
+	return fmt.Sprintf("value %d is not supported", value)
+}
+
+func readRows(db *sql.DB, query string) ([]map[string]any, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLoopBackup, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func rowString(row map[string]any, column string) string {
+	switch value := row[column].(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	}
+
+	return ""
+}
+
+func rowInt(row map[string]any, column string) int64 {
+	switch value := row[column].(type) {
+	case int64:
+		return value
+	case float64:
+		return int64(value)
+	}
+
+	return 0
+}
+
+func rowFloat(row map[string]any, column string) float64 {
+	switch value := row[column].(type) {
+	case int64:
+		return float64(value)
+	case float64:
+		return value
+	}
+
+	return 0
+}
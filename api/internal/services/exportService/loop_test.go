@@ -0,0 +1,63 @@
+package exportService
+
+import (
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopSchedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		num          int64
+		den          int64
+		expectedType models.ScheduleType
+		expectedOk   bool
+	}{
+		{name: "every day", num: 1, den: 1, expectedType: models.ScheduleDaily, expectedOk: true},
+		{name: "times per week", num: 3, den: 7, expectedType: models.ScheduleTimesPerWeek, expectedOk: true},
+		{name: "times per month", num: 2, den: 30, expectedType: models.ScheduleTimesPerMonth, expectedOk: true},
+		{name: "every n days", num: 1, den: 3, expectedType: models.ScheduleEveryNDays, expectedOk: true},
+		{name: "unsupported frequency", num: 2, den: 5, expectedType: models.ScheduleDaily, expectedOk: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			schedule, ok := loopSchedule(tc.num, tc.den)
+
+			// Assert
+			assert.Equal(t, tc.expectedType, schedule.Type)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.NoError(t, schedule.Validate())
+		})
+	}
+}
+
+func TestExportFromLoop(t *testing.T) {
+	// Arrange
+	habits := []loopHabit{
+		{Id: 1, Name: "Meditate", FreqNum: 1, FreqDen: 1, Colour: 7},
+		{Id: 2, Name: "Water", FreqNum: 1, FreqDen: 1, Colour: 99, Type: loopNumerical, TargetType: loopTargetMost, TargetValue: 8, Unit: "glasses", Archived: true},
+	}
+	repetitions := []loopRepetition{
+		{Id: 1, HabitId: 1, Timestamp: 1730419200000, Value: loopYesManual},
+		{Id: 2, HabitId: 1, Timestamp: 1730505600000, Value: loopSkip},
+		{Id: 3, HabitId: 2, Timestamp: 1730419200000, Value: 2500},
+		{Id: 4, HabitId: 3, Timestamp: 1730419200000, Value: loopYesManual},
+	}
+
+	// Act
+	export, skipped, unmapped := exportFromLoop(habits, repetitions)
+
+	// Assert
+	assert.Len(t, export.Habits, 2)
+	assert.Equal(t, "#388E3C", export.Habits[0].Colour)
+	assert.Equal(t, []ExportedEntry{{Date: "2024-11-01", Value: 1}, {Date: "2024-11-02", Status: models.EntrySkipped}}, export.Habits[0].Entries)
+	assert.True(t, export.Habits[1].Archived)
+	assert.Equal(t, models.NewTarget(8, "glasses", string(models.TargetAtMost)), export.Habits[1].Target)
+	assert.Equal(t, []ExportedEntry{{Date: "2024-11-01", Value: 2.5}}, export.Habits[1].Entries)
+	assert.Equal(t, []LoopRecord{{Table: "Repetitions", Id: 4, Reason: "habit was not imported"}}, skipped)
+	assert.Equal(t, []LoopRecord{{Table: "Habits", Id: 2, Reason: "colour 99 is not in the palette"}}, unmapped)
+}
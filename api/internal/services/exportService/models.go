@@ -0,0 +1,83 @@
+package exportService
+
+import (
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+// ExportVersion is the version of the export document format. It is bumped
+// whenever a change means older documents can no longer be imported as is.
+const ExportVersion = 1
+
+type ImportMode string
+
+const (
+	// ImportMerge adds habits to the user's existing habits, adding entries to
+	// habits with the same name.
+	ImportMerge ImportMode = "merge"
+	// ImportReplace moves all of the user's habits to the trash before importing.
+	ImportReplace ImportMode = "replace"
+)
+
+// Export is a user's habits and all of their entries.
+type Export struct {
+	ExportedAt time.Time          `json:"exportedAt"`
+	User       ExportedUser       `json:"user"`
+	Categories []ExportedCategory `json:"categories,omitempty"`
+	Habits     []ExportedHabit    `json:"habits"`
+	Version    int                `json:"version"`
+}
+
+type ExportedUser struct {
+	Name string `json:"name"`
+}
+
+type ExportedCategory struct {
+	Name   string `json:"name"`
+	Colour string `json:"colour"`
+}
+
+// ExportedHabit is a habit with its entries. Category is the name of the
+// habit's category and Tags are the names of its tags.
+type ExportedHabit struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Icon        string          `json:"icon,omitempty"`
+	Category    string          `json:"category,omitempty"`
+	Colour      string          `json:"colour"`
+	Tags        []string        `json:"tags,omitempty"`
+	Entries     []ExportedEntry `json:"entries"`
+	Schedule    models.Schedule `json:"schedule"`
+	Target      models.Target   `json:"target"`
+	Index       int64           `json:"index"`
+	Active      bool            `json:"active"`
+	Archived    bool            `json:"archived,omitempty"`
+}
+
+// ExportedEntry is a habit entry, its date formatted as YYYY-MM-DD. Status is
+// left out for done entries.
+type ExportedEntry struct {
+	Date   string             `json:"date"`
+	Note   string             `json:"note,omitempty"`
+	Status models.EntryStatus `json:"status,omitempty"`
+	Value  float64            `json:"value"`
+}
+
+// ImportConflict is an imported entry that was skipped because the habit
+// already has an entry for its date.
+type ImportConflict struct {
+	Habit string  `json:"habit"`
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+type ImportResult struct {
+	Conflicts         []ImportConflict `json:"conflicts"`
+	HabitsCreated     int              `json:"habitsCreated"`
+	CategoriesCreated int              `json:"categoriesCreated"`
+	TagsCreated       int              `json:"tagsCreated"`
+	HabitsMerged      int              `json:"habitsMerged"`
+	EntriesImported   int              `json:"entriesImported"`
+	DryRun            bool             `json:"dryRun"`
+}
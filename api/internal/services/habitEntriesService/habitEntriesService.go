@@ -2,15 +2,28 @@ package habitEntriesService
 
 import (
 	"context"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
 	"github.com/ReidMason/habit-tracker/internal/services/models"
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const (
+	// leadInDays of entries before a date range are loaded along with it, so the
+	// combos leading into the range can usually be calculated without more queries.
+	leadInDays     = 62
+	leadInPageSize = 100
+	maxDate        = "9999-12-31"
 )
 
 type HabitEntryStorage interface {
-	GetHabitEntries(ctx context.Context, habitID int64) ([]sqlite3Storage.HabitEntry, error)
+	GetHabitEntries(ctx context.Context, habitID int64) ([]repository.HabitEntry, error)
+	GetHabitEntriesBetween(ctx context.Context, arg repository.GetHabitEntriesBetweenParams) ([]repository.HabitEntry, error)
+	GetHabitEntriesBefore(ctx context.Context, arg repository.GetHabitEntriesBeforeParams) ([]repository.HabitEntry, error)
+	GetUserHabitEntriesBetween(ctx context.Context, arg repository.GetUserHabitEntriesBetweenParams) ([]repository.HabitEntry, error)
 }
 
 type HabitEntryService struct {
@@ -25,34 +38,372 @@ func NewHabitEntriesService(storage HabitEntryStorage, logger logger.Logger) *Ha
 	}
 }
 
-func (s *HabitEntryService) GetHabitEntries(habitId int64) ([]models.HabitEntry, error) {
+// GetHabitHistory returns every entry of a habit with its combo.
+func (s *HabitEntryService) GetHabitHistory(habitId int64, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error) {
 	ctx := context.Background()
 	entries, err := s.storage.GetHabitEntries(ctx, habitId)
 	if err != nil {
-		s.logger.Error("Failed to get habit entries", err)
+		s.logger.Error("Failed to get habit entries", slog.Any("error", err))
+		return nil, err
+	}
+
+	habitEntries, err := newHabitEntriesFromStorage(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	calculateCombos(habitEntries, schedule, target)
+
+	return habitEntries, nil
+}
+
+// GetHabitEntries returns up to limit of a habit's entries within the date range,
+// with a cursor to continue from if there are more.
+func (s *HabitEntryService) GetHabitEntries(habitId int64, dateRange models.DateRange, limit int64, schedule models.Schedule, target models.Target) (models.HabitEntriesPage, error) {
+	ctx := context.Background()
+	entries, err := s.storage.GetHabitEntriesBetween(ctx, repository.GetHabitEntriesBetweenParams{
+		HabitID:  habitId,
+		FromDate: fromDate(dateRange.From),
+		ToDate:   toDate(dateRange.To),
+		Limit:    limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get habit entries", slog.Any("error", err))
+		return models.HabitEntriesPage{}, err
+	}
+
+	habitEntries, err := newHabitEntriesFromStorage(entries)
+	if err != nil {
+		return models.HabitEntriesPage{}, err
+	}
+
+	page := models.HabitEntriesPage{}
+	if int64(len(habitEntries)) > limit {
+		habitEntries = habitEntries[:limit]
+		page.NextCursor = habitEntries[limit-1].Date.Format(time.DateOnly)
+	}
+
+	page.Entries, err = s.calculateCombosInRange(ctx, habitId, habitEntries, dateRange.From, dateRange, schedule, target)
+	if err != nil {
+		return models.HabitEntriesPage{}, err
+	}
+
+	return page, nil
+}
+
+// GetUserHabitEntries loads the entries of every habit a user has within the date
+// range in a single query, grouped by habit ID. Entries from shortly before the
+// range are included for CalculateCombosInRange.
+func (s *HabitEntryService) GetUserHabitEntries(userId int64, dateRange models.DateRange) (map[int64][]models.HabitEntry, error) {
+	ctx := context.Background()
+	entries, err := s.storage.GetUserHabitEntriesBetween(ctx, repository.GetUserHabitEntriesBetweenParams{
+		UserID:   userId,
+		FromDate: fromDate(leadInStart(dateRange.From)),
+		ToDate:   toDate(dateRange.To),
+	})
+	if err != nil {
+		s.logger.Error("Failed to get user habit entries", slog.Any("error", err))
 		return nil, err
 	}
 
+	habitEntries := make(map[int64][]models.HabitEntry)
+	for _, entry := range entries {
+		habitEntry, err := models.NewHabitEntryFromStorage(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		habitEntries[entry.HabitID] = append(habitEntries[entry.HabitID], habitEntry)
+	}
+
+	return habitEntries, nil
+}
+
+// CalculateCombosInRange calculates the combos of a habit's entries loaded by
+// GetUserHabitEntries and returns those within the date range.
+func (s *HabitEntryService) CalculateCombosInRange(habitId int64, habitEntries []models.HabitEntry, dateRange models.DateRange, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error) {
+	ctx := context.Background()
+	return s.calculateCombosInRange(ctx, habitId, habitEntries, leadInStart(dateRange.From), dateRange, schedule, target)
+}
+
+// calculateCombosInRange loads any earlier entries the combos leading into the
+// date range depend on, then calculates combos and drops entries outside the range.
+// Every entry of the habit dated from loadedFrom onwards must already be loaded.
+func (s *HabitEntryService) calculateCombosInRange(ctx context.Context, habitId int64, habitEntries []models.HabitEntry, loadedFrom time.Time, dateRange models.DateRange, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error) {
+	if !dateRange.From.IsZero() {
+		for !comboStartLoaded(habitEntries, loadedFrom, dateRange.From, schedule, target) {
+			entries, err := s.storage.GetHabitEntriesBefore(ctx, repository.GetHabitEntriesBeforeParams{
+				HabitID: habitId,
+				Date:    loadedFrom.Format(time.DateOnly),
+				Limit:   leadInPageSize,
+			})
+			if err != nil {
+				s.logger.Error("Failed to get earlier habit entries", slog.Any("error", err))
+				return nil, err
+			}
+
+			earlierEntries, err := newHabitEntriesFromStorage(entries)
+			if err != nil {
+				return nil, err
+			}
+			slices.Reverse(earlierEntries)
+			habitEntries = append(earlierEntries, habitEntries...)
+
+			if len(entries) < leadInPageSize {
+				break
+			}
+			loadedFrom = earlierEntries[0].Date
+		}
+	}
+
+	calculateCombos(habitEntries, schedule, target)
+
+	entriesInRange := make([]models.HabitEntry, 0, len(habitEntries))
+	for _, habitEntry := range habitEntries {
+		if dateRange.Contains(habitEntry.Date) {
+			entriesInRange = append(entriesInRange, habitEntry)
+		}
+	}
+
+	return entriesInRange, nil
+}
+
+// comboStartLoaded reports whether the combo running into the first completed
+// entry on or after from started within the loaded entries, i.e. there is a gap
+// or failed day that breaks it no matter what came before. Skipped days are
+// treated as continuing it, and with a freeze allowance the months it spans must
+// be loaded from their start to count the skips used. Nothing dated before
+// loadedFrom is loaded.
+func comboStartLoaded(habitEntries []models.HabitEntry, loadedFrom time.Time, from time.Time, schedule models.Schedule, target models.Target) bool {
+	atMost := target.Comparison == models.TargetAtMost
+	if atMost {
+		habitEntries, _ = fillUnrecordedDays(habitEntries, schedule)
+	}
+
+	freezesLoaded := func(start time.Time) bool {
+		if start.After(from) {
+			start = from
+		}
+
+		return schedule.FreezesPerMonth == nil || !loadedFrom.After(monthStart(start))
+	}
+
+	first := slices.IndexFunc(habitEntries, func(habitEntry models.HabitEntry) bool {
+		return !habitEntry.Date.Before(from) && completes(habitEntry, target)
+	})
+	if first == -1 {
+		return freezesLoaded(from)
+	}
+
+	next := habitEntries[first].Date
+	for i := first - 1; i >= 0; i-- {
+		if habitEntries[i].Status == models.EntryFailed {
+			return freezesLoaded(next)
+		}
+		if habitEntries[i].Status != models.EntrySkipped && !completes(habitEntries[i], target) {
+			continue
+		}
+		if schedule.BreaksCombo(habitEntries[i].Date, next) {
+			return freezesLoaded(next)
+		}
+		next = habitEntries[i].Date
+	}
+
+	// Days before loadedFrom without an entry would meet an at most target, so
+	// only an earlier entry can end the combo.
+	return !atMost && schedule.BreaksCombo(loadedFrom.AddDate(0, 0, -1), next) && freezesLoaded(next)
+}
+
+// calculateCombos marks which of a habit's date ordered entries meet its target
+// and which skipped days are excused, then sets their combos according to its
+// schedule. A day without an entry has a value of 0, so for an at most target
+// the days the schedule expects between entries count as completions.
+func calculateCombos(habitEntries []models.HabitEntry, schedule models.Schedule, target models.Target) {
+	if target.Comparison != models.TargetAtMost {
+		calculateEntryCombos(habitEntries, schedule, target)
+		return
+	}
+
+	filled, indexes := fillUnrecordedDays(habitEntries, schedule)
+	calculateEntryCombos(filled, schedule, target)
+	for i, index := range indexes {
+		habitEntries[i] = filled[index]
+	}
+}
+
+// fillUnrecordedDays returns the entries with an entry of value 0 added for
+// each day the schedule expects between them, along with the index each of the
+// given entries ended up at.
+func fillUnrecordedDays(habitEntries []models.HabitEntry, schedule models.Schedule) ([]models.HabitEntry, []int) {
+	filled := make([]models.HabitEntry, 0, len(habitEntries))
+	indexes := make([]int, len(habitEntries))
+	for i, habitEntry := range habitEntries {
+		if i > 0 {
+			previous := habitEntries[i-1].Date
+			for date := nextExpected(previous, schedule); date.Before(habitEntry.Date); date = nextExpected(date, schedule) {
+				filled = append(filled, models.NewHabitEntry(date, 0, 0, 0))
+			}
+		}
+
+		indexes[i] = len(filled)
+		filled = append(filled, habitEntry)
+	}
+
+	return filled, indexes
+}
+
+// nextExpected returns the day after date the schedule next expects the habit
+// to be done. Periodic schedules can be done on any day.
+func nextExpected(date time.Time, schedule models.Schedule) time.Time {
+	if schedule.IsPeriodic() {
+		return date.AddDate(0, 0, 1)
+	}
+
+	return schedule.NextDue(date)
+}
+
+func calculateEntryCombos(habitEntries []models.HabitEntry, schedule models.Schedule, target models.Target) {
+	skips := make(map[time.Time]int64)
+	for i, habitEntry := range habitEntries {
+		habitEntries[i].Completed = completes(habitEntry, target)
+		if habitEntry.Status == models.EntrySkipped {
+			month := monthStart(habitEntry.Date)
+			skips[month]++
+			habitEntries[i].Excused = schedule.FreezesPerMonth == nil || skips[month] <= *schedule.FreezesPerMonth
+		}
+	}
+
+	if schedule.IsPeriodic() {
+		calculatePeriodCombos(habitEntries, schedule)
+	} else {
+		calculateIntervalCombos(habitEntries, schedule)
+	}
+}
+
+// completes reports whether an entry counts as a completion. Skipped and
+// failed days never do, whatever their value.
+func completes(habitEntry models.HabitEntry, target models.Target) bool {
+	return habitEntry.Status != models.EntrySkipped && habitEntry.Status != models.EntryFailed && target.IsMet(habitEntry.Value)
+}
+
+func monthStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+}
+
+func newHabitEntriesFromStorage(entries []repository.HabitEntry) ([]models.HabitEntry, error) {
 	habitEntries := make([]models.HabitEntry, len(entries))
-	combo := 0
-	var tomorrow time.Time
 	for i, entry := range entries {
 		habitEntry, err := models.NewHabitEntryFromStorage(entry)
 		if err != nil {
 			return nil, err
 		}
 
-		if combo > 0 && habitEntry.Date == tomorrow {
+		habitEntries[i] = habitEntry
+	}
+
+	return habitEntries, nil
+}
+
+func leadInStart(from time.Time) time.Time {
+	if from.IsZero() {
+		return from
+	}
+
+	return from.AddDate(0, 0, -leadInDays)
+}
+
+func fromDate(from time.Time) string {
+	if from.IsZero() {
+		return ""
+	}
+
+	return from.Format(time.DateOnly)
+}
+
+func toDate(to time.Time) string {
+	if to.IsZero() {
+		return maxDate
+	}
+
+	return to.Format(time.DateOnly)
+}
+
+// calculateIntervalCombos continues the combo for as long as each completed entry
+// falls on or before the date the schedule next expects the habit to be done.
+// An excused day that falls in time pushes that date back as if it had been
+// completed, carrying the combo it kept going, and a failed day ends the combo.
+// Other entries that are not completions are left with a combo of 0.
+func calculateIntervalCombos(habitEntries []models.HabitEntry, schedule models.Schedule) {
+	combo := 0
+	var nextDue time.Time
+	for i, habitEntry := range habitEntries {
+		switch {
+		case habitEntry.Status == models.EntryFailed:
+			combo = 0
+			continue
+		case habitEntry.Excused:
+			if combo > 0 && !habitEntry.Date.After(nextDue) {
+				nextDue = schedule.NextDue(habitEntry.Date)
+				habitEntries[i].Combo = combo
+			}
+			continue
+		case !habitEntry.Completed:
+			continue
+		}
+
+		if combo > 0 && !habitEntry.Date.After(nextDue) {
 			combo++
 		} else {
 			combo = 1
 		}
 
-		tomorrow = habitEntry.Date.AddDate(0, 0, 1)
-
-		habitEntry.Combo = combo
-		habitEntries[i] = habitEntry
+		nextDue = schedule.NextDue(habitEntry.Date)
+		habitEntries[i].Combo = combo
 	}
+}
 
-	return habitEntries, nil
+// calculatePeriodCombos continues the combo for completed entries within the same
+// week or month, and into the following period only if the target count was
+// reached. Excused days count towards the target count, carrying the combo
+// without adding to it, and a failed day ends the combo.
+func calculatePeriodCombos(habitEntries []models.HabitEntry, schedule models.Schedule) {
+	combo := 0
+	var completed int64
+	var period time.Time
+	for i, habitEntry := range habitEntries {
+		if habitEntry.Status == models.EntryFailed {
+			combo = 0
+			continue
+		}
+		if !habitEntry.Completed && !habitEntry.Excused {
+			continue
+		}
+
+		periodStart := schedule.PeriodStart(habitEntry.Date)
+		continues := combo > 0 && (periodStart.Equal(period) || periodStart.Equal(schedule.NextPeriod(period)) && completed >= schedule.Count)
+		if !periodStart.Equal(period) {
+			completed = 0
+		}
+		period = periodStart
+
+		if habitEntry.Excused {
+			if continues {
+				completed++
+				habitEntries[i].Combo = combo
+			} else {
+				combo = 0
+			}
+			continue
+		}
+
+		if continues {
+			combo++
+			completed++
+		} else {
+			combo = 1
+			completed = 1
+		}
+
+		habitEntries[i].Combo = combo
+	}
 }
@@ -0,0 +1,387 @@
+package habitEntriesService
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHabitEntryStorage struct {
+	entries []repository.HabitEntry
+}
+
+func (m mockHabitEntryStorage) GetHabitEntries(_ context.Context, _ int64) ([]repository.HabitEntry, error) {
+	return m.entries, nil
+}
+
+func (m mockHabitEntryStorage) GetHabitEntriesBetween(_ context.Context, arg repository.GetHabitEntriesBetweenParams) ([]repository.HabitEntry, error) {
+	entries := make([]repository.HabitEntry, 0)
+	for _, entry := range m.entries {
+		if entry.HabitID == arg.HabitID && entry.Date >= arg.FromDate && entry.Date <= arg.ToDate && int64(len(entries)) < arg.Limit {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (m mockHabitEntryStorage) GetHabitEntriesBefore(_ context.Context, arg repository.GetHabitEntriesBeforeParams) ([]repository.HabitEntry, error) {
+	entries := make([]repository.HabitEntry, 0)
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		if entry.HabitID == arg.HabitID && entry.Date < arg.Date && int64(len(entries)) < arg.Limit {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (m mockHabitEntryStorage) GetUserHabitEntriesBetween(_ context.Context, arg repository.GetUserHabitEntriesBetweenParams) ([]repository.HabitEntry, error) {
+	entries := make([]repository.HabitEntry, 0)
+	for _, entry := range m.entries {
+		if entry.Date >= arg.FromDate && entry.Date <= arg.ToDate {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(time.DateOnly, value)
+	return parsed
+}
+
+// dailyEntries returns an entry for every day from start for the given number of days.
+func dailyEntries(start string, days int) []repository.HabitEntry {
+	dates := make([]string, days)
+	for i := range dates {
+		dates[i] = date(start).AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	return entriesOn(dates...)
+}
+
+func entriesOn(dates ...string) []repository.HabitEntry {
+	entries := make([]repository.HabitEntry, len(dates))
+	for i, date := range dates {
+		entries[i] = repository.HabitEntry{ID: int64(i + 1), HabitID: 1, Date: date, Value: 1}
+	}
+
+	return entries
+}
+
+func entriesWithValues(values map[string]float64) []repository.HabitEntry {
+	dates := make([]string, 0, len(values))
+	for date := range values {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	entries := entriesOn(dates...)
+	for i := range entries {
+		entries[i].Value = values[entries[i].Date]
+	}
+
+	return entries
+}
+
+func entriesWithStatuses(statuses map[string]models.EntryStatus) []repository.HabitEntry {
+	dates := make([]string, 0, len(statuses))
+	for date := range statuses {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	entries := entriesOn(dates...)
+	for i := range entries {
+		entries[i].Status = string(statuses[entries[i].Date])
+	}
+
+	return entries
+}
+
+func freezes(count int64) *int64 {
+	return &count
+}
+
+func TestGetHabitEntriesCombos(t *testing.T) {
+	tests := []struct {
+		name            string
+		entries         []repository.HabitEntry
+		schedule        models.Schedule
+		target          models.Target
+		expectedCombos  []int
+		expectedExcused []bool
+	}{
+		{
+			name:           "daily combo breaks on a missed day",
+			schedule:       models.NewDailySchedule(),
+			entries:        entriesOn("2024-11-01", "2024-11-02", "2024-11-03", "2024-11-05"),
+			expectedCombos: []int{1, 2, 3, 1},
+		},
+		{
+			name:           "every 3 days allows gaps up to 3 days",
+			schedule:       models.Schedule{Type: models.ScheduleEveryNDays, Count: 3},
+			entries:        entriesOn("2024-11-01", "2024-11-04", "2024-11-05", "2024-11-09"),
+			expectedCombos: []int{1, 2, 3, 1},
+		},
+		{
+			name:     "sundays only continues week to week",
+			schedule: models.Schedule{Type: models.ScheduleWeekdays, Weekdays: []time.Weekday{time.Sunday}},
+			// 3rd, 10th and 24th are Sundays
+			entries:        entriesOn("2024-11-03", "2024-11-10", "2024-11-24"),
+			expectedCombos: []int{1, 2, 1},
+		},
+		{
+			name:     "three times per week needs the target met before the next week",
+			schedule: models.Schedule{Type: models.ScheduleTimesPerWeek, Count: 3},
+			// Weeks start on Monday 4th, 11th and 18th
+			entries: entriesOn(
+				"2024-11-04", "2024-11-06", "2024-11-08",
+				"2024-11-11", "2024-11-13",
+				"2024-11-18",
+			),
+			expectedCombos: []int{1, 2, 3, 4, 5, 1},
+		},
+		{
+			name:           "times per month breaks when a month is skipped",
+			schedule:       models.Schedule{Type: models.ScheduleTimesPerMonth, Count: 1},
+			entries:        entriesOn("2024-09-15", "2024-10-01", "2024-12-01"),
+			expectedCombos: []int{1, 2, 1},
+		},
+		{
+			name:     "days below an at least target do not count",
+			schedule: models.NewDailySchedule(),
+			target:   models.Target{Comparison: models.TargetAtLeast, Value: 8, Unit: "glasses"},
+			entries: entriesWithValues(map[string]float64{
+				"2024-11-01": 8, "2024-11-02": 10, "2024-11-03": 4, "2024-11-04": 8,
+			}),
+			expectedCombos: []int{1, 2, 0, 1},
+		},
+		{
+			name:     "days above an at most target do not count",
+			schedule: models.NewDailySchedule(),
+			target:   models.Target{Comparison: models.TargetAtMost, Value: 2, Unit: "coffees"},
+			entries: entriesWithValues(map[string]float64{
+				"2024-11-01": 1, "2024-11-02": 2, "2024-11-03": 3,
+			}),
+			expectedCombos: []int{1, 2, 0},
+		},
+		{
+			name:     "days without an entry meet an at most target",
+			schedule: models.NewDailySchedule(),
+			target:   models.Target{Comparison: models.TargetAtMost, Value: 2, Unit: "coffees"},
+			entries: entriesWithValues(map[string]float64{
+				"2024-11-01": 1, "2024-11-03": 2, "2024-11-04": 3, "2024-11-06": 0,
+			}),
+			expectedCombos: []int{1, 3, 0, 2},
+		},
+		{
+			name:     "only scheduled days without an entry meet an at most target",
+			schedule: models.Schedule{Type: models.ScheduleEveryNDays, Count: 3},
+			target:   models.Target{Comparison: models.TargetAtMost, Value: 2, Unit: "coffees"},
+			entries: entriesWithValues(map[string]float64{
+				"2024-11-01": 1, "2024-11-08": 1,
+			}),
+			expectedCombos: []int{1, 4},
+		},
+		{
+			name:     "skipped days carry the combo without adding to it",
+			schedule: models.NewDailySchedule(),
+			entries: entriesWithStatuses(map[string]models.EntryStatus{
+				"2024-11-01": models.EntryDone, "2024-11-02": models.EntrySkipped, "2024-11-03": models.EntrySkipped, "2024-11-04": models.EntryDone,
+			}),
+			expectedCombos:  []int{1, 1, 1, 2},
+			expectedExcused: []bool{false, true, true, false},
+		},
+		{
+			name:     "failed days break the combo",
+			schedule: models.NewDailySchedule(),
+			entries: entriesWithStatuses(map[string]models.EntryStatus{
+				"2024-11-01": models.EntryDone, "2024-11-02": models.EntryDone, "2024-11-03": models.EntryFailed, "2024-11-04": models.EntryDone,
+			}),
+			expectedCombos: []int{1, 2, 0, 1},
+		},
+		{
+			name:     "skips beyond the monthly freeze allowance break the combo",
+			schedule: models.Schedule{Type: models.ScheduleDaily, Count: 1, FreezesPerMonth: freezes(1)},
+			entries: entriesWithStatuses(map[string]models.EntryStatus{
+				"2024-10-30": models.EntryDone, "2024-10-31": models.EntrySkipped, "2024-11-01": models.EntrySkipped, "2024-11-02": models.EntryDone,
+				"2024-11-03": models.EntrySkipped, "2024-11-04": models.EntryDone,
+			}),
+			expectedCombos:  []int{1, 1, 1, 2, 0, 1},
+			expectedExcused: []bool{false, true, true, false, false, false},
+		},
+		{
+			name:     "skipped days count towards a periodic target",
+			schedule: models.Schedule{Type: models.ScheduleTimesPerWeek, Count: 2},
+			// Weeks start on Monday 4th, 11th and 18th
+			entries: entriesWithStatuses(map[string]models.EntryStatus{
+				"2024-11-04": models.EntryDone, "2024-11-05": models.EntryDone,
+				"2024-11-11": models.EntryDone, "2024-11-12": models.EntrySkipped,
+				"2024-11-18": models.EntryDone,
+			}),
+			expectedCombos: []int{1, 2, 3, 3, 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			service := NewHabitEntriesService(mockHabitEntryStorage{entries: tc.entries}, &logger.MockLogger{})
+			if tc.target.IsZero() {
+				tc.target = models.NewDefaultTarget()
+			}
+
+			// Act
+			page, err := service.GetHabitEntries(1, models.DateRange{}, 100, tc.schedule, tc.target)
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+
+			combos := make([]int, len(page.Entries))
+			excused := make([]bool, len(page.Entries))
+			for i, entry := range page.Entries {
+				combos[i] = entry.Combo
+				excused[i] = entry.Excused
+			}
+			assert.Equal(t, tc.expectedCombos, combos)
+			if tc.expectedExcused != nil {
+				assert.Equal(t, tc.expectedExcused, excused)
+			}
+		})
+	}
+}
+
+func TestGetHabitEntriesInRange(t *testing.T) {
+	tests := []struct {
+		name               string
+		entries            []repository.HabitEntry
+		schedule           models.Schedule
+		target             models.Target
+		dateRange          models.DateRange
+		limit              int64
+		expectedCombos     []int
+		expectedNextCursor string
+	}{
+		{
+			name:           "combo continues from before the range",
+			schedule:       models.NewDailySchedule(),
+			entries:        entriesOn("2024-10-30", "2024-10-31", "2024-11-01", "2024-11-02"),
+			dateRange:      models.DateRange{From: date("2024-11-01"), To: date("2024-11-30")},
+			limit:          100,
+			expectedCombos: []int{3, 4},
+		},
+		{
+			name:           "combo longer than a page of earlier entries",
+			schedule:       models.NewDailySchedule(),
+			entries:        dailyEntries("2024-01-01", 366),
+			dateRange:      models.DateRange{From: date("2024-12-30")},
+			limit:          100,
+			expectedCombos: []int{365, 366},
+		},
+		{
+			name:           "combo broken before the range",
+			schedule:       models.NewDailySchedule(),
+			entries:        entriesOn("2024-10-28", "2024-10-31", "2024-11-01"),
+			dateRange:      models.DateRange{From: date("2024-11-01")},
+			limit:          100,
+			expectedCombos: []int{2},
+		},
+		{
+			name:           "periodic combo continues from before the range",
+			schedule:       models.Schedule{Type: models.ScheduleTimesPerWeek, Count: 2},
+			entries:        entriesOn("2024-10-21", "2024-10-22", "2024-10-28", "2024-10-29", "2024-11-04"),
+			dateRange:      models.DateRange{From: date("2024-10-29")},
+			limit:          100,
+			expectedCombos: []int{4, 5},
+		},
+		{
+			name:           "at most combo continues over days without an entry before the range",
+			schedule:       models.NewDailySchedule(),
+			target:         models.Target{Comparison: models.TargetAtMost, Value: 2, Unit: "coffees"},
+			entries:        entriesOn("2024-10-01", "2024-10-10", "2024-11-01"),
+			dateRange:      models.DateRange{From: date("2024-11-01")},
+			limit:          100,
+			expectedCombos: []int{32},
+		},
+		{
+			name:     "skips earlier in the month use the freeze allowance",
+			schedule: models.Schedule{Type: models.ScheduleDaily, Count: 1, FreezesPerMonth: freezes(1)},
+			entries: entriesWithStatuses(map[string]models.EntryStatus{
+				"2024-11-10": models.EntrySkipped,
+				"2024-11-27": models.EntryDone, "2024-11-28": models.EntrySkipped, "2024-11-29": models.EntryDone,
+			}),
+			dateRange:      models.DateRange{From: date("2024-11-25")},
+			limit:          100,
+			expectedCombos: []int{1, 0, 1},
+		},
+		{
+			name:               "returns a cursor when there are more entries",
+			schedule:           models.NewDailySchedule(),
+			entries:            entriesOn("2024-11-01", "2024-11-02", "2024-11-03"),
+			dateRange:          models.DateRange{From: date("2024-11-02")},
+			limit:              1,
+			expectedCombos:     []int{2},
+			expectedNextCursor: "2024-11-02",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			service := NewHabitEntriesService(mockHabitEntryStorage{entries: tc.entries}, &logger.MockLogger{})
+			if tc.target.IsZero() {
+				tc.target = models.NewDefaultTarget()
+			}
+
+			// Act
+			page, err := service.GetHabitEntries(1, tc.dateRange, tc.limit, tc.schedule, tc.target)
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+
+			combos := make([]int, len(page.Entries))
+			for i, entry := range page.Entries {
+				combos[i] = entry.Combo
+			}
+			assert.Equal(t, tc.expectedCombos, combos)
+			assert.Equal(t, tc.expectedNextCursor, page.NextCursor)
+		})
+	}
+}
+
+func TestGetUserHabitEntries(t *testing.T) {
+	// Arrange
+	entries := []repository.HabitEntry{
+		{ID: 1, HabitID: 1, Date: "2024-11-01", Value: 1},
+		{ID: 2, HabitID: 1, Date: "2024-11-02", Value: 1},
+		{ID: 3, HabitID: 2, Date: "2024-11-01", Value: 1},
+	}
+	service := NewHabitEntriesService(mockHabitEntryStorage{entries: entries}, &logger.MockLogger{})
+
+	// Act
+	habitEntries, err := service.GetUserHabitEntries(1, models.DateRange{})
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	assert.Len(t, habitEntries, 2)
+	assert.Len(t, habitEntries[1], 2)
+	assert.Len(t, habitEntries[2], 1)
+	assert.Equal(t, int64(3), habitEntries[2][0].Id)
+}
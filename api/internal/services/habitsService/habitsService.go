@@ -2,43 +2,102 @@ package habitsService
 
 import (
 	"context"
-	"log/slog"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
 	"github.com/ReidMason/habit-tracker/internal/services/models"
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const (
+	MaxDescriptionLength = 1000
+	MaxIconLength        = 32
+)
+
+var (
+	ErrHabitNotFound      = errors.New("habit not found")
+	ErrInvalidOrder       = errors.New("each habit can only be listed once")
+	ErrHabitNotArchived   = errors.New("habits must be archived before they are purged")
+	ErrCategoryNotFound   = errors.New("category not found")
+	ErrDescriptionTooLong = errors.New("description must be at most 1000 characters")
+	ErrIconTooLong        = errors.New("icon must be at most 32 characters")
+	ErrHabitConflict      = errors.New("habit has been changed since it was read")
+	ErrVersionRequired    = errors.New("habit version is required")
 )
 
 type HabitStorage interface {
-	GetHabits(ctx context.Context, userID int64) ([]sqlite3Storage.Habit, error)
-	UpdateHabit(ctx context.Context, arg sqlite3Storage.UpdateHabitParams) (sqlite3Storage.Habit, error)
-	CreateHabit(ctx context.Context, arg sqlite3Storage.CreateHabitParams) (sqlite3Storage.Habit, error)
-	DeleteHabit(ctx context.Context, id int64) (sqlite3Storage.Habit, error)
+	GetHabit(ctx context.Context, id int64) (repository.Habit, error)
+	GetHabits(ctx context.Context, userID int64) ([]repository.Habit, error)
+	UpdateHabit(ctx context.Context, arg repository.UpdateHabitParams) (repository.Habit, error)
+	CreateHabit(ctx context.Context, arg repository.CreateHabitParams) (repository.Habit, error)
+	TrashHabit(ctx context.Context, arg repository.TrashHabitParams) (repository.Habit, error)
+	SetHabitIndex(ctx context.Context, arg repository.SetHabitIndexParams) (int64, error)
+	SetHabitArchivedAt(ctx context.Context, arg repository.SetHabitArchivedAtParams) (repository.Habit, error)
+	GetCategory(ctx context.Context, arg repository.GetCategoryParams) (repository.Category, error)
+	GetCategories(ctx context.Context, userID int64) ([]repository.Category, error)
+	GetUserHabitTags(ctx context.Context, userID int64) ([]repository.GetUserHabitTagsRow, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Transactor runs fn with queries inside a transaction, rolling back if fn
+// returns an error.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
 }
 
 type HabitEntryStore interface {
-	GetHabitEntries(id int64) ([]models.HabitEntry, error)
+	GetHabitEntries(habitId int64, dateRange models.DateRange, limit int64, schedule models.Schedule, target models.Target) (models.HabitEntriesPage, error)
+	GetUserHabitEntries(userId int64, dateRange models.DateRange) (map[int64][]models.HabitEntry, error)
+	CalculateCombosInRange(habitId int64, habitEntries []models.HabitEntry, dateRange models.DateRange, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error)
+}
+
+// EventPublisher tells a user's other devices about changes to their habits.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
 }
 
 type HabitService struct {
 	storage         HabitStorage
+	transactor      Transactor
 	logger          logger.Logger
 	habitEntryStore HabitEntryStore
+	events          EventPublisher
 }
 
-func NewHabitService(storage HabitStorage, logger logger.Logger, habitEntryStore HabitEntryStore) *HabitService {
+func NewHabitService(storage HabitStorage, transactor Transactor, logger logger.Logger, habitEntryStore HabitEntryStore, events EventPublisher) *HabitService {
 	return &HabitService{
 		storage:         storage,
+		transactor:      transactor,
 		logger:          logger,
 		habitEntryStore: habitEntryStore,
+		events:          events,
 	}
 }
 
-func (s HabitService) GetActiveHabits(userId int64) ([]Habit, error) {
-	habits, err := s.GetHabits(userId)
+func (s HabitService) IsHabitOwner(userId int64, habitId int64) (bool, error) {
+	ctx := context.Background()
+	habit, err := s.storage.GetHabit(ctx, habitId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return habit.UserID == userId, nil
+}
+
+func (s HabitService) GetActiveHabits(userId int64, dateRange models.DateRange) ([]Habit, error) {
+	habits, err := s.GetHabits(userId, dateRange)
 	if err != nil {
 		return nil, err
 	}
@@ -53,22 +112,58 @@ func (s HabitService) GetActiveHabits(userId int64) ([]Habit, error) {
 	return activeHabits, nil
 }
 
-func (s HabitService) GetHabits(userId int64) ([]Habit, error) {
+// GetHabits returns a user's habits that are not archived with their entries
+// within the date range.
+func (s HabitService) GetHabits(userId int64, dateRange models.DateRange) ([]Habit, error) {
+	return s.getHabits(userId, dateRange, false)
+}
+
+// GetArchivedHabits returns a user's archived habits with their entries within
+// the date range.
+func (s HabitService) GetArchivedHabits(userId int64, dateRange models.DateRange) ([]Habit, error) {
+	return s.getHabits(userId, dateRange, true)
+}
+
+func (s HabitService) getHabits(userId int64, dateRange models.DateRange, archived bool) ([]Habit, error) {
 	ctx := context.Background()
-	rawHabits, err := s.storage.GetHabits(ctx, userId)
+	allHabits, err := s.storage.GetHabits(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rawHabits := make([]repository.Habit, 0, len(allHabits))
+	for _, habit := range allHabits {
+		if habit.ArchivedAt.Valid == archived {
+			rawHabits = append(rawHabits, habit)
+		}
+	}
+
+	habitEntries, err := s.habitEntryStore.GetUserHabitEntries(userId, dateRange)
 	if err != nil {
 		return nil, err
 	}
 
+	rawHabitTags, err := s.storage.GetUserHabitTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	habitTags := models.NewHabitTagsFromStorage(rawHabitTags)
+
 	habits := make([]Habit, len(rawHabits))
 
 	for i, habit := range rawHabits {
-		entries, err := s.habitEntryStore.GetHabitEntries(habit.ID)
+		schedule := models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes)
+		target := models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison)
+		entries, err := s.habitEntryStore.CalculateCombosInRange(habit.ID, habitEntries[habit.ID], dateRange, schedule, target)
 		if err != nil {
 			return nil, err
 		}
 
-		habits[i] = NewHabit(habit.ID, habit.Name, habit.Colour, habit.Index, entries, habit.Active)
+		habits[i] = NewHabitFromStorage(habit, entries)
+		habits[i].Tags = habitTags[habit.ID]
+		if habits[i].Tags == nil {
+			habits[i].Tags = []models.Tag{}
+		}
 	}
 
 	sort.Slice(habits, func(i, j int) bool {
@@ -78,34 +173,290 @@ func (s HabitService) GetHabits(userId int64) ([]Habit, error) {
 	return habits, nil
 }
 
-func (s HabitService) UpdateHabits(habits []Habit) ([]Habit, error) {
+// FilterByCategory returns the habits in a category, or the habits without a
+// category when categoryId is 0.
+func FilterByCategory(habits []Habit, categoryId int64) []Habit {
+	filtered := make([]Habit, 0, len(habits))
+	for _, habit := range habits {
+		if (habit.CategoryId == nil && categoryId == 0) || (habit.CategoryId != nil && *habit.CategoryId == categoryId) {
+			filtered = append(filtered, habit)
+		}
+	}
+
+	return filtered
+}
+
+// FilterByTags returns the habits that have all of the tags.
+func FilterByTags(habits []Habit, tagIds []int64) []Habit {
+	filtered := make([]Habit, 0, len(habits))
+	for _, habit := range habits {
+		if hasTags(habit, tagIds) {
+			filtered = append(filtered, habit)
+		}
+	}
+
+	return filtered
+}
+
+func hasTags(habit Habit, tagIds []int64) bool {
+	for _, tagId := range tagIds {
+		if !slices.ContainsFunc(habit.Tags, func(tag models.Tag) bool { return tag.Id == tagId }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GroupByCategory groups habits by the user's categories in name order,
+// including empty categories, followed by any habits without a category.
+func (s HabitService) GroupByCategory(userId int64, habits []Habit) ([]HabitGroup, error) {
 	ctx := context.Background()
-	updatedHabits := make([]Habit, len(habits))
+	categories, err := s.storage.GetCategories(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]HabitGroup, 0, len(categories)+1)
+	for _, rawCategory := range categories {
+		category := models.NewCategoryFromStorage(rawCategory)
+		groups = append(groups, HabitGroup{Category: &category, Habits: FilterByCategory(habits, category.Id)})
+	}
+
+	if uncategorised := FilterByCategory(habits, 0); len(uncategorised) > 0 {
+		groups = append(groups, HabitGroup{Habits: uncategorised})
+	}
+
+	return groups, nil
+}
+
+// GetHabitEntries returns a page of a habit's entries within the date range.
+func (s HabitService) GetHabitEntries(habitId int64, dateRange models.DateRange, limit int64) (models.HabitEntriesPage, error) {
+	ctx := context.Background()
+	habit, err := s.storage.GetHabit(ctx, habitId)
+	if err != nil {
+		return models.HabitEntriesPage{}, err
+	}
+
+	schedule := models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes)
+	target := models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison)
+	return s.habitEntryStore.GetHabitEntries(habitId, dateRange, limit, schedule, target)
+}
+
+// UpdateHabits updates a user's habits in a single transaction, so either all
+// of them are updated or none are. Every habit must give the version it was
+// read at, habits without one are refused with ErrVersionRequired and those
+// with a version that is no longer current with ErrHabitConflict.
+func (s HabitService) UpdateHabits(ctx context.Context, userId int64, habits []Habit) ([]Habit, error) {
+	var updatedHabits []Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		updatedHabits, err = updateHabits(ctx, queries, userId, habits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishUpdates(userId, updatedHabits)
+	return updatedHabits, nil
+}
+
+func updateHabits(ctx context.Context, storage HabitStorage, userId int64, habits []Habit) ([]Habit, error) {
+	updatedHabits := make([]Habit, 0, len(habits))
 	for _, habit := range habits {
-		updatedHabit, err := s.storage.UpdateHabit(ctx, sqlite3Storage.UpdateHabitParams{
+		existingHabit, err := storage.GetHabit(ctx, habit.Id)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && existingHabit.UserID != userId) {
+			return nil, fmt.Errorf("%w: %d", ErrHabitNotFound, habit.Id)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if habit.Version == 0 {
+			return nil, fmt.Errorf("%w: %d", ErrVersionRequired, habit.Id)
+		}
+		if habit.Version != existingHabit.Version {
+			return nil, fmt.Errorf("%w: %d", ErrHabitConflict, habit.Id)
+		}
+
+		if err := validateDetails(ctx, storage, userId, &habit); err != nil {
+			return nil, err
+		}
+
+		params := repository.UpdateHabitParams{
 			Name:      habit.Name,
 			Colour:    habit.Colour,
 			Index:     habit.Index,
 			Active:    habit.Active,
-			UpdatedAt: time.Now().Format(time.RFC3339),
+			UpdatedAt: time.Now().UTC().Format(time.DateTime),
 			ID:        habit.Id,
-		})
+			Version:   existingHabit.Version,
+		}
+		// Details left out are kept, an empty description or no category clears them
+		if !habit.omitted.description {
+			params.Description = sql.NullString{String: habit.Description, Valid: true}
+		}
+		if !habit.omitted.icon {
+			params.Icon = sql.NullString{String: habit.Icon, Valid: true}
+		}
+		if !habit.omitted.categoryId {
+			params.CategoryID = sql.NullInt64{Valid: true}
+			if habit.CategoryId != nil {
+				params.CategoryID.Int64 = *habit.CategoryId
+			}
+		}
+		if !habit.Schedule.IsZero() {
+			params.ScheduleType = sql.NullString{String: string(habit.Schedule.Type), Valid: true}
+			params.ScheduleCount = sql.NullInt64{Int64: habit.Schedule.Count, Valid: true}
+			params.ScheduleWeekdays = sql.NullInt64{Int64: habit.Schedule.WeekdayMask(), Valid: true}
+			params.ScheduleFreezes = habit.Schedule.Freezes()
+		}
+		if !habit.Target.IsZero() {
+			params.TargetValue = sql.NullFloat64{Float64: habit.Target.Value, Valid: true}
+			params.TargetUnit = sql.NullString{String: strings.TrimSpace(habit.Target.Unit), Valid: true}
+			params.TargetComparison = sql.NullString{String: string(habit.Target.Comparison), Valid: true}
+		}
+
+		// The habit can still change between reading and updating it, which
+		// leaves nothing at the version read to update
+		updatedHabit, err := storage.UpdateHabit(ctx, params)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", ErrHabitConflict, habit.Id)
+		}
 		if err != nil {
-			return updatedHabits, err
+			return nil, err
 		}
 
-		updatedHabits = append(updatedHabits, NewHabit(
-			updatedHabit.ID, updatedHabit.Name, updatedHabit.Colour, updatedHabit.Index, nil, updatedHabit.Active))
+		updated := NewHabitFromStorage(updatedHabit, nil)
+		err = recordChange(ctx, storage, auditService.ActionUpdate, userId, habit.Id, NewHabitFromStorage(existingHabit, nil), updated)
+		if err != nil {
+			return nil, err
+		}
+
+		updatedHabits = append(updatedHabits, updated)
 	}
 
 	return updatedHabits, nil
 }
 
-func (s HabitService) CreateHabit(userId int64, name string, colour string) (Habit, error) {
-	ctx := context.Background()
-	habits, err := s.GetHabits(userId)
+// ReorderHabits moves a user's habits into the order of habitIds, numbering
+// their indexes from 1. Habits that are not listed keep their order after the
+// listed ones.
+func (s HabitService) ReorderHabits(ctx context.Context, userId int64, habitIds []int64) ([]Habit, error) {
+	var habits []Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habits, err = reorderHabits(ctx, queries, userId, habitIds)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishUpdates(userId, habits)
+	return habits, nil
+}
+
+func reorderHabits(ctx context.Context, storage HabitStorage, userId int64, habitIds []int64) ([]Habit, error) {
+	allHabits, err := storage.GetHabits(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Archived habits are hidden, so keep their positions as they are.
+	rawHabits := make([]repository.Habit, 0, len(allHabits))
+	for _, habit := range allHabits {
+		if !habit.ArchivedAt.Valid {
+			rawHabits = append(rawHabits, habit)
+		}
+	}
+
+	sort.SliceStable(rawHabits, func(i, j int) bool {
+		return rawHabits[i].Index < rawHabits[j].Index
+	})
+
+	habitsById := make(map[int64]repository.Habit, len(rawHabits))
+	for _, habit := range rawHabits {
+		habitsById[habit.ID] = habit
+	}
+
+	listed := make(map[int64]bool, len(habitIds))
+	ordered := make([]repository.Habit, 0, len(rawHabits))
+	for _, habitId := range habitIds {
+		habit, ok := habitsById[habitId]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrHabitNotFound, habitId)
+		}
+		if listed[habitId] {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidOrder, habitId)
+		}
+
+		listed[habitId] = true
+		ordered = append(ordered, habit)
+	}
+	for _, habit := range rawHabits {
+		if !listed[habit.ID] {
+			ordered = append(ordered, habit)
+		}
+	}
+
+	updatedAt := time.Now().UTC().Format(time.DateTime)
+	habits := make([]Habit, len(ordered))
+	for i, habit := range ordered {
+		before := NewHabitFromStorage(habit, nil)
+		habit.Index = int64(i + 1)
+		habits[i] = NewHabitFromStorage(habit, nil)
+		if before.Index == habit.Index {
+			continue
+		}
+
+		updated, err := storage.SetHabitIndex(ctx, repository.SetHabitIndexParams{
+			Index:     habit.Index,
+			UpdatedAt: updatedAt,
+			ID:        habit.ID,
+			UserID:    userId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if updated == 0 {
+			return nil, fmt.Errorf("%w: %d", ErrHabitNotFound, habit.ID)
+		}
+
+		err = recordChange(ctx, storage, auditService.ActionUpdate, userId, habit.ID, before, habits[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return habits, nil
+}
+
+// CreateHabit adds a habit to the end of a user's habits, defaulting to a daily
+// schedule and a target of 1 when none is given.
+func (s HabitService) CreateHabit(ctx context.Context, userId int64, habit Habit) (Habit, error) {
+	var createdHabit Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		createdHabit, err = createHabit(ctx, queries, userId, habit)
+		return err
+	})
+	if err != nil {
+		return Habit{}, err
+	}
+
+	s.events.Publish(userId, eventsService.HabitCreated, createdHabit)
+	return createdHabit, nil
+}
+
+func createHabit(ctx context.Context, storage HabitStorage, userId int64, habit Habit) (Habit, error) {
+	if err := validateDetails(ctx, storage, userId, &habit); err != nil {
+		return Habit{}, err
+	}
+
+	habits, err := storage.GetHabits(ctx, userId)
 	if err != nil {
-		s.logger.Error("Failed to get habits", slog.Any("error", err))
 		return Habit{}, err
 	}
 
@@ -116,27 +467,198 @@ func (s HabitService) CreateHabit(userId int64, name string, colour string) (Hab
 		}
 	}
 
-	createdHabit, err := s.storage.CreateHabit(ctx, sqlite3Storage.CreateHabitParams{
-		UserID: userId,
-		Name:   strings.TrimSpace(name),
-		Colour: strings.TrimSpace(colour),
-		Index:  highestIndex + 1,
+	schedule := habit.Schedule
+	if schedule.IsZero() {
+		schedule = models.NewDailySchedule()
+	}
+	target := habit.Target
+	if target.IsZero() {
+		target = models.NewDefaultTarget()
+	}
+
+	createdHabit, err := storage.CreateHabit(ctx, repository.CreateHabitParams{
+		UserID:           userId,
+		Name:             strings.TrimSpace(habit.Name),
+		Description:      sql.NullString{String: habit.Description, Valid: habit.Description != ""},
+		Icon:             habit.Icon,
+		CategoryID:       nullableCategoryId(habit.CategoryId),
+		Colour:           strings.TrimSpace(habit.Colour),
+		Index:            highestIndex + 1,
+		Active:           true,
+		ScheduleType:     string(schedule.Type),
+		ScheduleCount:    schedule.Count,
+		ScheduleWeekdays: schedule.WeekdayMask(),
+		ScheduleFreezes:  schedule.Freezes(),
+		TargetValue:      target.Value,
+		TargetUnit:       strings.TrimSpace(target.Unit),
+		TargetComparison: string(target.Comparison),
 	})
 
 	if err != nil {
 		return Habit{}, err
 	}
 
-	return NewHabit(createdHabit.ID, createdHabit.Name, createdHabit.Colour, createdHabit.Index, nil, createdHabit.Active), nil
+	created := NewHabitFromStorage(createdHabit, nil)
+	err = recordChange(ctx, storage, auditService.ActionCreate, userId, created.Id, nil, created)
+	if err != nil {
+		return Habit{}, err
+	}
+
+	return created, nil
 }
 
-func (s HabitService) DeleteHabit(habitId int64) (Habit, error) {
-	ctx := context.Background()
+// validateDetails trims a habit's description and icon, checking their lengths
+// and that its category belongs to the user.
+func validateDetails(ctx context.Context, storage HabitStorage, userId int64, habit *Habit) error {
+	habit.Description = strings.TrimSpace(habit.Description)
+	if utf8.RuneCountInString(habit.Description) > MaxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+
+	habit.Icon = strings.TrimSpace(habit.Icon)
+	if utf8.RuneCountInString(habit.Icon) > MaxIconLength {
+		return ErrIconTooLong
+	}
+
+	if habit.CategoryId == nil {
+		return nil
+	}
+
+	_, err := storage.GetCategory(ctx, repository.GetCategoryParams{ID: *habit.CategoryId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %d", ErrCategoryNotFound, *habit.CategoryId)
+	}
+
+	return err
+}
+
+func nullableCategoryId(id *int64) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *id, Valid: true}
+}
+
+// ArchiveHabit hides one of a user's habits, keeping its entries and stats.
+func (s HabitService) ArchiveHabit(ctx context.Context, userId int64, habitId int64) (Habit, error) {
+	archivedAt := sql.NullString{String: time.Now().UTC().Format(time.DateTime), Valid: true}
+	return s.setArchivedAt(ctx, userId, habitId, archivedAt)
+}
+
+// UnarchiveHabit shows one of a user's archived habits again.
+func (s HabitService) UnarchiveHabit(ctx context.Context, userId int64, habitId int64) (Habit, error) {
+	return s.setArchivedAt(ctx, userId, habitId, sql.NullString{})
+}
+
+func (s HabitService) setArchivedAt(ctx context.Context, userId int64, habitId int64, archivedAt sql.NullString) (Habit, error) {
+	var habit Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habit, err = setArchivedAt(ctx, queries, userId, habitId, archivedAt)
+		return err
+	})
+	if err != nil {
+		return Habit{}, err
+	}
+
+	s.events.Publish(userId, eventsService.HabitUpdated, habit)
+	return habit, nil
+}
+
+func setArchivedAt(ctx context.Context, storage HabitStorage, userId int64, habitId int64, archivedAt sql.NullString) (Habit, error) {
+	existingHabit, err := storage.GetHabit(ctx, habitId)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && existingHabit.UserID != userId) {
+		return Habit{}, ErrHabitNotFound
+	}
+	if err != nil {
+		return Habit{}, err
+	}
+
+	updatedHabit, err := storage.SetHabitArchivedAt(ctx, repository.SetHabitArchivedAtParams{
+		ArchivedAt: archivedAt,
+		UpdatedAt:  time.Now().UTC().Format(time.DateTime),
+		ID:         habitId,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Habit{}, ErrHabitNotFound
+	}
+	if err != nil {
+		return Habit{}, err
+	}
+
+	habit := NewHabitFromStorage(updatedHabit, nil)
+	err = recordChange(ctx, storage, auditService.ActionUpdate, userId, habitId, NewHabitFromStorage(existingHabit, nil), habit)
+	if err != nil {
+		return Habit{}, err
+	}
+
+	return habit, nil
+}
+
+// PurgeHabit moves an archived habit and its entries to the trash, where they
+// are permanently deleted once the trash retention period has passed.
+func (s HabitService) PurgeHabit(ctx context.Context, userId int64, habitId int64) (Habit, error) {
+	var habit Habit
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habit, err = purgeHabit(ctx, queries, userId, habitId)
+		return err
+	})
+	if err != nil {
+		return Habit{}, err
+	}
+
+	s.events.Publish(userId, eventsService.HabitDeleted, eventsService.DeletedHabit{Id: habitId})
+	return habit, nil
+}
+
+func purgeHabit(ctx context.Context, storage HabitStorage, userId int64, habitId int64) (Habit, error) {
+	habit, err := storage.GetHabit(ctx, habitId)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && habit.UserID != userId) {
+		return Habit{}, ErrHabitNotFound
+	}
+	if err != nil {
+		return Habit{}, err
+	}
+	if !habit.ArchivedAt.Valid {
+		return Habit{}, ErrHabitNotArchived
+	}
+
+	deletedAt := time.Now().UTC().Format(time.DateTime)
+	deletedHabit, err := storage.TrashHabit(ctx, repository.TrashHabitParams{
+		DeletedAt: sql.NullString{String: deletedAt, Valid: true},
+		UpdatedAt: deletedAt,
+		ID:        habitId,
+	})
+	if err != nil {
+		return Habit{}, err
+	}
 
-	deletedHabit, err := s.storage.DeleteHabit(ctx, habitId)
+	err = recordChange(ctx, storage, auditService.ActionDelete, userId, habitId, NewHabitFromStorage(habit, nil), nil)
 	if err != nil {
 		return Habit{}, err
 	}
 
-	return NewHabit(deletedHabit.ID, deletedHabit.Name, deletedHabit.Colour, deletedHabit.Index, nil, deletedHabit.Active), nil
+	return NewHabitFromStorage(deletedHabit, nil), nil
+}
+
+// publishUpdates tells the user's other devices about their updated habits.
+func (s HabitService) publishUpdates(userId int64, habits []Habit) {
+	for _, habit := range habits {
+		s.events.Publish(userId, eventsService.HabitUpdated, habit)
+	}
+}
+
+// recordChange records a change to a habit in the audit log. before is nil
+// for creates and after is nil for deletes.
+func recordChange(ctx context.Context, storage HabitStorage, action string, userId int64, habitId int64, before any, after any) error {
+	return auditService.Record(ctx, storage, auditService.Change{
+		Before:     before,
+		After:      after,
+		EntityType: auditService.EntityHabit,
+		Action:     action,
+		UserId:     userId,
+		EntityId:   habitId,
+	})
 }
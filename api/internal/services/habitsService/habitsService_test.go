@@ -2,65 +2,180 @@ package habitsService
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ReidMason/habit-tracker/internal/logger"
 	"github.com/ReidMason/habit-tracker/internal/services/models"
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockHabitStorage struct {
-	err    error
-	habits []sqlite3Storage.Habit
+	err         error
+	auditEvents *[]repository.CreateAuditEventParams
+	habits      []repository.Habit
+	categories  []repository.Category
+	habitTags   []repository.GetUserHabitTagsRow
 }
 
-func (m mockHabitStorage) GetHabits(_ context.Context, _ int64) ([]sqlite3Storage.Habit, error) {
+func (m mockHabitStorage) GetHabit(_ context.Context, id int64) (repository.Habit, error) {
+	for _, habit := range m.habits {
+		if habit.ID == id {
+			return habit, m.err
+		}
+	}
+
+	return repository.Habit{}, sql.ErrNoRows
+}
+
+func (m mockHabitStorage) GetHabits(_ context.Context, _ int64) ([]repository.Habit, error) {
 	return m.habits, m.err
 }
 
-func (m mockHabitStorage) UpdateHabit(_ context.Context, _ sqlite3Storage.UpdateHabitParams) (sqlite3Storage.Habit, error) {
-	return sqlite3Storage.Habit{}, nil
+func (m mockHabitStorage) UpdateHabit(ctx context.Context, arg repository.UpdateHabitParams) (repository.Habit, error) {
+	habit, err := m.GetHabit(ctx, arg.ID)
+	if err != nil || habit.Version != arg.Version {
+		return repository.Habit{}, sql.ErrNoRows
+	}
+
+	if arg.Description.Valid {
+		habit.Description = sql.NullString{String: arg.Description.String, Valid: arg.Description.String != ""}
+	}
+	if arg.Icon.Valid {
+		habit.Icon = arg.Icon.String
+	}
+	if arg.CategoryID.Valid {
+		habit.CategoryID = sql.NullInt64{Int64: arg.CategoryID.Int64, Valid: arg.CategoryID.Int64 != 0}
+	}
+
+	return repository.Habit{ID: arg.ID, Name: arg.Name, Description: habit.Description, Icon: habit.Icon, CategoryID: habit.CategoryID, Colour: arg.Colour, Index: arg.Index, Active: arg.Active, Version: arg.Version + 1}, m.err
 }
 
-func (m mockHabitStorage) CreateHabit(_ context.Context, habit sqlite3Storage.CreateHabitParams) (sqlite3Storage.Habit, error) {
-	return sqlite3Storage.Habit{
-		ID:     2,
-		Name:   habit.Name,
-		Colour: habit.Colour,
-		Active: true,
-		Index:  habit.Index,
+func (m mockHabitStorage) CreateHabit(_ context.Context, habit repository.CreateHabitParams) (repository.Habit, error) {
+	return repository.Habit{
+		ID:               2,
+		Name:             habit.Name,
+		Description:      habit.Description,
+		Icon:             habit.Icon,
+		CategoryID:       habit.CategoryID,
+		Colour:           habit.Colour,
+		Active:           true,
+		Index:            habit.Index,
+		ScheduleType:     habit.ScheduleType,
+		ScheduleCount:    habit.ScheduleCount,
+		ScheduleWeekdays: habit.ScheduleWeekdays,
+		TargetValue:      habit.TargetValue,
+		TargetUnit:       habit.TargetUnit,
+		TargetComparison: habit.TargetComparison,
 	}, nil
 }
 
-func (m mockHabitStorage) DeleteHabit(_ context.Context, _ int64) (sqlite3Storage.Habit, error) {
-	return sqlite3Storage.Habit{}, nil
+func (m mockHabitStorage) TrashHabit(_ context.Context, _ repository.TrashHabitParams) (repository.Habit, error) {
+	return repository.Habit{}, nil
+}
+
+func (m mockHabitStorage) SetHabitIndex(_ context.Context, arg repository.SetHabitIndexParams) (int64, error) {
+	for _, habit := range m.habits {
+		if habit.ID == arg.ID && habit.UserID == arg.UserID {
+			return 1, m.err
+		}
+	}
+
+	return 0, m.err
+}
+
+func (m mockHabitStorage) SetHabitArchivedAt(ctx context.Context, arg repository.SetHabitArchivedAtParams) (repository.Habit, error) {
+	habit, err := m.GetHabit(ctx, arg.ID)
+	habit.ArchivedAt = arg.ArchivedAt
+	return habit, err
+}
+
+func (m mockHabitStorage) GetCategory(_ context.Context, arg repository.GetCategoryParams) (repository.Category, error) {
+	for _, category := range m.categories {
+		if category.ID == arg.ID && category.UserID == arg.UserID {
+			return category, m.err
+		}
+	}
+
+	return repository.Category{}, sql.ErrNoRows
+}
+
+func (m mockHabitStorage) GetCategories(_ context.Context, _ int64) ([]repository.Category, error) {
+	return m.categories, m.err
+}
+
+func (m mockHabitStorage) GetUserHabitTags(_ context.Context, _ int64) ([]repository.GetUserHabitTagsRow, error) {
+	return m.habitTags, m.err
+}
+
+func (m mockHabitStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	if m.auditEvents != nil {
+		*m.auditEvents = append(*m.auditEvents, arg)
+	}
+
+	return repository.AuditEvent{}, m.err
 }
 
 type mockHabitEntryStore struct{}
 
 type mockHabitEntryStorage struct{}
 
-func (m mockHabitEntryStorage) GetHabitEntries(id int64) ([]models.HabitEntry, error) {
+func (m mockHabitEntryStorage) GetHabitEntries(habitId int64, dateRange models.DateRange, limit int64, schedule models.Schedule, target models.Target) (models.HabitEntriesPage, error) {
+	return models.HabitEntriesPage{}, nil
+}
+
+func (m mockHabitEntryStorage) GetUserHabitEntries(userId int64, dateRange models.DateRange) (map[int64][]models.HabitEntry, error) {
 	return nil, nil
 }
 
+func (m mockHabitEntryStorage) CalculateCombosInRange(habitId int64, habitEntries []models.HabitEntry, dateRange models.DateRange, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error) {
+	return append([]models.HabitEntry{}, habitEntries...), nil
+}
+
 func TestGetActiveHabits(t *testing.T) {
 	tests := []struct {
 		name           string
-		habits         []sqlite3Storage.Habit
+		habits         []repository.Habit
+		habitTags      []repository.GetUserHabitTagsRow
 		expectedHabits []Habit
 	}{
 		{
 			name: "returns only active habits",
-			habits: []sqlite3Storage.Habit{
-				{ID: 1, Name: "Habit 1", Active: true},
-				{ID: 2, Name: "Habit 2", Active: false},
-				{ID: 3, Name: "Habit 3", Active: true},
+			habits: []repository.Habit{
+				{ID: 1, Name: "Habit 1", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+				{ID: 2, Name: "Habit 2", Active: false, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+				{ID: 3, Name: "Habit 3", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+			},
+			expectedHabits: []Habit{
+				{Id: 1, Name: "Habit 1", Active: true, Entries: []models.HabitEntry{}, Tags: []models.Tag{}, Schedule: models.NewDailySchedule(), Target: models.NewDefaultTarget()},
+				{Id: 3, Name: "Habit 3", Active: true, Entries: []models.HabitEntry{}, Tags: []models.Tag{}, Schedule: models.NewDailySchedule(), Target: models.NewDefaultTarget()},
+			},
+		},
+		{
+			name: "hides archived habits",
+			habits: []repository.Habit{
+				{ID: 1, Name: "Habit 1", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+				{ID: 2, Name: "Habit 2", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast", ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true}},
+			},
+			expectedHabits: []Habit{
+				{Id: 1, Name: "Habit 1", Active: true, Entries: []models.HabitEntry{}, Tags: []models.Tag{}, Schedule: models.NewDailySchedule(), Target: models.NewDefaultTarget()},
 			},
+		},
+		{
+			name: "includes each habit's tags",
+			habits: []repository.Habit{
+				{ID: 1, Name: "Habit 1", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+				{ID: 2, Name: "Habit 2", Active: true, ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"},
+			},
+			habitTags: []repository.GetUserHabitTagsRow{{HabitID: 2, ID: 4, Name: "health"}, {HabitID: 2, ID: 3, Name: "morning"}},
 			expectedHabits: []Habit{
-				{Id: 1, Name: "Habit 1", Active: true},
-				{Id: 3, Name: "Habit 3", Active: true},
+				{Id: 1, Name: "Habit 1", Active: true, Entries: []models.HabitEntry{}, Tags: []models.Tag{}, Schedule: models.NewDailySchedule(), Target: models.NewDefaultTarget()},
+				{Id: 2, Name: "Habit 2", Active: true, Entries: []models.HabitEntry{}, Tags: []models.Tag{models.NewTag(4, "health"), models.NewTag(3, "morning")}, Schedule: models.NewDailySchedule(), Target: models.NewDefaultTarget()},
 			},
 		},
 	}
@@ -69,12 +184,13 @@ func TestGetActiveHabits(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			storage := mockHabitStorage{
-				habits: tc.habits,
+				habits:    tc.habits,
+				habitTags: tc.habitTags,
 			}
-			service := NewHabitService(storage, &logger.MockLogger{}, &mockHabitEntryStorage{})
+			service := NewHabitService(storage, nil, &logger.MockLogger{}, &mockHabitEntryStorage{}, nil)
 
 			// Act
-			habits, err := service.GetActiveHabits(1)
+			habits, err := service.GetActiveHabits(1, models.DateRange{})
 
 			// Assert
 			if err != nil {
@@ -95,12 +211,18 @@ func TestGetActiveHabits(t *testing.T) {
 
 func TestCreateHabit(t *testing.T) {
 	tests := []struct {
-		newHabitName   string
-		newHabitColour string
-		name           string
-		habits         []sqlite3Storage.Habit
-		expectedHabit  Habit
-		newHabitId     int64
+		newHabitName        string
+		newHabitColour      string
+		newHabitDescription string
+		newHabitIcon        string
+		name                string
+		habits              []repository.Habit
+		newHabitSchedule    models.Schedule
+		newHabitTarget      models.Target
+		newHabitCategoryId  *int64
+		expectedErr         error
+		expectedHabit       Habit
+		newHabitId          int64
 	}{
 		{
 			name:           "creates a habit",
@@ -108,13 +230,15 @@ func TestCreateHabit(t *testing.T) {
 			newHabitColour: " #ffffff ",
 			newHabitId:     1,
 			expectedHabit: Habit{
-				Id:     2,
-				Name:   "Habit 2",
-				Colour: "#ffffff",
-				Active: true,
-				Index:  2,
+				Id:       2,
+				Name:     "Habit 2",
+				Colour:   "#ffffff",
+				Active:   true,
+				Index:    2,
+				Schedule: models.NewDailySchedule(),
+				Target:   models.NewDefaultTarget(),
 			},
-			habits: []sqlite3Storage.Habit{
+			habits: []repository.Habit{
 				{
 					ID:     1,
 					Name:   "Habit 1",
@@ -124,6 +248,138 @@ func TestCreateHabit(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "creates a habit with a weekday schedule",
+			newHabitName:   "Weekly review",
+			newHabitColour: "#ffffff",
+			newHabitId:     1,
+			newHabitSchedule: models.Schedule{
+				Type:     models.ScheduleWeekdays,
+				Weekdays: []time.Weekday{time.Sunday},
+			},
+			expectedHabit: Habit{
+				Id:     2,
+				Name:   "Weekly review",
+				Colour: "#ffffff",
+				Active: true,
+				Index:  1,
+				Schedule: models.Schedule{
+					Type:     models.ScheduleWeekdays,
+					Weekdays: []time.Weekday{time.Sunday},
+				},
+				Target: models.NewDefaultTarget(),
+			},
+		},
+		{
+			name:             "creates a measurable habit",
+			newHabitName:     "Water",
+			newHabitColour:   "#ffffff",
+			newHabitId:       1,
+			newHabitSchedule: models.NewDailySchedule(),
+			newHabitTarget:   models.Target{Value: 8, Unit: " glasses ", Comparison: models.TargetAtLeast},
+			expectedHabit: Habit{
+				Id:       2,
+				Name:     "Water",
+				Colour:   "#ffffff",
+				Active:   true,
+				Index:    1,
+				Schedule: models.NewDailySchedule(),
+				Target:   models.Target{Value: 8, Unit: "glasses", Comparison: models.TargetAtLeast},
+			},
+		},
+		{
+			name:                "creates a habit with a description, icon and category",
+			newHabitName:        "Stretch",
+			newHabitColour:      "#ffffff",
+			newHabitDescription: " Ten minutes after waking up ",
+			newHabitIcon:        " 🧘 ",
+			newHabitCategoryId:  categoryId(1),
+			newHabitId:          1,
+			expectedHabit: Habit{
+				Id:          2,
+				Name:        "Stretch",
+				Description: "Ten minutes after waking up",
+				Icon:        "🧘",
+				CategoryId:  categoryId(1),
+				Colour:      "#ffffff",
+				Active:      true,
+				Index:       1,
+				Schedule:    models.NewDailySchedule(),
+				Target:      models.NewDefaultTarget(),
+			},
+		},
+		{
+			name:               "rejects another user's category",
+			newHabitName:       "Stretch",
+			newHabitCategoryId: categoryId(2),
+			newHabitId:         1,
+			expectedErr:        ErrCategoryNotFound,
+		},
+		{
+			name:         "rejects a long icon",
+			newHabitName: "Stretch",
+			newHabitIcon: strings.Repeat("🧘", MaxIconLength+1),
+			newHabitId:   1,
+			expectedErr:  ErrIconTooLong,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := mockHabitStorage{
+				habits:     tc.habits,
+				categories: []repository.Category{{ID: 1, UserID: 1, Name: "Health"}, {ID: 2, UserID: 2, Name: "Health"}},
+			}
+			newHabit := Habit{
+				Name:        tc.newHabitName,
+				Description: tc.newHabitDescription,
+				Icon:        tc.newHabitIcon,
+				CategoryId:  tc.newHabitCategoryId,
+				Colour:      tc.newHabitColour,
+				Schedule:    tc.newHabitSchedule,
+				Target:      tc.newHabitTarget,
+			}
+
+			// Act
+			habit, err := createHabit(context.Background(), storage, tc.newHabitId, newHabit)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, habit, tc.expectedHabit, "expected habit %v but got %v", tc.expectedHabit, habit)
+		})
+	}
+}
+
+func TestIsHabitOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		habits   []repository.Habit
+		userId   int64
+		habitId  int64
+		expected bool
+	}{
+		{
+			name:     "owner of the habit",
+			habits:   []repository.Habit{{ID: 1, UserID: 1}},
+			userId:   1,
+			habitId:  1,
+			expected: true,
+		},
+		{
+			name:     "habit belongs to another user",
+			habits:   []repository.Habit{{ID: 1, UserID: 2}},
+			userId:   1,
+			habitId:  1,
+			expected: false,
+		},
+		{
+			name:     "habit does not exist",
+			habits:   []repository.Habit{},
+			userId:   1,
+			habitId:  1,
+			expected: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -132,17 +388,351 @@ func TestCreateHabit(t *testing.T) {
 			storage := mockHabitStorage{
 				habits: tc.habits,
 			}
-			service := NewHabitService(storage, &logger.MockLogger{}, &mockHabitEntryStorage{})
+			service := NewHabitService(storage, nil, &logger.MockLogger{}, &mockHabitEntryStorage{}, nil)
 
 			// Act
-			habit, err := service.CreateHabit(tc.newHabitId, tc.newHabitName, tc.newHabitColour)
+			isOwner, err := service.IsHabitOwner(tc.userId, tc.habitId)
 
 			// Assert
 			if err != nil {
 				t.Errorf("expected no error but got: %v", err)
 			}
 
-			assert.Equal(t, habit, tc.expectedHabit, "expected habit %v but got %v", tc.expectedHabit, habit)
+			assert.Equal(t, tc.expected, isOwner)
+		})
+	}
+}
+
+func TestUpdateHabits(t *testing.T) {
+	tests := []struct {
+		name           string
+		habits         []repository.Habit
+		updates        []Habit
+		expectedErr    error
+		expectedHabits []Habit
+		expectedAudits []int64
+	}{
+		{
+			name:    "updates every habit",
+			habits:  []repository.Habit{{ID: 1, UserID: 1, Version: 1}, {ID: 2, UserID: 1, Version: 1}},
+			updates: []Habit{{Id: 1, Name: "Run", Index: 2, Version: 1}, {Id: 2, Name: "Read", Index: 1, Version: 1}},
+			expectedHabits: []Habit{
+				NewHabitFromStorage(repository.Habit{ID: 1, Name: "Run", Index: 2, Version: 2}, nil),
+				NewHabitFromStorage(repository.Habit{ID: 2, Name: "Read", Index: 1, Version: 2}, nil),
+			},
+			expectedAudits: []int64{1, 2},
+		},
+		{
+			name:           "updates a habit at its current version",
+			habits:         []repository.Habit{{ID: 1, UserID: 1, Version: 3}},
+			updates:        []Habit{{Id: 1, Name: "Run", Version: 3}},
+			expectedHabits: []Habit{NewHabitFromStorage(repository.Habit{ID: 1, Name: "Run", Version: 4}, nil)},
+			expectedAudits: []int64{1},
+		},
+		{
+			name:        "rejects a habit that has changed since it was read",
+			habits:      []repository.Habit{{ID: 1, UserID: 1, Version: 3}, {ID: 2, UserID: 1, Version: 1}},
+			updates:     []Habit{{Id: 2, Name: "Read", Version: 1}, {Id: 1, Name: "Run", Version: 2}},
+			expectedErr: ErrHabitConflict,
+		},
+		{
+			name:        "rejects a habit without a version",
+			habits:      []repository.Habit{{ID: 1, UserID: 1, Version: 3}},
+			updates:     []Habit{{Id: 1, Name: "Run"}},
+			expectedErr: ErrVersionRequired,
+		},
+		{
+			name:    "updates the description, icon and category",
+			habits:  []repository.Habit{{ID: 1, UserID: 1, Version: 1}},
+			updates: []Habit{{Id: 1, Name: "Run", Description: "5k", Icon: "🏃", CategoryId: categoryId(1), Version: 1}},
+			expectedHabits: []Habit{
+				{Id: 1, Name: "Run", Description: "5k", Icon: "🏃", CategoryId: categoryId(1), Version: 2, Schedule: models.NewScheduleFromStorage("", 0, 0, sql.NullInt64{})},
+			},
+			expectedAudits: []int64{1},
+		},
+		{
+			name:        "rejects a category that does not exist",
+			habits:      []repository.Habit{{ID: 1, UserID: 1, Version: 1}},
+			updates:     []Habit{{Id: 1, Name: "Run", CategoryId: categoryId(3), Version: 1}},
+			expectedErr: ErrCategoryNotFound,
+		},
+		{
+			name:        "rejects a habit belonging to another user",
+			habits:      []repository.Habit{{ID: 1, UserID: 1, Version: 1}, {ID: 2, UserID: 2, Version: 1}},
+			updates:     []Habit{{Id: 1, Name: "Run", Version: 1}, {Id: 2, Name: "Read", Version: 1}},
+			expectedErr: ErrHabitNotFound,
+		},
+		{
+			name:        "rejects a habit that does not exist",
+			habits:      []repository.Habit{{ID: 1, UserID: 1}},
+			updates:     []Habit{{Id: 3, Name: "Run"}},
+			expectedErr: ErrHabitNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			auditEvents := make([]repository.CreateAuditEventParams, 0)
+			storage := mockHabitStorage{habits: tc.habits, categories: []repository.Category{{ID: 1, UserID: 1}}, auditEvents: &auditEvents}
+
+			// Act
+			habits, err := updateHabits(context.Background(), storage, 1, tc.updates)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedHabits, habits)
+			if tc.expectedErr != nil {
+				return
+			}
+			audited := make([]int64, len(auditEvents))
+			for i, event := range auditEvents {
+				assert.Equal(t, "update", event.Action)
+				assert.True(t, event.DataBefore.Valid)
+				assert.True(t, event.DataAfter.Valid)
+				audited[i] = event.EntityID
+			}
+			assert.Equal(t, tc.expectedAudits, audited)
+		})
+	}
+}
+
+func TestUpdateHabitsKeepsOmittedDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		update         string
+		expectedHabits []Habit
+	}{
+		{
+			name:   "keeps the description, icon and category when they are left out",
+			update: `[{"id": 1, "name": "Run", "colour": "#fff", "index": 2, "active": true, "version": 1}]`,
+			expectedHabits: []Habit{
+				NewHabitFromStorage(repository.Habit{ID: 1, Name: "Run", Description: sql.NullString{String: "5k", Valid: true}, Icon: "🏃", CategoryID: sql.NullInt64{Int64: 1, Valid: true}, Colour: "#fff", Index: 2, Active: true, Version: 2}, nil),
+			},
+		},
+		{
+			name:   "clears the description, icon and category when they are given empty",
+			update: `[{"id": 1, "name": "Run", "description": "", "icon": "", "categoryId": null, "version": 1}]`,
+			expectedHabits: []Habit{
+				NewHabitFromStorage(repository.Habit{ID: 1, Name: "Run", Version: 2}, nil),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			auditEvents := make([]repository.CreateAuditEventParams, 0)
+			storage := mockHabitStorage{
+				habits: []repository.Habit{
+					{ID: 1, UserID: 1, Name: "Run", Description: sql.NullString{String: "5k", Valid: true}, Icon: "🏃", CategoryID: sql.NullInt64{Int64: 1, Valid: true}, Version: 1},
+				},
+				categories:  []repository.Category{{ID: 1, UserID: 1}},
+				auditEvents: &auditEvents,
+			}
+			var updates []Habit
+			require.NoError(t, json.Unmarshal([]byte(tc.update), &updates))
+
+			// Act
+			habits, err := updateHabits(context.Background(), storage, 1, updates)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHabits, habits)
 		})
 	}
 }
+
+func TestReorderHabits(t *testing.T) {
+	tests := []struct {
+		name          string
+		habits        []repository.Habit
+		habitIds      []int64
+		expectedErr   error
+		expectedOrder []int64
+	}{
+		{
+			name:          "numbers habits in the given order",
+			habits:        []repository.Habit{{ID: 1, UserID: 1, Index: 1}, {ID: 2, UserID: 1, Index: 5}, {ID: 3, UserID: 1, Index: 9}},
+			habitIds:      []int64{3, 1, 2},
+			expectedOrder: []int64{3, 1, 2},
+		},
+		{
+			name:          "keeps habits that are not listed after the listed ones",
+			habits:        []repository.Habit{{ID: 1, UserID: 1, Index: 3}, {ID: 2, UserID: 1, Index: 1}, {ID: 3, UserID: 1, Index: 2}},
+			habitIds:      []int64{1},
+			expectedOrder: []int64{1, 2, 3},
+		},
+		{
+			name:        "rejects a habit belonging to another user",
+			habits:      []repository.Habit{{ID: 1, UserID: 1}},
+			habitIds:    []int64{1, 2},
+			expectedErr: ErrHabitNotFound,
+		},
+		{
+			name:        "rejects a habit listed twice",
+			habits:      []repository.Habit{{ID: 1, UserID: 1}, {ID: 2, UserID: 1}},
+			habitIds:    []int64{1, 2, 1},
+			expectedErr: ErrInvalidOrder,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := mockHabitStorage{habits: tc.habits}
+
+			// Act
+			habits, err := reorderHabits(context.Background(), storage, 1, tc.habitIds)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+			for i, habit := range habits {
+				assert.Equal(t, tc.expectedOrder[i], habit.Id)
+				assert.Equal(t, int64(i+1), habit.Index)
+			}
+		})
+	}
+}
+
+func TestGetArchivedHabits(t *testing.T) {
+	// Arrange
+	storage := mockHabitStorage{
+		habits: []repository.Habit{
+			{ID: 1, Name: "Habit 1", Active: true},
+			{ID: 2, Name: "Habit 2", Active: true, ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true}},
+		},
+	}
+	service := NewHabitService(storage, nil, &logger.MockLogger{}, &mockHabitEntryStorage{}, nil)
+
+	// Act
+	habits, err := service.GetArchivedHabits(1, models.DateRange{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, habits, 1)
+	assert.Equal(t, int64(2), habits[0].Id)
+	assert.Equal(t, time.Date(2024, 12, 18, 10, 0, 0, 0, time.UTC), *habits[0].ArchivedAt)
+}
+
+func TestPurgeHabit(t *testing.T) {
+	tests := []struct {
+		name        string
+		habits      []repository.Habit
+		expectedErr error
+	}{
+		{
+			name:   "purges an archived habit",
+			habits: []repository.Habit{{ID: 1, UserID: 1, ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true}}},
+		},
+		{
+			name:        "refuses to purge a habit that is not archived",
+			habits:      []repository.Habit{{ID: 1, UserID: 1}},
+			expectedErr: ErrHabitNotArchived,
+		},
+		{
+			name:        "habit belongs to another user",
+			habits:      []repository.Habit{{ID: 1, UserID: 2, ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true}}},
+			expectedErr: ErrHabitNotFound,
+		},
+		{
+			name:        "habit does not exist",
+			habits:      []repository.Habit{},
+			expectedErr: ErrHabitNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			auditEvents := make([]repository.CreateAuditEventParams, 0)
+			storage := mockHabitStorage{habits: tc.habits, auditEvents: &auditEvents}
+
+			// Act
+			_, err := purgeHabit(context.Background(), storage, 1, 1)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				assert.Empty(t, auditEvents)
+				return
+			}
+			assert.Len(t, auditEvents, 1)
+			assert.Equal(t, "delete", auditEvents[0].Action)
+			assert.False(t, auditEvents[0].DataAfter.Valid)
+		})
+	}
+}
+
+func TestGroupByCategory(t *testing.T) {
+	// Arrange
+	storage := mockHabitStorage{categories: []repository.Category{{ID: 2, UserID: 1, Name: "Health"}, {ID: 1, UserID: 1, Name: "Work"}}}
+	service := NewHabitService(storage, nil, &logger.MockLogger{}, &mockHabitEntryStorage{}, nil)
+	habits := []Habit{{Id: 1, CategoryId: categoryId(1)}, {Id: 2}, {Id: 3, CategoryId: categoryId(1)}}
+
+	// Act
+	groups, err := service.GroupByCategory(1, habits)
+	uncategorised := FilterByCategory(habits, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, groups, 3)
+	assert.Equal(t, "Health", groups[0].Category.Name)
+	assert.Empty(t, groups[0].Habits)
+	assert.Equal(t, "Work", groups[1].Category.Name)
+	assert.Equal(t, []Habit{habits[0], habits[2]}, groups[1].Habits)
+	assert.Nil(t, groups[2].Category)
+	assert.Equal(t, []Habit{habits[1]}, groups[2].Habits)
+	assert.Equal(t, []Habit{habits[1]}, uncategorised)
+}
+
+func TestFilterByTags(t *testing.T) {
+	habits := []Habit{
+		{Id: 1, Tags: []models.Tag{models.NewTag(1, "morning"), models.NewTag(2, "health")}},
+		{Id: 2, Tags: []models.Tag{models.NewTag(1, "morning")}},
+		{Id: 3, Tags: []models.Tag{}},
+	}
+
+	tests := []struct {
+		name        string
+		tagIds      []int64
+		expectedIds []int64
+	}{
+		{
+			name:        "returns the habits with a tag",
+			tagIds:      []int64{1},
+			expectedIds: []int64{1, 2},
+		},
+		{
+			name:        "returns the habits with all of the tags",
+			tagIds:      []int64{1, 2},
+			expectedIds: []int64{1},
+		},
+		{
+			name:        "returns every habit without tags to filter by",
+			tagIds:      []int64{},
+			expectedIds: []int64{1, 2, 3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			filtered := FilterByTags(habits, tc.tagIds)
+
+			// Assert
+			ids := make([]int64, len(filtered))
+			for i, habit := range filtered {
+				ids[i] = habit.Id
+			}
+			assert.Equal(t, tc.expectedIds, ids)
+		})
+	}
+}
+
+func categoryId(id int64) *int64 {
+	return &id
+}
@@ -1,23 +1,94 @@
 package habitsService
 
-import "github.com/ReidMason/habit-tracker/internal/services/models"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
 
 type Habit struct {
-	Name    string              `json:"name"`
-	Colour  string              `json:"colour"`
-	Entries []models.HabitEntry `json:"entries"`
-	Id      int64               `json:"id"`
-	Index   int64               `json:"index"`
-	Active  bool                `json:"active"`
+	ArchivedAt  *time.Time          `json:"archivedAt,omitempty"`
+	CategoryId  *int64              `json:"categoryId"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Icon        string              `json:"icon"`
+	Colour      string              `json:"colour"`
+	Entries     []models.HabitEntry `json:"entries"`
+	Tags        []models.Tag        `json:"tags"`
+	Schedule    models.Schedule     `json:"schedule"`
+	Target      models.Target       `json:"target"`
+	Id          int64               `json:"id"`
+	Index       int64               `json:"index"`
+	Version     int64               `json:"version"`
+	Active      bool                `json:"active"`
+	omitted     omittedDetails
+}
+
+// omittedDetails are the details left out of the JSON a habit was decoded
+// from, which updates keep as they are rather than clearing.
+type omittedDetails struct {
+	description bool
+	icon        bool
+	categoryId  bool
+}
+
+func (h *Habit) UnmarshalJSON(data []byte) error {
+	type habit Habit
+	if err := json.Unmarshal(data, (*habit)(h)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	_, description := fields["description"]
+	_, icon := fields["icon"]
+	_, categoryId := fields["categoryId"]
+	h.omitted = omittedDetails{description: !description, icon: !icon, categoryId: !categoryId}
+
+	return nil
 }
 
-func NewHabit(id int64, name string, colour string, index int64, entries []models.HabitEntry, active bool) Habit {
+func NewHabit(id int64, name string, colour string, index int64, entries []models.HabitEntry, active bool, schedule models.Schedule, target models.Target) Habit {
 	return Habit{
-		Id:      id,
-		Name:    name,
-		Colour:  colour,
-		Index:   index,
-		Entries: entries,
-		Active:  active,
+		Id:       id,
+		Name:     name,
+		Colour:   colour,
+		Index:    index,
+		Entries:  entries,
+		Active:   active,
+		Schedule: schedule,
+		Target:   target,
+	}
+}
+
+func NewHabitFromStorage(habit repository.Habit, entries []models.HabitEntry) Habit {
+	schedule := models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes)
+	target := models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison)
+	newHabit := NewHabit(habit.ID, habit.Name, habit.Colour, habit.Index, entries, habit.Active, schedule, target)
+	newHabit.Description = habit.Description.String
+	newHabit.Icon = habit.Icon
+	newHabit.Version = habit.Version
+	if habit.CategoryID.Valid {
+		categoryId := habit.CategoryID.Int64
+		newHabit.CategoryId = &categoryId
 	}
+	if habit.ArchivedAt.Valid {
+		archivedAt, err := time.Parse(time.DateTime, habit.ArchivedAt.String)
+		if err == nil {
+			newHabit.ArchivedAt = &archivedAt
+		}
+	}
+
+	return newHabit
+}
+
+// HabitGroup is the habits in a category. Category is nil for the habits
+// without one.
+type HabitGroup struct {
+	Category *models.Category `json:"category"`
+	Habits   []Habit          `json:"habits"`
 }
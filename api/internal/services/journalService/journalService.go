@@ -0,0 +1,231 @@
+package journalService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const (
+	MaxTextLength = 10000
+	maxDate       = "9999-12-31"
+)
+
+var (
+	ErrTextRequired         = errors.New("text is required")
+	ErrTextTooLong          = errors.New("text must be at most 10000 characters")
+	ErrQueryRequired        = errors.New("search query is required")
+	ErrJournalEntryNotFound = errors.New("journal entry not found")
+)
+
+type JournalStorage interface {
+	GetJournalEntry(ctx context.Context, arg repository.GetJournalEntryParams) (repository.JournalEntry, error)
+	GetJournalEntriesBetween(ctx context.Context, arg repository.GetJournalEntriesBetweenParams) ([]repository.JournalEntry, error)
+	SaveJournalEntry(ctx context.Context, arg repository.SaveJournalEntryParams) (repository.JournalEntry, error)
+	DeleteJournalEntry(ctx context.Context, arg repository.DeleteJournalEntryParams) (repository.JournalEntry, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Transactor runs fn with queries inside a transaction, rolling back if fn
+// returns an error.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
+}
+
+// Searcher searches notes and journal entries, using full text search where
+// the database supports it.
+type Searcher interface {
+	SearchHabitEntryNotes(ctx context.Context, userId int64, query string, limit int64) ([]repository.SearchHabitEntryNotesRow, error)
+	SearchJournalEntries(ctx context.Context, userId int64, query string, limit int64) ([]repository.JournalEntry, error)
+}
+
+type JournalService struct {
+	storage    JournalStorage
+	transactor Transactor
+	searcher   Searcher
+	logger     logger.Logger
+}
+
+func NewJournalService(storage JournalStorage, transactor Transactor, searcher Searcher, logger logger.Logger) *JournalService {
+	return &JournalService{
+		storage:    storage,
+		transactor: transactor,
+		searcher:   searcher,
+		logger:     logger,
+	}
+}
+
+// GetJournal returns a user's journal entries within the date range.
+func (s *JournalService) GetJournal(userId int64, dateRange models.DateRange) ([]JournalEntry, error) {
+	ctx := context.Background()
+	params := repository.GetJournalEntriesBetweenParams{UserID: userId, ToDate: maxDate}
+	if !dateRange.From.IsZero() {
+		params.FromDate = dateRange.From.Format(time.DateOnly)
+	}
+	if !dateRange.To.IsZero() {
+		params.ToDate = dateRange.To.Format(time.DateOnly)
+	}
+
+	rawEntries, err := s.storage.GetJournalEntriesBetween(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return newJournalEntriesFromStorage(rawEntries)
+}
+
+// SaveJournalEntry creates or replaces a user's journal entry for the day.
+func (s *JournalService) SaveJournalEntry(ctx context.Context, userId int64, date time.Time, text string) (JournalEntry, error) {
+	var entry JournalEntry
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		entry, err = saveJournalEntry(ctx, queries, userId, date, text)
+		return err
+	})
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func saveJournalEntry(ctx context.Context, storage JournalStorage, userId int64, date time.Time, text string) (JournalEntry, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return JournalEntry{}, ErrTextRequired
+	}
+	if utf8.RuneCountInString(text) > MaxTextLength {
+		return JournalEntry{}, ErrTextTooLong
+	}
+
+	day := date.Format(time.DateOnly)
+	change := auditService.Change{EntityType: auditService.EntityJournalEntry, Action: auditService.ActionCreate, UserId: userId}
+	existing, err := storage.GetJournalEntry(ctx, repository.GetJournalEntryParams{UserID: userId, Date: day})
+	switch {
+	case err == nil:
+		change.Action = auditService.ActionUpdate
+		change.Before, err = newJournalEntryFromStorage(existing)
+		if err != nil {
+			return JournalEntry{}, err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return JournalEntry{}, err
+	}
+
+	saved, err := storage.SaveJournalEntry(ctx, repository.SaveJournalEntryParams{
+		UserID:    userId,
+		Date:      day,
+		Text:      text,
+		UpdatedAt: time.Now().UTC().Format(time.DateTime),
+	})
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	entry, err := newJournalEntryFromStorage(saved)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	change.EntityId = entry.Id
+	change.After = entry
+	if err := auditService.Record(ctx, storage, change); err != nil {
+		return JournalEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// DeleteJournalEntry deletes a user's journal entry for the day.
+func (s *JournalService) DeleteJournalEntry(ctx context.Context, userId int64, date time.Time) error {
+	return s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		return deleteJournalEntry(ctx, queries, userId, date)
+	})
+}
+
+func deleteJournalEntry(ctx context.Context, storage JournalStorage, userId int64, date time.Time) error {
+	deleted, err := storage.DeleteJournalEntry(ctx, repository.DeleteJournalEntryParams{UserID: userId, Date: date.Format(time.DateOnly)})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrJournalEntryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	entry, err := newJournalEntryFromStorage(deleted)
+	if err != nil {
+		return err
+	}
+
+	return auditService.Record(ctx, storage, auditService.Change{
+		Before:     entry,
+		EntityType: auditService.EntityJournalEntry,
+		Action:     auditService.ActionDelete,
+		UserId:     userId,
+		EntityId:   entry.Id,
+	})
+}
+
+// Search returns up to limit of a user's most recent habit entry notes and up
+// to limit of their journal entries matching query.
+func (s *JournalService) Search(userId int64, query string, limit int64) (SearchResults, error) {
+	ctx := context.Background()
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return SearchResults{}, ErrQueryRequired
+	}
+
+	rawNotes, err := s.searcher.SearchHabitEntryNotes(ctx, userId, query, limit)
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	rawEntries, err := s.searcher.SearchJournalEntries(ctx, userId, query, limit)
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	results := SearchResults{Notes: make([]Note, len(rawNotes))}
+	for i, note := range rawNotes {
+		date, err := time.Parse(time.DateOnly, note.Date)
+		if err != nil {
+			return SearchResults{}, err
+		}
+
+		results.Notes[i] = Note{
+			Date:      date,
+			HabitName: note.HabitName,
+			Note:      note.Note,
+			Id:        note.ID,
+			HabitId:   note.HabitID,
+		}
+	}
+
+	results.Journal, err = newJournalEntriesFromStorage(rawEntries)
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	return results, nil
+}
+
+func newJournalEntriesFromStorage(rawEntries []repository.JournalEntry) ([]JournalEntry, error) {
+	entries := make([]JournalEntry, len(rawEntries))
+	for i, rawEntry := range rawEntries {
+		entry, err := newJournalEntryFromStorage(rawEntry)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
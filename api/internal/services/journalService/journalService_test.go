@@ -0,0 +1,259 @@
+package journalService
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockJournalStorage struct {
+	entries     map[string]repository.JournalEntry
+	params      repository.GetJournalEntriesBetweenParams
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func (m *mockJournalStorage) GetJournalEntry(_ context.Context, arg repository.GetJournalEntryParams) (repository.JournalEntry, error) {
+	entry, ok := m.entries[arg.Date]
+	if !ok {
+		return repository.JournalEntry{}, sql.ErrNoRows
+	}
+
+	return entry, nil
+}
+
+func (m *mockJournalStorage) GetJournalEntriesBetween(_ context.Context, arg repository.GetJournalEntriesBetweenParams) ([]repository.JournalEntry, error) {
+	m.params = arg
+	var entries []repository.JournalEntry
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (m *mockJournalStorage) SaveJournalEntry(_ context.Context, arg repository.SaveJournalEntryParams) (repository.JournalEntry, error) {
+	entry := repository.JournalEntry{ID: int64(len(m.entries) + 1), UserID: arg.UserID, Date: arg.Date, Text: arg.Text, CreatedAt: arg.UpdatedAt, UpdatedAt: arg.UpdatedAt}
+	if existing, ok := m.entries[arg.Date]; ok {
+		entry.ID = existing.ID
+		entry.CreatedAt = existing.CreatedAt
+	}
+	m.entries[arg.Date] = entry
+
+	return entry, nil
+}
+
+func (m *mockJournalStorage) DeleteJournalEntry(_ context.Context, arg repository.DeleteJournalEntryParams) (repository.JournalEntry, error) {
+	entry, ok := m.entries[arg.Date]
+	if !ok {
+		return repository.JournalEntry{}, sql.ErrNoRows
+	}
+	delete(m.entries, arg.Date)
+
+	return entry, nil
+}
+
+func (m *mockJournalStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+type mockSearcher struct {
+	query string
+}
+
+func (m *mockSearcher) SearchHabitEntryNotes(_ context.Context, _ int64, query string, _ int64) ([]repository.SearchHabitEntryNotesRow, error) {
+	m.query = query
+	return []repository.SearchHabitEntryNotesRow{{ID: 3, HabitID: 1, HabitName: "Run", Date: "2024-12-20", Note: "Heavy rain"}}, nil
+}
+
+func (m *mockSearcher) SearchJournalEntries(_ context.Context, _ int64, _ string, _ int64) ([]repository.JournalEntry, error) {
+	return []repository.JournalEntry{{ID: 1, Date: "2024-12-20", Text: "A rainy day", UpdatedAt: "2024-12-20 20:00:00"}}, nil
+}
+
+func newMockJournalStorage() *mockJournalStorage {
+	return &mockJournalStorage{entries: map[string]repository.JournalEntry{
+		"2024-12-20": {ID: 1, UserID: 7, Date: "2024-12-20", Text: "A rainy day", CreatedAt: "2024-12-20 20:00:00", UpdatedAt: "2024-12-20 20:00:00"},
+	}}
+}
+
+func TestGetJournal(t *testing.T) {
+	tests := []struct {
+		name           string
+		dateRange      models.DateRange
+		expectedParams repository.GetJournalEntriesBetweenParams
+	}{
+		{
+			name:           "gets every entry without a range",
+			expectedParams: repository.GetJournalEntriesBetweenParams{UserID: 7, ToDate: maxDate},
+		},
+		{
+			name:           "passes the range to storage",
+			dateRange:      models.DateRange{From: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+			expectedParams: repository.GetJournalEntriesBetweenParams{UserID: 7, FromDate: "2024-12-01", ToDate: "2024-12-31"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockJournalStorage()
+			service := NewJournalService(storage, nil, &mockSearcher{}, &logger.MockLogger{})
+
+			// Act
+			entries, err := service.GetJournal(7, tc.dateRange)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedParams, storage.params)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, "A rainy day", entries[0].Text)
+			assert.Equal(t, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), entries[0].Date)
+		})
+	}
+}
+
+func TestSaveJournalEntry(t *testing.T) {
+	tests := []struct {
+		expectedErr    error
+		name           string
+		text           string
+		expectedText   string
+		expectedAction string
+		date           time.Time
+	}{
+		{
+			name:           "creates an entry",
+			date:           time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+			text:           "  Sunny  ",
+			expectedText:   "Sunny",
+			expectedAction: auditService.ActionCreate,
+		},
+		{
+			name:           "replaces an existing entry",
+			date:           time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+			text:           "A very rainy day",
+			expectedText:   "A very rainy day",
+			expectedAction: auditService.ActionUpdate,
+		},
+		{
+			name:        "rejects empty text",
+			date:        time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+			text:        "   ",
+			expectedErr: ErrTextRequired,
+		},
+		{
+			name:        "rejects text that is too long",
+			date:        time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+			text:        strings.Repeat("a", MaxTextLength+1),
+			expectedErr: ErrTextTooLong,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockJournalStorage()
+
+			// Act
+			entry, err := saveJournalEntry(context.Background(), storage, 7, tc.date, tc.text)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				assert.Empty(t, storage.auditEvents)
+				return
+			}
+			assert.Equal(t, tc.expectedText, entry.Text)
+			assert.Equal(t, tc.date, entry.Date)
+			assert.Len(t, storage.auditEvents, 1)
+			assert.Equal(t, auditService.EntityJournalEntry, storage.auditEvents[0].EntityType)
+			assert.Equal(t, tc.expectedAction, storage.auditEvents[0].Action)
+			assert.Equal(t, tc.expectedAction == auditService.ActionUpdate, storage.auditEvents[0].DataBefore.Valid)
+		})
+	}
+}
+
+func TestDeleteJournalEntry(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		date        time.Time
+	}{
+		{
+			name: "deletes an entry",
+			date: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "entry not found",
+			date:        time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+			expectedErr: ErrJournalEntryNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockJournalStorage()
+
+			// Act
+			err := deleteJournalEntry(context.Background(), storage, 7, tc.date)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+			assert.Empty(t, storage.entries)
+			assert.Len(t, storage.auditEvents, 1)
+			assert.Equal(t, auditService.ActionDelete, storage.auditEvents[0].Action)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		query       string
+	}{
+		{
+			name:  "returns matching notes and journal entries",
+			query: " rain ",
+		},
+		{
+			name:        "rejects an empty query",
+			query:       "  ",
+			expectedErr: ErrQueryRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			searcher := &mockSearcher{}
+			service := NewJournalService(newMockJournalStorage(), nil, searcher, &logger.MockLogger{})
+
+			// Act
+			results, err := service.Search(7, tc.query, 10)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				return
+			}
+			assert.Equal(t, "rain", searcher.query)
+			assert.Len(t, results.Notes, 1)
+			assert.Equal(t, "Run", results.Notes[0].HabitName)
+			assert.Equal(t, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), results.Notes[0].Date)
+			assert.Len(t, results.Journal, 1)
+		})
+	}
+}
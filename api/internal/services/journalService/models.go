@@ -0,0 +1,50 @@
+package journalService
+
+import (
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+// JournalEntry is a user's journal for a day, independent of their habits.
+type JournalEntry struct {
+	Date      time.Time `json:"date"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Text      string    `json:"text"`
+	Id        int64     `json:"id"`
+}
+
+func newJournalEntryFromStorage(entry repository.JournalEntry) (JournalEntry, error) {
+	date, err := time.Parse(time.DateOnly, entry.Date)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	updatedAt, err := time.Parse(time.DateTime, entry.UpdatedAt)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	return JournalEntry{
+		Date:      date,
+		UpdatedAt: updatedAt,
+		Text:      entry.Text,
+		Id:        entry.ID,
+	}, nil
+}
+
+// Note is a note on one of a user's habit entries.
+type Note struct {
+	Date      time.Time `json:"date"`
+	HabitName string    `json:"habitName"`
+	Note      string    `json:"note"`
+	Id        int64     `json:"id"`
+	HabitId   int64     `json:"habitId"`
+}
+
+// SearchResults are the notes and journal entries matching a search, most
+// recent first.
+type SearchResults struct {
+	Notes   []Note         `json:"notes"`
+	Journal []JournalEntry `json:"journal"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type Scope string
+
+const (
+	ScopeRead      Scope = "read"
+	ScopeReadWrite Scope = "readWrite"
+)
+
+func (s Scope) IsValid() bool {
+	return s == ScopeRead || s == ScopeReadWrite
+}
+
+// Allows reports whether a request authenticated with this scope may use a
+// route that requires the given scope.
+func (s Scope) Allows(required Scope) bool {
+	return s == ScopeReadWrite || s == required
+}
+
+type ApiToken struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	Name       string     `json:"name"`
+	Scope      Scope      `json:"scope"`
+	Token      string     `json:"token,omitempty"`
+	Id         int64      `json:"id"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "github.com/ReidMason/habit-tracker/internal/storage/repository"
+
+// DefaultColour is the colour of habits and categories created without one.
+const DefaultColour = "#0284c7"
+
+// Category is a user defined group of habits.
+type Category struct {
+	Name   string `json:"name"`
+	Colour string `json:"colour"`
+	Id     int64  `json:"id"`
+}
+
+func NewCategoryFromStorage(category repository.Category) Category {
+	return Category{
+		Id:     category.ID,
+		Name:   category.Name,
+		Colour: category.Colour,
+	}
+}
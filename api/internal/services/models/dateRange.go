@@ -0,0 +1,26 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// DateRange is an inclusive range of days. A zero From or To leaves that
+// side of the range unbounded.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (d DateRange) Validate() error {
+	if !d.From.IsZero() && !d.To.IsZero() && d.From.After(d.To) {
+		return errors.New("from must not be after to")
+	}
+
+	return nil
+}
+
+// Contains reports whether date falls within the range.
+func (d DateRange) Contains(date time.Time) bool {
+	return (d.From.IsZero() || !date.Before(d.From)) && (d.To.IsZero() || !date.After(d.To))
+}
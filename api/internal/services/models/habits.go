@@ -1,30 +1,67 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
 )
 
+type EntryStatus string
+
+const (
+	EntryDone    EntryStatus = "done"
+	EntrySkipped EntryStatus = "skipped"
+	EntryFailed  EntryStatus = "failed"
+)
+
+func (s EntryStatus) Validate() error {
+	switch s {
+	case EntryDone, EntrySkipped, EntryFailed:
+		return nil
+	}
+
+	return fmt.Errorf("unknown entry status %q", s)
+}
+
+// HabitEntry is a day of a habit. Skipped days are Excused, keeping the combo
+// going, while they are within the habit's monthly freeze allowance.
 type HabitEntry struct {
-	Date  time.Time `json:"date"`
-	Id    int64     `json:"id"`
-	Combo int       `json:"combo"`
+	Date      time.Time   `json:"date"`
+	Note      string      `json:"note,omitempty"`
+	Status    EntryStatus `json:"status"`
+	Id        int64       `json:"id"`
+	Value     float64     `json:"value"`
+	Combo     int         `json:"combo"`
+	Completed bool        `json:"completed"`
+	Excused   bool        `json:"excused,omitempty"`
 }
 
-func NewHabitEntryFromStorage(storageHabitEntry sqlite3Storage.HabitEntry) (HabitEntry, error) {
+func NewHabitEntryFromStorage(storageHabitEntry repository.HabitEntry) (HabitEntry, error) {
 	entryDate, err := time.Parse(time.DateOnly, storageHabitEntry.Date)
 	if err != nil {
 		return HabitEntry{}, err
 	}
 
-	return NewHabitEntry(entryDate, storageHabitEntry.ID, 0), nil
+	habitEntry := NewHabitEntry(entryDate, storageHabitEntry.ID, storageHabitEntry.Value, 0)
+	habitEntry.Note = storageHabitEntry.Note
+	habitEntry.Status = EntryStatus(storageHabitEntry.Status)
+	return habitEntry, nil
 }
 
-func NewHabitEntry(date time.Time, id int64, combo int) HabitEntry {
+func NewHabitEntry(date time.Time, id int64, value float64, combo int) HabitEntry {
 	return HabitEntry{
-		Date:  date,
-		Id:    id,
-		Combo: combo,
+		Date:   date,
+		Status: EntryDone,
+		Id:     id,
+		Value:  value,
+		Combo:  combo,
 	}
 }
+
+// HabitEntriesPage is a page of a habit's entries. NextCursor is set when
+// there are more entries to fetch.
+type HabitEntriesPage struct {
+	Entries    []HabitEntry `json:"entries"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type ScheduleType string
+
+const (
+	ScheduleDaily         ScheduleType = "daily"
+	ScheduleEveryNDays    ScheduleType = "everyNDays"
+	ScheduleTimesPerWeek  ScheduleType = "timesPerWeek"
+	ScheduleTimesPerMonth ScheduleType = "timesPerMonth"
+	ScheduleWeekdays      ScheduleType = "weekdays"
+)
+
+// Schedule describes how often a habit is expected to be completed.
+// Count is the N in "every N days" or the X in "X times per week/month".
+// FreezesPerMonth caps how many skipped days a calendar month keep the combo
+// going, with no cap when it is nil.
+type Schedule struct {
+	FreezesPerMonth *int64         `json:"freezesPerMonth,omitempty"`
+	Type            ScheduleType   `json:"type"`
+	Weekdays        []time.Weekday `json:"weekdays"`
+	Count           int64          `json:"count"`
+}
+
+func NewDailySchedule() Schedule {
+	return Schedule{
+		Type:     ScheduleDaily,
+		Weekdays: []time.Weekday{},
+		Count:    1,
+	}
+}
+
+func NewScheduleFromStorage(scheduleType string, count int64, weekdayMask int64, freezes sql.NullInt64) Schedule {
+	weekdays := make([]time.Weekday, 0)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if weekdayMask&(1<<day) != 0 {
+			weekdays = append(weekdays, day)
+		}
+	}
+
+	schedule := Schedule{
+		Type:     ScheduleType(scheduleType),
+		Weekdays: weekdays,
+		Count:    count,
+	}
+	if freezes.Valid {
+		schedule.FreezesPerMonth = &freezes.Int64
+	}
+
+	return schedule
+}
+
+// IsZero reports whether no schedule was provided, e.g. a request body that omits it.
+func (s Schedule) IsZero() bool {
+	return s.Type == "" && s.Count == 0 && len(s.Weekdays) == 0 && s.FreezesPerMonth == nil
+}
+
+func (s Schedule) Validate() error {
+	if s.FreezesPerMonth != nil && (*s.FreezesPerMonth < 0 || *s.FreezesPerMonth > 31) {
+		return errors.New("freezesPerMonth must be between 0 and 31")
+	}
+
+	switch s.Type {
+	case ScheduleDaily:
+		return nil
+	case ScheduleEveryNDays:
+		if s.Count < 1 {
+			return errors.New("count must be at least 1")
+		}
+	case ScheduleTimesPerWeek:
+		if s.Count < 1 || s.Count > 7 {
+			return errors.New("count must be between 1 and 7")
+		}
+	case ScheduleTimesPerMonth:
+		if s.Count < 1 || s.Count > 31 {
+			return errors.New("count must be between 1 and 31")
+		}
+	case ScheduleWeekdays:
+		if len(s.Weekdays) == 0 {
+			return errors.New("at least one weekday is required")
+		}
+		for _, day := range s.Weekdays {
+			if day < time.Sunday || day > time.Saturday {
+				return fmt.Errorf("invalid weekday %d", day)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown schedule type %q", s.Type)
+	}
+
+	return nil
+}
+
+// Freezes returns the freeze allowance as stored, null when there is no cap.
+func (s Schedule) Freezes() sql.NullInt64 {
+	if s.FreezesPerMonth == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *s.FreezesPerMonth, Valid: true}
+}
+
+func (s Schedule) WeekdayMask() int64 {
+	var mask int64
+	for _, day := range s.Weekdays {
+		mask |= 1 << day
+	}
+
+	return mask
+}
+
+// IsPeriodic reports whether the schedule is measured as a number of
+// completions within a calendar week or month rather than a gap between entries.
+func (s Schedule) IsPeriodic() bool {
+	return s.Type == ScheduleTimesPerWeek || s.Type == ScheduleTimesPerMonth
+}
+
+// NextDue returns the latest date the habit can next be completed on without
+// breaking the streak when it was last completed on date.
+func (s Schedule) NextDue(date time.Time) time.Time {
+	switch s.Type {
+	case ScheduleEveryNDays:
+		return date.AddDate(0, 0, int(max(s.Count, 1)))
+	case ScheduleWeekdays:
+		mask := s.WeekdayMask()
+		if mask == 0 {
+			break
+		}
+		next := date.AddDate(0, 0, 1)
+		for mask&(1<<next.Weekday()) == 0 {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+
+	return date.AddDate(0, 0, 1)
+}
+
+// PeriodStart returns the first day of the week (starting Monday) or month
+// containing date for periodic schedules.
+func (s Schedule) PeriodStart(date time.Time) time.Time {
+	if s.Type == ScheduleTimesPerMonth {
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	}
+
+	daysSinceMonday := (int(date.Weekday()) + 6) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-daysSinceMonday, 0, 0, 0, 0, date.Location())
+}
+
+func (s Schedule) NextPeriod(periodStart time.Time) time.Time {
+	if s.Type == ScheduleTimesPerMonth {
+		return periodStart.AddDate(0, 1, 0)
+	}
+
+	return periodStart.AddDate(0, 0, 7)
+}
+
+// BreaksCombo reports whether completing the habit on previous and next, with
+// nothing in between, ends the combo regardless of any earlier entries.
+func (s Schedule) BreaksCombo(previous time.Time, next time.Time) bool {
+	if s.IsPeriodic() {
+		return s.PeriodStart(next).After(s.NextPeriod(s.PeriodStart(previous)))
+	}
+
+	return next.After(s.NextDue(previous))
+}
+
+// ExpectedCompletions returns how many times the habit is expected to be
+// completed between from and to inclusive.
+func (s Schedule) ExpectedCompletions(from time.Time, to time.Time) float64 {
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days <= 0 {
+		return 0
+	}
+
+	switch s.Type {
+	case ScheduleEveryNDays:
+		return math.Ceil(float64(days) / float64(max(s.Count, 1)))
+	case ScheduleWeekdays:
+		mask := s.WeekdayMask()
+		expected := 0
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if mask&(1<<date.Weekday()) != 0 {
+				expected++
+			}
+		}
+		return float64(expected)
+	case ScheduleTimesPerWeek:
+		return float64(s.Count) * float64(days) / 7
+	case ScheduleTimesPerMonth:
+		return float64(s.Count) * float64(days) * 12 / 365
+	}
+
+	return float64(days)
+}
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/ReidMason/habit-tracker/internal/storage/repository"
+
+// Tag is a user defined label, unlike categories a habit can have any number
+// of them.
+type Tag struct {
+	Name string `json:"name"`
+	Id   int64  `json:"id"`
+}
+
+func NewTag(id int64, name string) Tag {
+	return Tag{
+		Id:   id,
+		Name: name,
+	}
+}
+
+func NewTagFromStorage(tag repository.Tag) Tag {
+	return NewTag(tag.ID, tag.Name)
+}
+
+// NewHabitTagsFromStorage returns the tags on each of a user's habits, keyed by
+// habit ID.
+func NewHabitTagsFromStorage(rows []repository.GetUserHabitTagsRow) map[int64][]Tag {
+	habitTags := make(map[int64][]Tag)
+	for _, row := range rows {
+		habitTags[row.HabitID] = append(habitTags[row.HabitID], NewTag(row.ID, row.Name))
+	}
+
+	return habitTags
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type TargetComparison string
+
+const (
+	TargetAtLeast TargetComparison = "atLeast"
+	TargetAtMost  TargetComparison = "atMost"
+)
+
+// Target is the value an entry needs to reach for its day to count as complete.
+// Habits without a measurable goal use the default target of at least 1.
+type Target struct {
+	Unit       string           `json:"unit"`
+	Comparison TargetComparison `json:"comparison"`
+	Value      float64          `json:"value"`
+}
+
+func NewDefaultTarget() Target {
+	return Target{
+		Unit:       "",
+		Comparison: TargetAtLeast,
+		Value:      1,
+	}
+}
+
+func NewTarget(value float64, unit string, comparison string) Target {
+	return Target{
+		Unit:       unit,
+		Comparison: TargetComparison(comparison),
+		Value:      value,
+	}
+}
+
+// IsZero reports whether no target was provided, e.g. a request body that omits it.
+func (t Target) IsZero() bool {
+	return t.Value == 0 && t.Unit == "" && t.Comparison == ""
+}
+
+func (t Target) Validate() error {
+	switch t.Comparison {
+	case TargetAtLeast:
+		if t.Value <= 0 {
+			return errors.New("value must be greater than 0")
+		}
+	case TargetAtMost:
+		if t.Value < 0 {
+			return errors.New("value must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown comparison %q", t.Comparison)
+	}
+
+	return nil
+}
+
+func (t Target) IsMet(value float64) bool {
+	if t.Comparison == TargetAtMost {
+		return value <= t.Value
+	}
+
+	return value >= t.Value
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+type User struct {
+	Name     string       `json:"name"`
+	Settings UserSettings `json:"settings"`
+	Id       int64        `json:"id"`
+	Admin    bool         `json:"admin"`
+}
+
+func NewUser(id int64, name string) User {
+	return User{
+		Id:       id,
+		Name:     name,
+		Settings: NewDefaultUserSettings(),
+	}
+}
+
+// NewAdminUser returns a user that can use the administration endpoints when admin is set.
+func NewAdminUser(id int64, name string, admin bool) User {
+	user := NewUser(id, name)
+	user.Admin = admin
+
+	return user
+}
+
+func NewUserFromStorage(user repository.User) User {
+	newUser := NewAdminUser(user.ID, user.Name, user.Admin)
+	newUser.Settings = NewUserSettings(user.Timezone, user.DayStartHour)
+
+	return newUser
+}
+
+// UserSettings control when a user's days start. Days roll over at
+// DayStartHour in the user's Timezone, so a night owl with a DayStartHour of 4
+// still counts 2am as the day before.
+type UserSettings struct {
+	Timezone     string `json:"timezone"`
+	DayStartHour int64  `json:"dayStartHour"`
+}
+
+func NewUserSettings(timezone string, dayStartHour int64) UserSettings {
+	return UserSettings{
+		Timezone:     timezone,
+		DayStartHour: dayStartHour,
+	}
+}
+
+func NewDefaultUserSettings() UserSettings {
+	return NewUserSettings("UTC", 0)
+}
+
+func (s UserSettings) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if s.DayStartHour < 0 || s.DayStartHour > 23 {
+		return fmt.Errorf("day start hour must be between 0 and 23")
+	}
+
+	return nil
+}
+
+// Location returns the user's timezone, falling back to UTC if it is not known.
+func (s UserSettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// Date returns the user's date at the instant t, as midnight UTC like entry dates.
+func (s UserSettings) Date(t time.Time) time.Time {
+	local := t.In(s.Location())
+	if int64(local.Hour()) < s.DayStartHour {
+		local = local.AddDate(0, 0, -1)
+	}
+
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the user's current date.
+func (s UserSettings) Today() time.Time {
+	return s.Date(time.Now())
+}
@@ -0,0 +1,55 @@
+package statsService
+
+import (
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+)
+
+// Streak is a run of consecutive completions. Start and End are nil for an
+// empty streak.
+type Streak struct {
+	Start  *time.Time `json:"start"`
+	End    *time.Time `json:"end"`
+	Length int        `json:"length"`
+}
+
+type MonthlyCompletions struct {
+	Month       string `json:"month"`
+	Completions int    `json:"completions"`
+}
+
+// HabitStats are the statistics for a single habit. CompletionRates are keyed
+// by the number of days up to and including today they cover.
+type HabitStats struct {
+	CompletionRates    map[int]float64      `json:"completionRates"`
+	BestWeekday        *time.Weekday        `json:"bestWeekday"`
+	Name               string               `json:"name"`
+	Monthly            []MonthlyCompletions `json:"monthly"`
+	CurrentStreak      Streak               `json:"currentStreak"`
+	LongestStreak      Streak               `json:"longestStreak"`
+	WeekdayCompletions [7]int               `json:"weekdayCompletions"`
+	HabitId            int64                `json:"habitId"`
+	TotalCompletions   int                  `json:"totalCompletions"`
+}
+
+// UserStats summarises the statistics of a user's active habits.
+type UserStats struct {
+	BestWeekday        *time.Weekday `json:"bestWeekday"`
+	Habits             []HabitStats  `json:"habits"`
+	WeekdayCompletions [7]int        `json:"weekdayCompletions"`
+	TotalCompletions   int           `json:"totalCompletions"`
+	ActiveStreaks      int           `json:"activeStreaks"`
+}
+
+// TagStats combine the statistics of a user's active habits with a tag.
+// CompletionRates are the average of the habits' completion rates.
+type TagStats struct {
+	CompletionRates    map[int]float64 `json:"completionRates"`
+	BestWeekday        *time.Weekday   `json:"bestWeekday"`
+	Tag                models.Tag      `json:"tag"`
+	HabitIds           []int64         `json:"habitIds"`
+	WeekdayCompletions [7]int          `json:"weekdayCompletions"`
+	TotalCompletions   int             `json:"totalCompletions"`
+	ActiveStreaks      int             `json:"activeStreaks"`
+}
@@ -0,0 +1,357 @@
+package statsService
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+// completionRateDays are the windows completion rates are calculated over.
+var completionRateDays = []int{7, 30, 90, 365}
+
+type StatsStorage interface {
+	GetHabit(ctx context.Context, id int64) (repository.Habit, error)
+	GetHabits(ctx context.Context, userID int64) ([]repository.Habit, error)
+	GetTags(ctx context.Context, userID int64) ([]repository.Tag, error)
+	GetUserHabitTags(ctx context.Context, userID int64) ([]repository.GetUserHabitTagsRow, error)
+}
+
+type HabitEntryStore interface {
+	GetHabitHistory(habitId int64, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error)
+	GetUserHabitEntries(userId int64, dateRange models.DateRange) (map[int64][]models.HabitEntry, error)
+	CalculateCombosInRange(habitId int64, habitEntries []models.HabitEntry, dateRange models.DateRange, schedule models.Schedule, target models.Target) ([]models.HabitEntry, error)
+}
+
+type StatsService struct {
+	storage         StatsStorage
+	logger          logger.Logger
+	habitEntryStore HabitEntryStore
+	now             func() time.Time
+}
+
+func NewStatsService(storage StatsStorage, logger logger.Logger, habitEntryStore HabitEntryStore) *StatsService {
+	return &StatsService{
+		storage:         storage,
+		logger:          logger,
+		habitEntryStore: habitEntryStore,
+		now:             time.Now,
+	}
+}
+
+// GetHabitStats returns the statistics of a habit, with streaks and completion
+// rates measured up to the current day in the user's settings.
+func (s StatsService) GetHabitStats(habitId int64, settings models.UserSettings) (HabitStats, error) {
+	ctx := context.Background()
+	habit, err := s.storage.GetHabit(ctx, habitId)
+	if err != nil {
+		return HabitStats{}, err
+	}
+
+	schedule := models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes)
+	target := models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison)
+	entries, err := s.habitEntryStore.GetHabitHistory(habitId, schedule, target)
+	if err != nil {
+		return HabitStats{}, err
+	}
+
+	return s.calculateHabitStats(habit, entries, schedule, settings), nil
+}
+
+// GetUserStats returns the statistics of all of a user's active habits, loading
+// their entries in a single query.
+func (s StatsService) GetUserStats(userId int64, settings models.UserSettings) (UserStats, error) {
+	habitStats, err := s.activeHabitStats(userId, settings)
+	if err != nil {
+		return UserStats{}, err
+	}
+
+	userStats := UserStats{Habits: habitStats}
+	for _, stats := range habitStats {
+		userStats.TotalCompletions += stats.TotalCompletions
+		for day, completions := range stats.WeekdayCompletions {
+			userStats.WeekdayCompletions[day] += completions
+		}
+		if stats.CurrentStreak.Length > 0 {
+			userStats.ActiveStreaks++
+		}
+	}
+	userStats.BestWeekday = bestWeekday(userStats.WeekdayCompletions)
+
+	return userStats, nil
+}
+
+// GetTagStats returns the combined statistics of the active habits with each
+// of a user's tags, in tag name order.
+func (s StatsService) GetTagStats(userId int64, settings models.UserSettings) ([]TagStats, error) {
+	ctx := context.Background()
+	tags, err := s.storage.GetTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rawHabitTags, err := s.storage.GetUserHabitTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	habitTags := models.NewHabitTagsFromStorage(rawHabitTags)
+
+	habitStats, err := s.activeHabitStats(userId, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tagStats := make([]TagStats, len(tags))
+	for i, tag := range tags {
+		tagStats[i] = TagStats{
+			CompletionRates: make(map[int]float64, len(completionRateDays)),
+			Tag:             models.NewTagFromStorage(tag),
+			HabitIds:        make([]int64, 0),
+		}
+	}
+
+	for _, stats := range habitStats {
+		for _, habitTag := range habitTags[stats.HabitId] {
+			i := slices.IndexFunc(tagStats, func(tagStats TagStats) bool { return tagStats.Tag.Id == habitTag.Id })
+			if i == -1 {
+				continue
+			}
+
+			tagStats[i].HabitIds = append(tagStats[i].HabitIds, stats.HabitId)
+			tagStats[i].TotalCompletions += stats.TotalCompletions
+			for day, completions := range stats.WeekdayCompletions {
+				tagStats[i].WeekdayCompletions[day] += completions
+			}
+			if stats.CurrentStreak.Length > 0 {
+				tagStats[i].ActiveStreaks++
+			}
+			for days, rate := range stats.CompletionRates {
+				tagStats[i].CompletionRates[days] += rate
+			}
+		}
+	}
+
+	for i := range tagStats {
+		for _, days := range completionRateDays {
+			if habits := len(tagStats[i].HabitIds); habits > 0 {
+				tagStats[i].CompletionRates[days] /= float64(habits)
+			} else {
+				tagStats[i].CompletionRates[days] = 0
+			}
+		}
+		tagStats[i].BestWeekday = bestWeekday(tagStats[i].WeekdayCompletions)
+	}
+
+	return tagStats, nil
+}
+
+// activeHabitStats returns the statistics of each of a user's active habits.
+func (s StatsService) activeHabitStats(userId int64, settings models.UserSettings) ([]HabitStats, error) {
+	ctx := context.Background()
+	habits, err := s.storage.GetHabits(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	habitEntries, err := s.habitEntryStore.GetUserHabitEntries(userId, models.DateRange{})
+	if err != nil {
+		return nil, err
+	}
+
+	habitStats := make([]HabitStats, 0, len(habits))
+	for _, habit := range habits {
+		if !habit.Active {
+			continue
+		}
+
+		schedule := models.NewScheduleFromStorage(habit.ScheduleType, habit.ScheduleCount, habit.ScheduleWeekdays, habit.ScheduleFreezes)
+		target := models.NewTarget(habit.TargetValue, habit.TargetUnit, habit.TargetComparison)
+		entries, err := s.habitEntryStore.CalculateCombosInRange(habit.ID, habitEntries[habit.ID], models.DateRange{}, schedule, target)
+		if err != nil {
+			s.logger.Error("Failed to calculate combos", slog.Int64("habitId", habit.ID), slog.Any("error", err))
+			return nil, err
+		}
+
+		habitStats = append(habitStats, s.calculateHabitStats(habit, entries, schedule, settings))
+	}
+
+	return habitStats, nil
+}
+
+// calculateHabitStats calculates a habit's statistics from its date ordered
+// entries with their combos already calculated.
+func (s StatsService) calculateHabitStats(habit repository.Habit, entries []models.HabitEntry, schedule models.Schedule, settings models.UserSettings) HabitStats {
+	today := settings.Date(s.now())
+	completions := make([]models.HabitEntry, 0, len(entries))
+	// streakEntries are the entries that continue or end a streak, excused
+	// days only continue one when they carry a combo
+	streakEntries := make([]models.HabitEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Completed {
+			completions = append(completions, entry)
+		}
+		if entry.Completed || (entry.Excused && entry.Combo > 0) || entry.Status == models.EntryFailed {
+			streakEntries = append(streakEntries, entry)
+		}
+	}
+
+	stats := HabitStats{
+		CompletionRates:  make(map[int]float64, len(completionRateDays)),
+		Name:             habit.Name,
+		Monthly:          monthlyCompletions(completions, today),
+		HabitId:          habit.ID,
+		TotalCompletions: len(completions),
+	}
+
+	for _, completion := range completions {
+		stats.WeekdayCompletions[completion.Date.Weekday()]++
+	}
+	stats.BestWeekday = bestWeekday(stats.WeekdayCompletions)
+
+	stats.CurrentStreak, stats.LongestStreak = streaks(completions, streakEntries, schedule, today)
+
+	// Days before the habit was tracked are not counted against it, unless
+	// earlier entries were added.
+	trackedFrom := today
+	if createdAt, err := time.Parse(time.DateTime, habit.CreatedAt); err == nil {
+		trackedFrom = settings.Date(createdAt)
+	}
+	if len(entries) > 0 && entries[0].Date.Before(trackedFrom) {
+		trackedFrom = entries[0].Date
+	}
+
+	for _, days := range completionRateDays {
+		from := today.AddDate(0, 0, 1-days)
+		if from.Before(trackedFrom) {
+			from = trackedFrom
+		}
+
+		completed := 0
+		for _, completion := range completions {
+			if !completion.Date.Before(from) && !completion.Date.After(today) {
+				completed++
+			}
+		}
+
+		// Excused days are not expected to be completed
+		excused := 0
+		for _, entry := range entries {
+			if entry.Excused && !entry.Date.Before(from) && !entry.Date.After(today) {
+				excused++
+			}
+		}
+
+		stats.CompletionRates[days] = completionRate(completed, schedule.ExpectedCompletions(from, today)-float64(excused))
+	}
+
+	return stats
+}
+
+// streaks returns the current and longest runs of completions. The current
+// streak is empty if it can no longer be continued today.
+func streaks(completions []models.HabitEntry, streakEntries []models.HabitEntry, schedule models.Schedule, today time.Time) (Streak, Streak) {
+	var current, longest Streak
+	for _, completion := range completions {
+		date := completion.Date
+		if completion.Combo == 1 {
+			current = Streak{Start: &date}
+		}
+		current.End = &date
+		current.Length = completion.Combo
+
+		if current.Length > longest.Length {
+			longest = current
+		}
+	}
+
+	if current.Length == 0 || !streakContinues(streakEntries, schedule, today) {
+		return Streak{}, longest
+	}
+
+	return current, longest
+}
+
+// streakContinues reports whether completing the habit today would continue
+// the combo of the last completion, given the completions, excused days and
+// failed days since.
+func streakContinues(streakEntries []models.HabitEntry, schedule models.Schedule, today time.Time) bool {
+	lastEntry := streakEntries[len(streakEntries)-1]
+	if lastEntry.Status == models.EntryFailed {
+		return false
+	}
+
+	last := lastEntry.Date
+	if !schedule.IsPeriodic() {
+		return !today.After(schedule.NextDue(last))
+	}
+
+	period := schedule.PeriodStart(last)
+	todayPeriod := schedule.PeriodStart(today)
+	if !todayPeriod.After(period) {
+		return true
+	}
+	if !todayPeriod.Equal(schedule.NextPeriod(period)) {
+		return false
+	}
+
+	var completed int64
+	for _, entry := range streakEntries {
+		if entry.Status != models.EntryFailed && schedule.PeriodStart(entry.Date).Equal(period) {
+			completed++
+		}
+	}
+
+	return completed >= schedule.Count
+}
+
+// monthlyCompletions returns the number of completions in each month from the
+// first completion up to the current month.
+func monthlyCompletions(completions []models.HabitEntry, today time.Time) []MonthlyCompletions {
+	monthly := make([]MonthlyCompletions, 0)
+	if len(completions) == 0 {
+		return monthly
+	}
+
+	first := completions[0].Date
+	i := 0
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(today); month = month.AddDate(0, 1, 0) {
+		nextMonth := month.AddDate(0, 1, 0)
+		completed := 0
+		for ; i < len(completions) && completions[i].Date.Before(nextMonth); i++ {
+			completed++
+		}
+
+		monthly = append(monthly, MonthlyCompletions{
+			Month:       month.Format("2006-01"),
+			Completions: completed,
+		})
+	}
+
+	return monthly
+}
+
+// bestWeekday returns the weekday with the most completions, or nil if there
+// are none. Ties go to the earliest day of the week starting Monday.
+func bestWeekday(weekdayCompletions [7]int) *time.Weekday {
+	var best *time.Weekday
+	for i := range 7 {
+		day := time.Weekday((i + 1) % 7)
+		if weekdayCompletions[day] > 0 && (best == nil || weekdayCompletions[day] > weekdayCompletions[*best]) {
+			best = &day
+		}
+	}
+
+	return best
+}
+
+func completionRate(completed int, expected float64) float64 {
+	if expected <= 0 {
+		return 0
+	}
+
+	return min(float64(completed)/expected, 1)
+}
@@ -0,0 +1,305 @@
+package statsService
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStatsStorage struct {
+	habits    []repository.Habit
+	tags      []repository.Tag
+	habitTags []repository.GetUserHabitTagsRow
+}
+
+func (m mockStatsStorage) GetHabit(_ context.Context, id int64) (repository.Habit, error) {
+	for _, habit := range m.habits {
+		if habit.ID == id {
+			return habit, nil
+		}
+	}
+
+	return repository.Habit{}, nil
+}
+
+func (m mockStatsStorage) GetHabits(_ context.Context, _ int64) ([]repository.Habit, error) {
+	return m.habits, nil
+}
+
+func (m mockStatsStorage) GetTags(_ context.Context, _ int64) ([]repository.Tag, error) {
+	return m.tags, nil
+}
+
+func (m mockStatsStorage) GetUserHabitTags(_ context.Context, _ int64) ([]repository.GetUserHabitTagsRow, error) {
+	return m.habitTags, nil
+}
+
+// mockHabitEntryStorage returns the entries with the combos they were given.
+type mockHabitEntryStorage struct {
+	entries map[int64][]models.HabitEntry
+}
+
+func (m mockHabitEntryStorage) GetHabitHistory(habitId int64, _ models.Schedule, _ models.Target) ([]models.HabitEntry, error) {
+	return m.entries[habitId], nil
+}
+
+func (m mockHabitEntryStorage) GetUserHabitEntries(_ int64, _ models.DateRange) (map[int64][]models.HabitEntry, error) {
+	return m.entries, nil
+}
+
+func (m mockHabitEntryStorage) CalculateCombosInRange(_ int64, habitEntries []models.HabitEntry, _ models.DateRange, _ models.Schedule, _ models.Target) ([]models.HabitEntry, error) {
+	return habitEntries, nil
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(time.DateOnly, value)
+	return parsed
+}
+
+// completedEntries returns completed entries on the dates with their combos
+// numbered as consecutive runs, starting a new run after a gap of more than a day.
+func completedEntries(dates ...string) []models.HabitEntry {
+	entries := make([]models.HabitEntry, len(dates))
+	for i, value := range dates {
+		entries[i] = models.NewHabitEntry(date(value), int64(i+1), 1, 1)
+		entries[i].Completed = true
+		if i > 0 && !entries[i].Date.After(entries[i-1].Date.AddDate(0, 0, 1)) {
+			entries[i].Combo = entries[i-1].Combo + 1
+		}
+	}
+
+	return entries
+}
+
+// withEntry appends an entry that is not a completion to entries.
+func withEntry(entries []models.HabitEntry, value string, status models.EntryStatus, combo int) []models.HabitEntry {
+	entry := models.NewHabitEntry(date(value), int64(len(entries)+1), 0, combo)
+	entry.Status = status
+	entry.Excused = status == models.EntrySkipped
+	return append(entries, entry)
+}
+
+func dailyHabit(id int64) repository.Habit {
+	return repository.Habit{ID: id, Name: "Habit", Active: true, CreatedAt: "2024-11-01 09:00:00", ScheduleType: "daily", ScheduleCount: 1, TargetValue: 1, TargetComparison: "atLeast"}
+}
+
+func TestGetHabitStats(t *testing.T) {
+	tests := []struct {
+		name                  string
+		entries               []models.HabitEntry
+		today                 string
+		expectedCurrent       int
+		expectedLongest       int
+		expectedLongestStart  string
+		expectedTotal         int
+		expectedSevenDayRate  float64
+		expectedMonthlyLength int
+	}{
+		{
+			name:                  "current streak continues until today is missed",
+			entries:               completedEntries("2024-11-01", "2024-11-02", "2024-11-03", "2024-11-05", "2024-11-06"),
+			today:                 "2024-11-07",
+			expectedCurrent:       2,
+			expectedLongest:       3,
+			expectedLongestStart:  "2024-11-01",
+			expectedTotal:         5,
+			expectedSevenDayRate:  5.0 / 7,
+			expectedMonthlyLength: 1,
+		},
+		{
+			name:                  "current streak is broken after a missed day",
+			entries:               completedEntries("2024-11-01", "2024-11-02"),
+			today:                 "2024-11-04",
+			expectedCurrent:       0,
+			expectedLongest:       2,
+			expectedLongestStart:  "2024-11-01",
+			expectedTotal:         2,
+			expectedSevenDayRate:  2.0 / 4,
+			expectedMonthlyLength: 1,
+		},
+		{
+			name:                  "histogram includes months without completions",
+			entries:               completedEntries("2024-09-30", "2024-12-01"),
+			today:                 "2024-12-01",
+			expectedCurrent:       1,
+			expectedLongest:       1,
+			expectedLongestStart:  "2024-09-30",
+			expectedTotal:         2,
+			expectedSevenDayRate:  1.0 / 7,
+			expectedMonthlyLength: 4,
+		},
+		{
+			name:                  "current streak continues through an excused day",
+			entries:               withEntry(completedEntries("2024-11-01", "2024-11-02"), "2024-11-03", models.EntrySkipped, 2),
+			today:                 "2024-11-04",
+			expectedCurrent:       2,
+			expectedLongest:       2,
+			expectedLongestStart:  "2024-11-01",
+			expectedTotal:         2,
+			expectedSevenDayRate:  2.0 / 3,
+			expectedMonthlyLength: 1,
+		},
+		{
+			name:                  "current streak is broken by a failed day",
+			entries:               withEntry(completedEntries("2024-11-01", "2024-11-02"), "2024-11-03", models.EntryFailed, 0),
+			today:                 "2024-11-03",
+			expectedCurrent:       0,
+			expectedLongest:       2,
+			expectedLongestStart:  "2024-11-01",
+			expectedTotal:         2,
+			expectedSevenDayRate:  2.0 / 3,
+			expectedMonthlyLength: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := mockStatsStorage{habits: []repository.Habit{dailyHabit(1)}}
+			entryStorage := mockHabitEntryStorage{entries: map[int64][]models.HabitEntry{1: tc.entries}}
+			service := NewStatsService(storage, &logger.MockLogger{}, entryStorage)
+			service.now = func() time.Time { return date(tc.today).Add(20 * time.Hour) }
+
+			// Act
+			stats, err := service.GetHabitStats(1, models.NewDefaultUserSettings())
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+
+			assert.Equal(t, tc.expectedCurrent, stats.CurrentStreak.Length)
+			assert.Equal(t, tc.expectedLongest, stats.LongestStreak.Length)
+			assert.Equal(t, date(tc.expectedLongestStart), *stats.LongestStreak.Start)
+			assert.Equal(t, tc.expectedTotal, stats.TotalCompletions)
+			assert.InDelta(t, tc.expectedSevenDayRate, stats.CompletionRates[7], 0.0001)
+			assert.Len(t, stats.Monthly, tc.expectedMonthlyLength)
+		})
+	}
+}
+
+func TestGetHabitStatsUsesUserDay(t *testing.T) {
+	tests := []struct {
+		name            string
+		settings        models.UserSettings
+		now             time.Time
+		expectedCurrent int
+	}{
+		{
+			name:            "day has rolled over in UTC",
+			settings:        models.NewDefaultUserSettings(),
+			now:             time.Date(2024, 11, 4, 2, 0, 0, 0, time.UTC),
+			expectedCurrent: 0,
+		},
+		{
+			name:            "day has not rolled over before the day start hour",
+			settings:        models.NewUserSettings("UTC", 4),
+			now:             time.Date(2024, 11, 4, 2, 0, 0, 0, time.UTC),
+			expectedCurrent: 2,
+		},
+		{
+			name:            "day has not rolled over in the user's timezone",
+			settings:        models.NewUserSettings("America/New_York", 0),
+			now:             time.Date(2024, 11, 4, 2, 0, 0, 0, time.UTC),
+			expectedCurrent: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := mockStatsStorage{habits: []repository.Habit{dailyHabit(1)}}
+			entryStorage := mockHabitEntryStorage{entries: map[int64][]models.HabitEntry{1: completedEntries("2024-11-01", "2024-11-02")}}
+			service := NewStatsService(storage, &logger.MockLogger{}, entryStorage)
+			service.now = func() time.Time { return tc.now }
+
+			// Act
+			stats, err := service.GetHabitStats(1, tc.settings)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCurrent, stats.CurrentStreak.Length)
+		})
+	}
+}
+
+func TestGetUserStats(t *testing.T) {
+	// Arrange
+	inactiveHabit := dailyHabit(3)
+	inactiveHabit.Active = false
+	storage := mockStatsStorage{habits: []repository.Habit{dailyHabit(1), dailyHabit(2), inactiveHabit}}
+	entryStorage := mockHabitEntryStorage{entries: map[int64][]models.HabitEntry{
+		1: completedEntries("2024-11-04", "2024-11-05"),
+		2: completedEntries("2024-11-01", "2024-11-04"),
+		3: completedEntries("2024-11-04"),
+	}}
+	service := NewStatsService(storage, &logger.MockLogger{}, entryStorage)
+	service.now = func() time.Time { return date("2024-11-05") }
+
+	// Act
+	stats, err := service.GetUserStats(1, models.NewDefaultUserSettings())
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	monday := time.Monday
+	assert.Len(t, stats.Habits, 2)
+	assert.Equal(t, 4, stats.TotalCompletions)
+	assert.Equal(t, 2, stats.ActiveStreaks)
+	assert.Equal(t, &monday, stats.BestWeekday)
+}
+
+func TestGetTagStats(t *testing.T) {
+	// Arrange
+	inactiveHabit := dailyHabit(3)
+	inactiveHabit.Active = false
+	storage := mockStatsStorage{
+		habits: []repository.Habit{dailyHabit(1), dailyHabit(2), inactiveHabit},
+		tags:   []repository.Tag{{ID: 2, Name: "health"}, {ID: 1, Name: "morning"}, {ID: 3, Name: "unused"}},
+		habitTags: []repository.GetUserHabitTagsRow{
+			{HabitID: 1, ID: 2, Name: "health"},
+			{HabitID: 2, ID: 2, Name: "health"},
+			{HabitID: 1, ID: 1, Name: "morning"},
+			{HabitID: 3, ID: 1, Name: "morning"},
+		},
+	}
+	entryStorage := mockHabitEntryStorage{entries: map[int64][]models.HabitEntry{
+		1: completedEntries("2024-11-04", "2024-11-05"),
+		2: completedEntries("2024-11-01", "2024-11-04"),
+		3: completedEntries("2024-11-04"),
+	}}
+	service := NewStatsService(storage, &logger.MockLogger{}, entryStorage)
+	service.now = func() time.Time { return date("2024-11-05") }
+
+	// Act
+	stats, err := service.GetTagStats(1, models.NewDefaultUserSettings())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+
+	monday := time.Monday
+	assert.Equal(t, models.NewTag(2, "health"), stats[0].Tag)
+	assert.Equal(t, []int64{1, 2}, stats[0].HabitIds)
+	assert.Equal(t, 4, stats[0].TotalCompletions)
+	assert.Equal(t, 2, stats[0].ActiveStreaks)
+	assert.Equal(t, &monday, stats[0].BestWeekday)
+	assert.InDelta(t, 0.4, stats[0].CompletionRates[7], 0.0001)
+
+	assert.Equal(t, models.NewTag(1, "morning"), stats[1].Tag)
+	assert.Equal(t, []int64{1}, stats[1].HabitIds)
+	assert.Equal(t, 2, stats[1].TotalCompletions)
+	assert.Equal(t, 1, stats[1].ActiveStreaks)
+
+	assert.Empty(t, stats[2].HabitIds)
+	assert.Zero(t, stats[2].TotalCompletions)
+	assert.Nil(t, stats[2].BestWeekday)
+	assert.Equal(t, 0.0, stats[2].CompletionRates[7])
+}
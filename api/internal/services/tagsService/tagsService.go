@@ -0,0 +1,321 @@
+package tagsService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const MaxNameLength = 100
+
+var (
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrTagExists     = errors.New("a tag with that name already exists")
+	ErrHabitNotFound = errors.New("habit not found")
+	ErrNameRequired  = errors.New("name is required")
+	ErrNameTooLong   = errors.New("name must be at most 100 characters")
+)
+
+type TagStorage interface {
+	GetTags(ctx context.Context, userID int64) ([]repository.Tag, error)
+	GetTag(ctx context.Context, arg repository.GetTagParams) (repository.Tag, error)
+	CreateTag(ctx context.Context, arg repository.CreateTagParams) (repository.Tag, error)
+	UpdateTag(ctx context.Context, arg repository.UpdateTagParams) (repository.Tag, error)
+	DeleteTag(ctx context.Context, arg repository.DeleteTagParams) (repository.Tag, error)
+	GetHabit(ctx context.Context, id int64) (repository.Habit, error)
+	GetHabitTags(ctx context.Context, habitID int64) ([]repository.Tag, error)
+	AttachHabitTag(ctx context.Context, arg repository.AttachHabitTagParams) (int64, error)
+	DetachHabitTag(ctx context.Context, arg repository.DetachHabitTagParams) (int64, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+// Transactor runs fn with queries inside a transaction, rolling back if fn
+// returns an error.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
+}
+
+// habitTags is how tagging a habit is recorded in the audit log.
+type habitTags struct {
+	Tags []models.Tag `json:"tags"`
+}
+
+// EventPublisher tells a user's other devices about changes to their habits.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
+}
+
+type TagService struct {
+	storage    TagStorage
+	transactor Transactor
+	logger     logger.Logger
+	events     EventPublisher
+}
+
+func NewTagService(storage TagStorage, transactor Transactor, logger logger.Logger, events EventPublisher) *TagService {
+	return &TagService{
+		storage:    storage,
+		transactor: transactor,
+		logger:     logger,
+		events:     events,
+	}
+}
+
+// GetTags returns a user's tags in name order.
+func (s *TagService) GetTags(userId int64) ([]models.Tag, error) {
+	ctx := context.Background()
+	rawTags, err := s.storage.GetTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTagsFromStorage(rawTags), nil
+}
+
+// CreateTag adds a tag for a user. Names are unique per user, ignoring case.
+func (s *TagService) CreateTag(ctx context.Context, userId int64, name string) (models.Tag, error) {
+	var tag models.Tag
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		tag, err = createTag(ctx, queries, userId, name)
+		return err
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func createTag(ctx context.Context, storage TagStorage, userId int64, name string) (models.Tag, error) {
+	name, err := validateName(ctx, storage, userId, 0, name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	created, err := storage.CreateTag(ctx, repository.CreateTagParams{
+		UserID: userId,
+		Name:   name,
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tag := models.NewTagFromStorage(created)
+	err = recordChange(ctx, storage, auditService.EntityTag, userId, tag.Id, auditService.ActionCreate, nil, tag)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// UpdateTag renames a user's tag.
+func (s *TagService) UpdateTag(ctx context.Context, userId int64, tagId int64, name string) (models.Tag, error) {
+	var tag models.Tag
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		tag, err = updateTag(ctx, queries, userId, tagId, name)
+		return err
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func updateTag(ctx context.Context, storage TagStorage, userId int64, tagId int64, name string) (models.Tag, error) {
+	existing, err := storage.GetTag(ctx, repository.GetTagParams{ID: tagId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tag{}, ErrTagNotFound
+	}
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	name, err = validateName(ctx, storage, userId, tagId, name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	updated, err := storage.UpdateTag(ctx, repository.UpdateTagParams{
+		Name:      name,
+		UpdatedAt: time.Now().UTC().Format(time.DateTime),
+		ID:        tagId,
+		UserID:    userId,
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tag := models.NewTagFromStorage(updated)
+	err = recordChange(ctx, storage, auditService.EntityTag, userId, tagId, auditService.ActionUpdate, models.NewTagFromStorage(existing), tag)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// DeleteTag deletes a user's tag, removing it from all of their habits.
+func (s *TagService) DeleteTag(ctx context.Context, userId int64, tagId int64) error {
+	return s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		return deleteTag(ctx, queries, userId, tagId)
+	})
+}
+
+func deleteTag(ctx context.Context, storage TagStorage, userId int64, tagId int64) error {
+	deleted, err := storage.DeleteTag(ctx, repository.DeleteTagParams{ID: tagId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTagNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return recordChange(ctx, storage, auditService.EntityTag, userId, tagId, auditService.ActionDelete, models.NewTagFromStorage(deleted), nil)
+}
+
+// AttachTag tags one of a user's habits, returning the habit's tags. Tagging a
+// habit with a tag it already has changes nothing.
+func (s *TagService) AttachTag(ctx context.Context, userId int64, habitId int64, tagId int64) ([]models.Tag, error) {
+	return s.setHabitTag(ctx, userId, habitId, tagId, true)
+}
+
+// DetachTag removes a tag from one of a user's habits, returning the habit's
+// remaining tags.
+func (s *TagService) DetachTag(ctx context.Context, userId int64, habitId int64, tagId int64) ([]models.Tag, error) {
+	return s.setHabitTag(ctx, userId, habitId, tagId, false)
+}
+
+// setHabitTag attaches or detaches a tag, telling the user's other devices
+// about the habit when its tags change.
+func (s *TagService) setHabitTag(ctx context.Context, userId int64, habitId int64, tagId int64, attach bool) ([]models.Tag, error) {
+	var habit habitsService.Habit
+	var changed bool
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habit, changed, err = setHabitTag(ctx, queries, userId, habitId, tagId, attach)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		s.events.Publish(userId, eventsService.HabitUpdated, habit)
+	}
+	return habit.Tags, nil
+}
+
+// setHabitTag attaches or detaches a tag, recording the habit's tags before and
+// after in the audit log when they change. It returns the habit with its tags
+// and whether they changed.
+func setHabitTag(ctx context.Context, storage TagStorage, userId int64, habitId int64, tagId int64, attach bool) (habitsService.Habit, bool, error) {
+	rawHabit, err := storage.GetHabit(ctx, habitId)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && rawHabit.UserID != userId) {
+		return habitsService.Habit{}, false, ErrHabitNotFound
+	}
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+	habit := habitsService.NewHabitFromStorage(rawHabit, nil)
+
+	_, err = storage.GetTag(ctx, repository.GetTagParams{ID: tagId, UserID: userId})
+	if errors.Is(err, sql.ErrNoRows) {
+		return habitsService.Habit{}, false, ErrTagNotFound
+	}
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+
+	rawTags, err := storage.GetHabitTags(ctx, habitId)
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+	before := newTagsFromStorage(rawTags)
+
+	var changed int64
+	if attach {
+		changed, err = storage.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: habitId, TagID: tagId})
+	} else {
+		changed, err = storage.DetachHabitTag(ctx, repository.DetachHabitTagParams{HabitID: habitId, TagID: tagId})
+	}
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+	if changed == 0 {
+		habit.Tags = before
+		return habit, false, nil
+	}
+
+	rawTags, err = storage.GetHabitTags(ctx, habitId)
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+	habit.Tags = newTagsFromStorage(rawTags)
+
+	err = recordChange(ctx, storage, auditService.EntityHabit, userId, habitId, auditService.ActionUpdate, habitTags{Tags: before}, habitTags{Tags: habit.Tags})
+	if err != nil {
+		return habitsService.Habit{}, false, err
+	}
+
+	return habit, true, nil
+}
+
+// validateName returns the trimmed name, checking no other of the user's tags
+// has it.
+func validateName(ctx context.Context, storage TagStorage, userId int64, tagId int64, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrNameTooLong
+	}
+
+	tags, err := storage.GetTags(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag.ID != tagId && strings.EqualFold(tag.Name, name) {
+			return "", ErrTagExists
+		}
+	}
+
+	return name, nil
+}
+
+func newTagsFromStorage(rawTags []repository.Tag) []models.Tag {
+	tags := make([]models.Tag, len(rawTags))
+	for i, tag := range rawTags {
+		tags[i] = models.NewTagFromStorage(tag)
+	}
+
+	return tags
+}
+
+// recordChange records a change in the audit log. before is nil for creates
+// and after is nil for deletes.
+func recordChange(ctx context.Context, storage TagStorage, entityType string, userId int64, entityId int64, action string, before any, after any) error {
+	return auditService.Record(ctx, storage, auditService.Change{
+		Before:     before,
+		After:      after,
+		EntityType: entityType,
+		Action:     action,
+		UserId:     userId,
+		EntityId:   entityId,
+	})
+}
@@ -0,0 +1,289 @@
+package tagsService
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/models"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTagStorage struct {
+	tags        []repository.Tag
+	habits      []repository.Habit
+	habitTags   []repository.HabitTag
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func (m *mockTagStorage) GetTags(_ context.Context, userId int64) ([]repository.Tag, error) {
+	var tags []repository.Tag
+	for _, tag := range m.tags {
+		if tag.UserID == userId {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+func (m *mockTagStorage) GetTag(_ context.Context, arg repository.GetTagParams) (repository.Tag, error) {
+	for _, tag := range m.tags {
+		if tag.ID == arg.ID && tag.UserID == arg.UserID {
+			return tag, nil
+		}
+	}
+
+	return repository.Tag{}, sql.ErrNoRows
+}
+
+func (m *mockTagStorage) CreateTag(_ context.Context, arg repository.CreateTagParams) (repository.Tag, error) {
+	tag := repository.Tag{ID: int64(len(m.tags) + 1), UserID: arg.UserID, Name: arg.Name}
+	m.tags = append(m.tags, tag)
+	return tag, nil
+}
+
+func (m *mockTagStorage) UpdateTag(_ context.Context, arg repository.UpdateTagParams) (repository.Tag, error) {
+	for i, tag := range m.tags {
+		if tag.ID == arg.ID && tag.UserID == arg.UserID {
+			m.tags[i].Name = arg.Name
+			return m.tags[i], nil
+		}
+	}
+
+	return repository.Tag{}, sql.ErrNoRows
+}
+
+func (m *mockTagStorage) DeleteTag(_ context.Context, arg repository.DeleteTagParams) (repository.Tag, error) {
+	for i, tag := range m.tags {
+		if tag.ID == arg.ID && tag.UserID == arg.UserID {
+			m.tags = append(m.tags[:i], m.tags[i+1:]...)
+			return tag, nil
+		}
+	}
+
+	return repository.Tag{}, sql.ErrNoRows
+}
+
+func (m *mockTagStorage) GetHabit(_ context.Context, id int64) (repository.Habit, error) {
+	for _, habit := range m.habits {
+		if habit.ID == id {
+			return habit, nil
+		}
+	}
+
+	return repository.Habit{}, sql.ErrNoRows
+}
+
+func (m *mockTagStorage) GetHabitTags(_ context.Context, habitId int64) ([]repository.Tag, error) {
+	var tags []repository.Tag
+	for _, habitTag := range m.habitTags {
+		for _, tag := range m.tags {
+			if habitTag.HabitID == habitId && habitTag.TagID == tag.ID {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+func (m *mockTagStorage) AttachHabitTag(_ context.Context, arg repository.AttachHabitTagParams) (int64, error) {
+	for _, habitTag := range m.habitTags {
+		if habitTag.HabitID == arg.HabitID && habitTag.TagID == arg.TagID {
+			return 0, nil
+		}
+	}
+
+	m.habitTags = append(m.habitTags, repository.HabitTag{HabitID: arg.HabitID, TagID: arg.TagID})
+	return 1, nil
+}
+
+func (m *mockTagStorage) DetachHabitTag(_ context.Context, arg repository.DetachHabitTagParams) (int64, error) {
+	for i, habitTag := range m.habitTags {
+		if habitTag.HabitID == arg.HabitID && habitTag.TagID == arg.TagID {
+			m.habitTags = append(m.habitTags[:i], m.habitTags[i+1:]...)
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (m *mockTagStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+func newMockTagStorage() *mockTagStorage {
+	return &mockTagStorage{
+		tags: []repository.Tag{
+			{ID: 1, UserID: 1, Name: "morning"},
+			{ID: 2, UserID: 1, Name: "health"},
+			{ID: 3, UserID: 2, Name: "work"},
+		},
+		habits: []repository.Habit{
+			{ID: 1, UserID: 1, Name: "Run"},
+			{ID: 2, UserID: 2, Name: "Read"},
+		},
+		habitTags: []repository.HabitTag{{HabitID: 1, TagID: 1}},
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		expectedErr error
+		expectedTag models.Tag
+	}{
+		{
+			name:        "creates a tag with a trimmed name",
+			tagName:     " evening ",
+			expectedTag: models.NewTag(4, "evening"),
+		},
+		{
+			name:        "allows a name another user has",
+			tagName:     "work",
+			expectedTag: models.NewTag(4, "work"),
+		},
+		{
+			name:        "rejects a name the user already has",
+			tagName:     "Morning",
+			expectedErr: ErrTagExists,
+		},
+		{
+			name:        "rejects a missing name",
+			tagName:     " ",
+			expectedErr: ErrNameRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockTagStorage()
+
+			// Act
+			tag, err := createTag(context.Background(), storage, 1, tc.tagName)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedTag, tag)
+			if tc.expectedErr == nil {
+				assert.Len(t, storage.auditEvents, 1)
+				assert.Equal(t, auditService.EntityTag, storage.auditEvents[0].EntityType)
+			}
+		})
+	}
+}
+
+func TestUpdateTag(t *testing.T) {
+	// Arrange
+	storage := newMockTagStorage()
+
+	// Act
+	tag, err := updateTag(context.Background(), storage, 1, 2, "fitness")
+	_, existsErr := updateTag(context.Background(), storage, 1, 2, "MORNING")
+	_, notFoundErr := updateTag(context.Background(), storage, 1, 3, "fitness")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.NewTag(2, "fitness"), tag)
+	assert.ErrorIs(t, existsErr, ErrTagExists)
+	assert.ErrorIs(t, notFoundErr, ErrTagNotFound)
+	assert.Len(t, storage.auditEvents, 1)
+}
+
+func TestDeleteTag(t *testing.T) {
+	// Arrange
+	storage := newMockTagStorage()
+
+	// Act
+	err := deleteTag(context.Background(), storage, 1, 1)
+	notFoundErr := deleteTag(context.Background(), storage, 1, 3)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, notFoundErr, ErrTagNotFound)
+	assert.Len(t, storage.tags, 2)
+	assert.Len(t, storage.auditEvents, 1)
+	assert.Equal(t, auditService.ActionDelete, storage.auditEvents[0].Action)
+}
+
+func TestSetHabitTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		habitId        int64
+		tagId          int64
+		attach         bool
+		expectedErr    error
+		expectedTags   []models.Tag
+		expectedEvents int
+	}{
+		{
+			name:           "attaches a tag",
+			habitId:        1,
+			tagId:          2,
+			attach:         true,
+			expectedTags:   []models.Tag{models.NewTag(1, "morning"), models.NewTag(2, "health")},
+			expectedEvents: 1,
+		},
+		{
+			name:         "does nothing when the habit already has the tag",
+			habitId:      1,
+			tagId:        1,
+			attach:       true,
+			expectedTags: []models.Tag{models.NewTag(1, "morning")},
+		},
+		{
+			name:           "detaches a tag",
+			habitId:        1,
+			tagId:          1,
+			expectedTags:   []models.Tag{},
+			expectedEvents: 1,
+		},
+		{
+			name:         "does nothing when the habit does not have the tag",
+			habitId:      1,
+			tagId:        2,
+			expectedTags: []models.Tag{models.NewTag(1, "morning")},
+		},
+		{
+			name:        "rejects another user's habit",
+			habitId:     2,
+			tagId:       1,
+			attach:      true,
+			expectedErr: ErrHabitNotFound,
+		},
+		{
+			name:        "rejects another user's tag",
+			habitId:     1,
+			tagId:       3,
+			attach:      true,
+			expectedErr: ErrTagNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := newMockTagStorage()
+
+			// Act
+			habit, changed, err := setHabitTag(context.Background(), storage, 1, tc.habitId, tc.tagId, tc.attach)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedTags, habit.Tags)
+			assert.Equal(t, tc.expectedEvents > 0, changed)
+			assert.Len(t, storage.auditEvents, tc.expectedEvents)
+			if tc.expectedEvents > 0 {
+				assert.Equal(t, auditService.EntityHabit, storage.auditEvents[0].EntityType)
+				assert.Equal(t, tc.habitId, storage.auditEvents[0].EntityID)
+			}
+		})
+	}
+}
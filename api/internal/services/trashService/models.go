@@ -0,0 +1,28 @@
+package trashService
+
+import "time"
+
+// Trash is everything a user has deleted that can still be restored.
+type Trash struct {
+	Habits  []TrashedHabit `json:"habits"`
+	Entries []TrashedEntry `json:"entries"`
+}
+
+// TrashedHabit is a deleted habit. Its entries are restored along with it.
+type TrashedHabit struct {
+	DeletedAt time.Time  `json:"deletedAt"`
+	PurgeAt   *time.Time `json:"purgeAt,omitempty"`
+	Name      string     `json:"name"`
+	Colour    string     `json:"colour"`
+	Id        int64      `json:"id"`
+}
+
+// TrashedEntry is a deleted habit entry, its date formatted as YYYY-MM-DD.
+type TrashedEntry struct {
+	DeletedAt time.Time  `json:"deletedAt"`
+	PurgeAt   *time.Time `json:"purgeAt,omitempty"`
+	Date      string     `json:"date"`
+	Id        int64      `json:"id"`
+	HabitId   int64      `json:"habitId"`
+	Value     float64    `json:"value"`
+}
@@ -0,0 +1,292 @@
+package trashService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/services/habitsService"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+const (
+	TypeHabits  = "habits"
+	TypeEntries = "entries"
+)
+
+var (
+	ErrNotInTrash  = errors.New("not found in the trash")
+	ErrInvalidType = errors.New("trash type must be habits or entries")
+)
+
+type TrashStorage interface {
+	GetTrashedHabits(ctx context.Context, userID int64) ([]repository.Habit, error)
+	GetTrashedHabitEntries(ctx context.Context, userID int64) ([]repository.HabitEntry, error)
+	RestoreHabit(ctx context.Context, arg repository.RestoreHabitParams) (repository.Habit, error)
+	RestoreHabitEntry(ctx context.Context, arg repository.RestoreHabitEntryParams) (repository.HabitEntry, error)
+	PurgeHabits(ctx context.Context, deletedAt sql.NullString) ([]repository.Habit, error)
+	PurgeHabitEntries(ctx context.Context, deletedAt sql.NullString) ([]repository.HabitEntry, error)
+	GetHabitOwner(ctx context.Context, id int64) (int64, error)
+	CreateAuditEvent(ctx context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error)
+}
+
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(queries repository.Querier) error) error
+}
+
+// EventPublisher tells a user's other devices about restored habits and entries.
+type EventPublisher interface {
+	Publish(userId int64, eventType eventsService.EventType, data any)
+}
+
+// TrashService restores deleted habits and entries, and permanently deletes
+// them once they have been in the trash for longer than the retention period.
+// A retention period of 0 keeps them forever.
+type TrashService struct {
+	storage    TrashStorage
+	transactor Transactor
+	logger     logger.Logger
+	events     EventPublisher
+	now        func() time.Time
+	retention  time.Duration
+}
+
+func NewTrashService(storage TrashStorage, transactor Transactor, logger logger.Logger, events EventPublisher, retention time.Duration) *TrashService {
+	return &TrashService{
+		storage:    storage,
+		transactor: transactor,
+		logger:     logger,
+		events:     events,
+		now:        time.Now,
+		retention:  retention,
+	}
+}
+
+// GetTrash returns a user's deleted habits and entries, most recently deleted first.
+func (s *TrashService) GetTrash(userId int64) (Trash, error) {
+	ctx := context.Background()
+	habits, err := s.storage.GetTrashedHabits(ctx, userId)
+	if err != nil {
+		return Trash{}, err
+	}
+
+	entries, err := s.storage.GetTrashedHabitEntries(ctx, userId)
+	if err != nil {
+		return Trash{}, err
+	}
+
+	trash := Trash{
+		Habits:  make([]TrashedHabit, len(habits)),
+		Entries: make([]TrashedEntry, len(entries)),
+	}
+	for i, habit := range habits {
+		deletedAt, err := time.Parse(time.DateTime, habit.DeletedAt.String)
+		if err != nil {
+			return Trash{}, err
+		}
+
+		trash.Habits[i] = TrashedHabit{
+			DeletedAt: deletedAt,
+			PurgeAt:   s.purgeAt(deletedAt),
+			Name:      habit.Name,
+			Colour:    habit.Colour,
+			Id:        habit.ID,
+		}
+	}
+	for i, entry := range entries {
+		deletedAt, err := time.Parse(time.DateTime, entry.DeletedAt.String)
+		if err != nil {
+			return Trash{}, err
+		}
+
+		trash.Entries[i] = TrashedEntry{
+			DeletedAt: deletedAt,
+			PurgeAt:   s.purgeAt(deletedAt),
+			Date:      entry.Date,
+			Id:        entry.ID,
+			HabitId:   entry.HabitID,
+			Value:     entry.Value,
+		}
+	}
+
+	return trash, nil
+}
+
+// Restore takes a user's habit or entry out of the trash, recording it as
+// created again in the audit log and telling the user's other devices about it.
+// Entries can only be restored while their habit is not in the trash.
+func (s *TrashService) Restore(userId int64, itemType string, id int64) error {
+	ctx := context.Background()
+	updatedAt := s.now().UTC().Format(time.DateTime)
+
+	var eventType eventsService.EventType
+	var restored any
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		eventType, restored, err = restore(ctx, queries, userId, itemType, id, updatedAt)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(userId, eventType, restored)
+	return nil
+}
+
+// restore takes an item out of the trash, returning the event to publish and
+// the restored habit or entry.
+func restore(ctx context.Context, trashStorage TrashStorage, userId int64, itemType string, id int64, updatedAt string) (eventsService.EventType, any, error) {
+	change := auditService.Change{Action: auditService.ActionCreate, UserId: userId, EntityId: id}
+	var eventType eventsService.EventType
+	switch itemType {
+	case TypeHabits:
+		habit, err := trashStorage.RestoreHabit(ctx, repository.RestoreHabitParams{UpdatedAt: updatedAt, ID: id, UserID: userId})
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, ErrNotInTrash
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		eventType = eventsService.HabitCreated
+		change.EntityType = auditService.EntityHabit
+		change.After = habitsService.NewHabitFromStorage(habit, nil)
+	case TypeEntries:
+		rawEntry, err := trashStorage.RestoreHabitEntry(ctx, repository.RestoreHabitEntryParams{UpdatedAt: updatedAt, ID: id, UserID: userId})
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, ErrNotInTrash
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		eventType = eventsService.EntryCreated
+		change.EntityType = auditService.EntityHabitEntry
+		change.After, err = storage.NewHabitEntryFromStorage(rawEntry)
+		if err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, ErrInvalidType
+	}
+
+	err := auditService.Record(ctx, trashStorage, change)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return eventType, change.After, nil
+}
+
+// Purge permanently deletes everything that has been in the trash for longer
+// than the retention period, recording each deletion in the audit log and
+// returning how many habits and entries were deleted.
+func (s *TrashService) Purge() (int64, int64, error) {
+	if s.retention == 0 {
+		return 0, 0, nil
+	}
+
+	ctx := context.Background()
+	before := sql.NullString{String: s.now().UTC().Add(-s.retention).Format(time.DateTime), Valid: true}
+	var habits, entries int64
+	err := s.transactor.Transaction(ctx, func(queries repository.Querier) error {
+		var err error
+		habits, entries, err = purge(ctx, queries, before)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if habits > 0 || entries > 0 {
+		s.logger.Info("Purged trash", slog.Int64("habits", habits), slog.Int64("entries", entries))
+	}
+
+	return habits, entries, nil
+}
+
+// purge deletes the habits and entries moved to the trash before a time. The
+// entries go first, while the habits they belong to still exist to say who
+// owned them.
+func purge(ctx context.Context, trashStorage TrashStorage, before sql.NullString) (int64, int64, error) {
+	entries, err := trashStorage.PurgeHabitEntries(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, rawEntry := range entries {
+		userId, err := trashStorage.GetHabitOwner(ctx, rawEntry.HabitID)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		entry, err := storage.NewHabitEntryFromStorage(rawEntry)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		err = auditService.Record(ctx, trashStorage, auditService.Change{
+			Before:     entry,
+			EntityType: auditService.EntityHabitEntry,
+			Action:     auditService.ActionDelete,
+			UserId:     userId,
+			EntityId:   entry.Id,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	habits, err := trashStorage.PurgeHabits(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, habit := range habits {
+		err := auditService.Record(ctx, trashStorage, auditService.Change{
+			Before:     habitsService.NewHabitFromStorage(habit, nil),
+			EntityType: auditService.EntityHabit,
+			Action:     auditService.ActionDelete,
+			UserId:     habit.UserID,
+			EntityId:   habit.ID,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return int64(len(habits)), int64(len(entries)), nil
+}
+
+// Run purges the trash every interval until ctx is cancelled.
+func (s *TrashService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, _, err := s.Purge(); err != nil {
+			s.logger.Error("Failed to purge trash", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TrashService) purgeAt(deletedAt time.Time) *time.Time {
+	if s.retention == 0 {
+		return nil
+	}
+
+	purgeAt := deletedAt.Add(s.retention)
+	return &purgeAt
+}
@@ -0,0 +1,314 @@
+package trashService
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/services/eventsService"
+	"github.com/ReidMason/habit-tracker/internal/storage"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTrashStorage struct {
+	habits      []repository.Habit
+	entries     []repository.HabitEntry
+	purgedSince []string
+	auditEvents []repository.CreateAuditEventParams
+}
+
+func (m *mockTrashStorage) GetTrashedHabits(_ context.Context, _ int64) ([]repository.Habit, error) {
+	return m.habits, nil
+}
+
+func (m *mockTrashStorage) GetTrashedHabitEntries(_ context.Context, _ int64) ([]repository.HabitEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockTrashStorage) RestoreHabit(_ context.Context, arg repository.RestoreHabitParams) (repository.Habit, error) {
+	for _, habit := range m.habits {
+		if habit.ID == arg.ID && habit.UserID == arg.UserID {
+			return habit, nil
+		}
+	}
+
+	return repository.Habit{}, sql.ErrNoRows
+}
+
+func (m *mockTrashStorage) RestoreHabitEntry(_ context.Context, arg repository.RestoreHabitEntryParams) (repository.HabitEntry, error) {
+	for _, entry := range m.entries {
+		if entry.ID == arg.ID {
+			return entry, nil
+		}
+	}
+
+	return repository.HabitEntry{}, sql.ErrNoRows
+}
+
+func (m *mockTrashStorage) PurgeHabits(_ context.Context, deletedAt sql.NullString) ([]repository.Habit, error) {
+	m.purgedSince = append(m.purgedSince, deletedAt.String)
+	return m.habits, nil
+}
+
+func (m *mockTrashStorage) PurgeHabitEntries(_ context.Context, deletedAt sql.NullString) ([]repository.HabitEntry, error) {
+	m.purgedSince = append(m.purgedSince, deletedAt.String)
+	return m.entries, nil
+}
+
+func (m *mockTrashStorage) GetHabitOwner(_ context.Context, id int64) (int64, error) {
+	for _, habit := range m.habits {
+		if habit.ID == id {
+			return habit.UserID, nil
+		}
+	}
+
+	return 0, sql.ErrNoRows
+}
+
+func (m *mockTrashStorage) CreateAuditEvent(_ context.Context, arg repository.CreateAuditEventParams) (repository.AuditEvent, error) {
+	m.auditEvents = append(m.auditEvents, arg)
+	return repository.AuditEvent{}, nil
+}
+
+// audited returns the entity type and action of each recorded audit event.
+func (m *mockTrashStorage) audited() []string {
+	audited := make([]string, len(m.auditEvents))
+	for i, event := range m.auditEvents {
+		audited[i] = event.EntityType + " " + event.Action
+	}
+
+	return audited
+}
+
+func deletedAt(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: true}
+}
+
+func TestGetTrash(t *testing.T) {
+	tests := []struct {
+		name            string
+		expectedPurgeAt *time.Time
+		retention       time.Duration
+	}{
+		{
+			name:            "includes when each item will be purged",
+			retention:       48 * time.Hour,
+			expectedPurgeAt: ptr(time.Date(2024, 12, 22, 10, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:      "leaves out the purge time when the trash is kept forever",
+			retention: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockTrashStorage{
+				habits:  []repository.Habit{{ID: 1, UserID: 7, Name: "Run", Colour: "red", DeletedAt: deletedAt("2024-12-20 10:00:00")}},
+				entries: []repository.HabitEntry{{ID: 3, HabitID: 2, Date: "2024-12-19", Value: 2, DeletedAt: deletedAt("2024-12-20 10:00:00")}},
+			}
+			service := NewTrashService(storage, nil, &logger.MockLogger{}, eventsService.NewHub(&logger.MockLogger{}), tc.retention)
+
+			// Act
+			trash, err := service.GetTrash(7)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, trash.Habits, 1)
+			assert.Equal(t, "Run", trash.Habits[0].Name)
+			assert.Equal(t, time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC), trash.Habits[0].DeletedAt)
+			assert.Equal(t, tc.expectedPurgeAt, trash.Habits[0].PurgeAt)
+			assert.Len(t, trash.Entries, 1)
+			assert.Equal(t, "2024-12-19", trash.Entries[0].Date)
+			assert.Equal(t, int64(2), trash.Entries[0].HabitId)
+			assert.Equal(t, tc.expectedPurgeAt, trash.Entries[0].PurgeAt)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		expectedErr     error
+		name            string
+		itemType        string
+		expectedEvent   eventsService.EventType
+		expectedAudited []string
+		id              int64
+	}{
+		{
+			name:            "restores a habit",
+			itemType:        TypeHabits,
+			id:              1,
+			expectedEvent:   eventsService.HabitCreated,
+			expectedAudited: []string{"habit create"},
+		},
+		{
+			name:            "restores an entry",
+			itemType:        TypeEntries,
+			id:              3,
+			expectedEvent:   eventsService.EntryCreated,
+			expectedAudited: []string{"habitEntry create"},
+		},
+		{
+			name:            "habit not in the trash",
+			itemType:        TypeHabits,
+			id:              2,
+			expectedErr:     ErrNotInTrash,
+			expectedAudited: []string{},
+		},
+		{
+			name:            "unknown type",
+			itemType:        "users",
+			id:              1,
+			expectedErr:     ErrInvalidType,
+			expectedAudited: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			storage := &mockTrashStorage{
+				habits:  []repository.Habit{{ID: 1, UserID: 7, DeletedAt: deletedAt("2024-12-20 10:00:00")}},
+				entries: []repository.HabitEntry{{ID: 3, HabitID: 1, Date: "2024-12-19", DeletedAt: deletedAt("2024-12-20 10:00:00")}},
+			}
+
+			// Act
+			eventType, _, err := restore(context.Background(), storage, 7, tc.itemType, tc.id, "2024-12-21 10:00:00")
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedEvent, eventType)
+			assert.Equal(t, tc.expectedAudited, storage.audited())
+			for _, event := range storage.auditEvents {
+				assert.Equal(t, int64(7), event.UserID)
+				assert.Equal(t, tc.id, event.EntityID)
+			}
+		})
+	}
+}
+
+func TestRestoreService(t *testing.T) {
+	// Arrange
+	db, err := storage.NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.ApplyMigrations())
+
+	ctx := context.Background()
+	user, err := db.Queries.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{UserID: user.ID, Name: "Run", Colour: "red", Active: true, ScheduleType: "daily"})
+	require.NoError(t, err)
+	_, err = db.Queries.TrashHabit(ctx, repository.TrashHabitParams{DeletedAt: deletedAt("2024-12-20 10:00:00"), UpdatedAt: "2024-12-20 10:00:00", ID: habit.ID})
+	require.NoError(t, err)
+	hub := eventsService.NewHub(&logger.MockLogger{})
+	subscription, _ := hub.Subscribe(user.ID, 0)
+	defer subscription.Close()
+	service := NewTrashService(db.Queries, db, &logger.MockLogger{}, hub, 0)
+
+	// Act
+	err = service.Restore(user.ID, TypeHabits, habit.ID)
+	audited, auditedErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.ID, Limit: 10})
+
+	// Assert
+	assert.NoError(t, err)
+	event := <-subscription.Events()
+	assert.Equal(t, eventsService.HabitCreated, event.Type)
+	assert.NoError(t, auditedErr)
+	require.Len(t, audited, 1)
+	assert.Equal(t, auditService.EntityHabit, audited[0].EntityType)
+	assert.Equal(t, auditService.ActionCreate, audited[0].Action)
+	assert.Equal(t, habit.ID, audited[0].EntityID)
+}
+
+func TestPurge(t *testing.T) {
+	// Arrange
+	storage := &mockTrashStorage{
+		habits:  []repository.Habit{{ID: 1, UserID: 7, DeletedAt: deletedAt("2024-11-01 10:00:00")}},
+		entries: []repository.HabitEntry{{ID: 3, HabitID: 1, Date: "2024-10-30"}, {ID: 4, HabitID: 1, Date: "2024-10-31"}},
+	}
+
+	// Act
+	habits, entries, err := purge(context.Background(), storage, deletedAt("2024-11-20 10:00:00"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), habits)
+	assert.Equal(t, int64(2), entries)
+	assert.Equal(t, []string{"2024-11-20 10:00:00", "2024-11-20 10:00:00"}, storage.purgedSince)
+	assert.Equal(t, []string{"habitEntry delete", "habitEntry delete", "habit delete"}, storage.audited())
+	for _, event := range storage.auditEvents {
+		assert.Equal(t, int64(7), event.UserID)
+	}
+}
+
+func TestPurgeRetention(t *testing.T) {
+	tests := []struct {
+		name            string
+		retention       time.Duration
+		expectedHabits  int64
+		expectedEntries int64
+	}{
+		{
+			name:            "purges items deleted before the retention period",
+			retention:       30 * 24 * time.Hour,
+			expectedHabits:  1,
+			expectedEntries: 1,
+		},
+		{
+			name:      "keeps everything when retention is 0",
+			retention: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			db, err := storage.NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+			require.NoError(t, err)
+			defer db.Close()
+			require.NoError(t, db.ApplyMigrations())
+
+			ctx := context.Background()
+			user, err := db.Queries.CreateUser(ctx, "alice")
+			require.NoError(t, err)
+			trash := func(name string, deletedAtTime string) {
+				habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{UserID: user.ID, Name: name, Colour: "red", Active: true, ScheduleType: "daily"})
+				require.NoError(t, err)
+				entry, err := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: "2024-11-01", Value: 1, Status: "done"})
+				require.NoError(t, err)
+				_, err = db.Queries.TrashHabitEntry(ctx, repository.TrashHabitEntryParams{DeletedAt: deletedAt(deletedAtTime), UpdatedAt: deletedAtTime, ID: entry.ID})
+				require.NoError(t, err)
+				_, err = db.Queries.TrashHabit(ctx, repository.TrashHabitParams{DeletedAt: deletedAt(deletedAtTime), UpdatedAt: deletedAtTime, ID: habit.ID})
+				require.NoError(t, err)
+			}
+			trash("Run", "2024-11-01 10:00:00")
+			trash("Read", "2024-12-01 10:00:00")
+			service := NewTrashService(db.Queries, db, &logger.MockLogger{}, eventsService.NewHub(&logger.MockLogger{}), tc.retention)
+			service.now = func() time.Time { return time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC) }
+
+			// Act
+			habits, entries, err := service.Purge()
+			audited, auditedErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.ID, Limit: 10})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHabits, habits)
+			assert.Equal(t, tc.expectedEntries, entries)
+			assert.NoError(t, auditedErr)
+			assert.Len(t, audited, int(tc.expectedHabits+tc.expectedEntries))
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrBackupExists      = errors.New("backup file already exists")
+	ErrBackupUnsupported = errors.New("backups are only supported for SQLite, use pg_dump for PostgreSQL")
+)
+
+// SupportsBackups reports whether Backup can copy this database.
+func (s Database) SupportsBackups() bool {
+	return s.dialect == "sqlite3"
+}
+
+// Backup writes a consistent copy of the database to path while it is in use.
+func (s Database) Backup(path string) error {
+	ctx := context.Background()
+	if !s.SupportsBackups() {
+		return ErrBackupUnsupported
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s", ErrBackupExists, path)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
+
+// VerifyBackup returns an error if the backup at path is corrupt.
+func (s Database) VerifyBackup(path string) error {
+	return CheckIntegrity(path)
+}
+
+// CheckIntegrity returns an error if the SQLite database at path is corrupt.
+func CheckIntegrity(path string) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	err = db.QueryRow("PRAGMA integrity_check").Scan(&result)
+	if err != nil {
+		return err
+	}
+
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	return nil
+}
+
+// Restore replaces the database at databasePath with a backup. Nothing may
+// be using the database while it is restored.
+func Restore(backupPath string, databasePath string) error {
+	err := CheckIntegrity(backupPath)
+	if err != nil {
+		return err
+	}
+
+	backup, err := os.Open(backupPath)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	err = os.MkdirAll(filepath.Dir(databasePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	// Copy next to the database first so it is replaced in a single rename.
+	restored, err := os.CreateTemp(filepath.Dir(databasePath), filepath.Base(databasePath)+".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(restored.Name())
+
+	_, err = io.Copy(restored, backup)
+	if closeErr := restored.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		err = os.Remove(databasePath + suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Rename(restored.Name(), databasePath)
+}
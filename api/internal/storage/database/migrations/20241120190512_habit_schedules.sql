@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE habits ADD COLUMN schedule_type VARCHAR(255) NOT NULL DEFAULT 'daily';
+ALTER TABLE habits ADD COLUMN schedule_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE habits ADD COLUMN schedule_weekdays INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habits DROP COLUMN schedule_weekdays;
+ALTER TABLE habits DROP COLUMN schedule_count;
+ALTER TABLE habits DROP COLUMN schedule_type;
+-- +goose StatementEnd
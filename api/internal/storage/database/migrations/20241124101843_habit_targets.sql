@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE habits ADD COLUMN target_value REAL NOT NULL DEFAULT 1;
+ALTER TABLE habits ADD COLUMN target_unit VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE habits ADD COLUMN target_comparison VARCHAR(255) NOT NULL DEFAULT 'atLeast';
+ALTER TABLE habit_entries ADD COLUMN value REAL NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habit_entries DROP COLUMN value;
+ALTER TABLE habits DROP COLUMN target_comparison;
+ALTER TABLE habits DROP COLUMN target_unit;
+ALTER TABLE habits DROP COLUMN target_value;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users ADD COLUMN admin BOOLEAN NOT NULL DEFAULT FALSE;
+-- The first user to set a password administers existing deployments
+UPDATE users SET admin = TRUE WHERE id = (SELECT MIN(id) FROM users WHERE password_hash IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE users DROP COLUMN admin;
+-- +goose StatementEnd
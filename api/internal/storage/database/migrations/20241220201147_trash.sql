@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Deleted habits and entries stay in the trash until they are purged
+ALTER TABLE habits ADD COLUMN deleted_at TEXT;
+ALTER TABLE habit_entries ADD COLUMN deleted_at TEXT;
+CREATE INDEX habits_deleted_at ON habits(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX habit_entries_deleted_at ON habit_entries(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX habit_entries_deleted_at;
+DROP INDEX habits_deleted_at;
+ALTER TABLE habit_entries DROP COLUMN deleted_at;
+ALTER TABLE habits DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Every change to a user's data, the JSON of the entity before and after the
+-- change and who made it. The actor is null for changes made from the CLI.
+CREATE TABLE audit_events (
+    id INTEGER NOT NULL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    actor_id INTEGER,
+    entity_type VARCHAR(255) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    data_before TEXT,
+    data_after TEXT,
+    request_id TEXT,
+    created_at TEXT NOT NULL DEFAULT(datetime('now')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE INDEX audit_events_user_id ON audit_events(user_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX audit_events_user_id;
+DROP TABLE audit_events;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE habit_entries ADD COLUMN note TEXT NOT NULL DEFAULT '';
+-- One journal entry per user per day, independent of their habits
+CREATE TABLE journal_entries (
+    id INTEGER NOT NULL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    date TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT(datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT(datetime('now')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE(user_id, date)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE journal_entries;
+ALTER TABLE habit_entries DROP COLUMN note;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE categories (
+    id INTEGER NOT NULL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    colour VARCHAR(255) NOT NULL DEFAULT '#0284c7',
+    created_at TEXT NOT NULL DEFAULT(datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT(datetime('now')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE(user_id, name)
+);
+ALTER TABLE habits ADD COLUMN icon VARCHAR(255) NOT NULL DEFAULT '';
+-- Habits are moved out of a category when it is deleted, SQLite can't drop
+-- columns with foreign keys so this isn't left to ON DELETE SET NULL
+ALTER TABLE habits ADD COLUMN category_id INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habits DROP COLUMN category_id;
+ALTER TABLE habits DROP COLUMN icon;
+DROP TABLE categories;
+-- +goose StatementEnd
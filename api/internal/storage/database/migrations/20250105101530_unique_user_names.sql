@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Users created before accounts existed could share a name. Keep the name for
+-- the user who can sign in, or else the oldest, and add the ID to the others.
+UPDATE users SET name = name || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM users AS other
+    WHERE other.name = users.name AND other.id <> users.id AND (
+        (other.password_hash IS NOT NULL AND users.password_hash IS NULL)
+        OR ((other.password_hash IS NULL) = (users.password_hash IS NULL) AND other.id < users.id)
+    )
+);
+
+DROP INDEX users_name_with_password;
+CREATE UNIQUE INDEX users_name ON users(name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX users_name;
+CREATE UNIQUE INDEX users_name_with_password ON users(name) WHERE password_hash IS NOT NULL;
+-- +goose StatementEnd
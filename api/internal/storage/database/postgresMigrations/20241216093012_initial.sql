@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- Matches the SQLite schema column for column so both backends share the same
+-- row types. Timestamps are stored as UTC text like SQLite's datetime('now').
+CREATE TABLE users (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    password_hash TEXT,
+    admin BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX users_name_with_password ON users(name) WHERE password_hash IS NOT NULL;
+
+CREATE TABLE habits (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    colour VARCHAR(255) NOT NULL DEFAULT '#0284c7',
+    "index" BIGINT NOT NULL DEFAULT 1,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    schedule_type VARCHAR(255) NOT NULL DEFAULT 'daily',
+    schedule_count BIGINT NOT NULL DEFAULT 1,
+    schedule_weekdays BIGINT NOT NULL DEFAULT 0,
+    target_value DOUBLE PRECISION NOT NULL DEFAULT 1,
+    target_unit VARCHAR(255) NOT NULL DEFAULT '',
+    target_comparison VARCHAR(255) NOT NULL DEFAULT 'atLeast',
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX habits_user_id ON habits(user_id);
+
+CREATE TABLE habit_entries (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    habit_id BIGINT NOT NULL,
+    date TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    value DOUBLE PRECISION NOT NULL DEFAULT 1,
+    FOREIGN KEY(habit_id) REFERENCES habits(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE(habit_id, date)
+);
+
+CREATE TABLE sessions (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE api_tokens (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scope VARCHAR(255) NOT NULL,
+    last_used_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE api_tokens;
+DROP TABLE sessions;
+DROP TABLE habit_entries;
+DROP TABLE habits;
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Archived habits are hidden but keep their entries until they are purged
+ALTER TABLE habits ADD COLUMN archived_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habits DROP COLUMN archived_at;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE habit_entries ADD COLUMN note TEXT NOT NULL DEFAULT '';
+-- One journal entry per user per day, independent of their habits
+CREATE TABLE journal_entries (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    date TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE(user_id, date)
+);
+-- Full text search over notes and the journal
+CREATE INDEX habit_entries_note_search ON habit_entries USING GIN (to_tsvector('english', note));
+CREATE INDEX journal_entries_text_search ON journal_entries USING GIN (to_tsvector('english', text));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE journal_entries;
+DROP INDEX habit_entries_note_search;
+ALTER TABLE habit_entries DROP COLUMN note;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Whether the day was done, skipped as an excused day or explicitly failed
+ALTER TABLE habit_entries ADD COLUMN status VARCHAR(255) NOT NULL DEFAULT 'done';
+-- How many skipped days a month keep a combo going, unlimited when null
+ALTER TABLE habits ADD COLUMN schedule_freezes BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habits DROP COLUMN schedule_freezes;
+ALTER TABLE habit_entries DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users ADD COLUMN timezone VARCHAR(255) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN day_start_hour BIGINT NOT NULL DEFAULT 0;
+-- Bulk habit updates stored RFC3339 timestamps, convert them to UTC like the rest
+UPDATE habits SET updated_at = to_char(updated_at::timestamptz AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS') WHERE updated_at LIKE '%T%';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE users DROP COLUMN day_start_hour;
+ALTER TABLE users DROP COLUMN timezone;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE categories (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    colour VARCHAR(255) NOT NULL DEFAULT '#0284c7',
+    created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE(user_id, name)
+);
+ALTER TABLE habits ADD COLUMN icon VARCHAR(255) NOT NULL DEFAULT '';
+-- Matches SQLite, where habits are moved out of a deleted category by the app
+ALTER TABLE habits ADD COLUMN category_id BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE habits DROP COLUMN category_id;
+ALTER TABLE habits DROP COLUMN icon;
+DROP TABLE categories;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- name: CreateAuditEvent :one
+-- Record a change to a user's data
+INSERT INTO audit_events (user_id, actor_id, entity_type, entity_id, action, data_before, data_after, request_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: GetAuditEvents :many
+-- Retrieve up to limit of a user's most recent audit events before the cursor id, each filter is ignored when null
+SELECT * FROM audit_events
+WHERE user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+    AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+    AND (sqlc.narg(entity_id)::bigint IS NULL OR entity_id = sqlc.narg(entity_id))
+    AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+    AND (sqlc.narg(from_time)::text IS NULL OR created_at >= sqlc.narg(from_time))
+    AND (sqlc.narg(to_time)::text IS NULL OR created_at <= sqlc.narg(to_time))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')::bigint;
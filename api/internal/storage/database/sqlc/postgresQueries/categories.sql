@@ -0,0 +1,19 @@
+-- name: GetCategories :many
+-- Retrieve all of a user's categories
+SELECT * FROM categories WHERE user_id = $1 ORDER BY name, id;
+
+-- name: GetCategory :one
+-- Retrieve a user's category by ID
+SELECT * FROM categories WHERE id = $1 AND user_id = $2;
+
+-- name: CreateCategory :one
+-- Create a new category
+INSERT INTO categories (user_id, name, colour) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: UpdateCategory :one
+-- Rename or recolour a user's category
+UPDATE categories SET name = $1, colour = $2, updated_at = $3 WHERE id = $4 AND user_id = $5 RETURNING *;
+
+-- name: DeleteCategory :one
+-- Delete a user's category
+DELETE FROM categories WHERE id = $1 AND user_id = $2 RETURNING *;
@@ -0,0 +1,24 @@
+-- name: SaveJournalEntry :one
+-- Create or replace a user's journal entry for a day
+INSERT INTO journal_entries (user_id, date, text, updated_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, date) DO UPDATE SET text = excluded.text, updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetJournalEntry :one
+-- Retrieve a user's journal entry for a day
+SELECT * FROM journal_entries WHERE user_id = $1 AND date = $2;
+
+-- name: GetJournalEntriesBetween :many
+-- Retrieve a user's journal entries between two dates inclusive
+SELECT * FROM journal_entries WHERE user_id = sqlc.arg(user_id) AND date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date) ORDER BY date;
+
+-- name: DeleteJournalEntry :one
+-- Delete a user's journal entry for a day
+DELETE FROM journal_entries WHERE user_id = $1 AND date = $2 RETURNING *;
+
+-- name: SearchJournalEntries :many
+-- Retrieve up to limit of a user's most recent journal entries matching a full text search
+SELECT * FROM journal_entries
+WHERE user_id = sqlc.arg(user_id) AND to_tsvector('english', text) @@ plainto_tsquery('english', sqlc.arg(query)::text)
+ORDER BY date DESC
+LIMIT sqlc.arg('limit')::bigint;
@@ -0,0 +1,15 @@
+-- name: CreateSession :one
+-- Create a new session for a user
+INSERT INTO sessions (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetSessionUser :one
+-- Retrieve the user for an unexpired session
+SELECT users.id, users.name, users.admin, users.timezone, users.day_start_hour FROM sessions JOIN users ON users.id = sessions.user_id WHERE sessions.token_hash = $1 AND sessions.expires_at > $2;
+
+-- name: DeleteSession :exec
+-- Delete a session
+DELETE FROM sessions WHERE token_hash = $1;
+
+-- name: DeleteExpiredSessions :exec
+-- Delete all sessions that have expired
+DELETE FROM sessions WHERE expires_at <= $1;
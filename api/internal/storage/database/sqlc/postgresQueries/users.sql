@@ -0,0 +1,35 @@
+-- name: GetUsers :many
+-- Retrieve all users
+SELECT * FROM users ORDER BY id;
+
+-- name: CreateUser :one
+-- Create a new user
+INSERT INTO users (name) VALUES ($1) RETURNING *;
+
+-- name: GetUserByID :one
+-- Retrieve a user by ID
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetUserByName :one
+-- Retrieve the oldest user with a name
+SELECT * FROM users WHERE name = $1 ORDER BY id LIMIT 1;
+
+-- name: CreateUserWithPassword :one
+-- Create a new user that can sign in
+INSERT INTO users (name, password_hash) VALUES ($1, $2) RETURNING *;
+
+-- name: UpdateUserPassword :one
+-- Set the password of a user
+UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3 RETURNING *;
+
+-- name: DeleteUser :execrows
+-- Delete a user along with all of their habits, sessions and API tokens
+DELETE FROM users WHERE id = $1;
+
+-- name: SetUserAdmin :execrows
+-- Grant or revoke a user's access to administration endpoints
+UPDATE users SET admin = $1, updated_at = $2 WHERE id = $3;
+
+-- name: UpdateUserSettings :one
+-- Set the timezone and day start hour of a user
+UPDATE users SET timezone = $1, day_start_hour = $2, updated_at = $3 WHERE id = $4 RETURNING *;
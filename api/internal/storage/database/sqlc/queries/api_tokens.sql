@@ -0,0 +1,19 @@
+-- name: CreateApiToken :one
+-- Create a new API token for a user
+INSERT INTO api_tokens (user_id, name, token_hash, scope) VALUES (?, ?, ?, ?) RETURNING *;
+
+-- name: GetApiTokens :many
+-- Retrieve all API tokens for a user
+SELECT * FROM api_tokens WHERE user_id = ? ORDER BY id;
+
+-- name: DeleteApiToken :execrows
+-- Delete an API token belonging to a user
+DELETE FROM api_tokens WHERE id = ? AND user_id = ?;
+
+-- name: GetApiTokenUser :one
+-- Retrieve an API token and the user it belongs to
+SELECT api_tokens.id, api_tokens.scope, users.id AS user_id, users.name, users.admin, users.timezone, users.day_start_hour FROM api_tokens JOIN users ON users.id = api_tokens.user_id WHERE api_tokens.token_hash = ?;
+
+-- name: UpdateApiTokenLastUsed :exec
+-- Record when an API token was last used
+UPDATE api_tokens SET last_used_at = ? WHERE id = ?;
@@ -0,0 +1,16 @@
+-- name: CreateAuditEvent :one
+-- Record a change to a user's data
+INSERT INTO audit_events (user_id, actor_id, entity_type, entity_id, action, data_before, data_after, request_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: GetAuditEvents :many
+-- Retrieve up to limit of a user's most recent audit events before the cursor id, each filter is ignored when null
+SELECT * FROM audit_events
+WHERE user_id = sqlc.arg(user_id)
+    AND (CAST(sqlc.narg(before_id) AS INTEGER) IS NULL OR id < sqlc.narg(before_id))
+    AND (CAST(sqlc.narg(entity_type) AS TEXT) IS NULL OR entity_type = sqlc.narg(entity_type))
+    AND (CAST(sqlc.narg(entity_id) AS INTEGER) IS NULL OR entity_id = sqlc.narg(entity_id))
+    AND (CAST(sqlc.narg(action) AS TEXT) IS NULL OR action = sqlc.narg(action))
+    AND (CAST(sqlc.narg(from_time) AS TEXT) IS NULL OR created_at >= sqlc.narg(from_time))
+    AND (CAST(sqlc.narg(to_time) AS TEXT) IS NULL OR created_at <= sqlc.narg(to_time))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
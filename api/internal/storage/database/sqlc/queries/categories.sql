@@ -0,0 +1,19 @@
+-- name: GetCategories :many
+-- Retrieve all of a user's categories
+SELECT * FROM categories WHERE user_id = ? ORDER BY name, id;
+
+-- name: GetCategory :one
+-- Retrieve a user's category by ID
+SELECT * FROM categories WHERE id = ? AND user_id = ?;
+
+-- name: CreateCategory :one
+-- Create a new category
+INSERT INTO categories (user_id, name, colour) VALUES (?, ?, ?) RETURNING *;
+
+-- name: UpdateCategory :one
+-- Rename or recolour a user's category
+UPDATE categories SET name = ?, colour = ?, updated_at = ? WHERE id = ? AND user_id = ? RETURNING *;
+
+-- name: DeleteCategory :one
+-- Delete a user's category
+DELETE FROM categories WHERE id = ? AND user_id = ? RETURNING *;
@@ -1,11 +1,79 @@
 -- name: CreateHabitEntry :one
--- Create a new habit entry
-INSERT INTO habit_entries (habit_id, date) VALUES (?, ?) RETURNING *;
+-- Create a new habit entry, replacing an entry for the same day in the trash
+INSERT INTO habit_entries (habit_id, date, value, status) VALUES (?, ?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET value = excluded.value, note = excluded.note, status = excluded.status, deleted_at = NULL, updated_at = datetime('now')
+WHERE habit_entries.deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: IncrementHabitEntry :one
+-- Add to the value of a habit entry and mark it done, creating it if it does not exist or is in the trash
+INSERT INTO habit_entries (habit_id, date, value) VALUES (?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET
+    value = CASE WHEN habit_entries.deleted_at IS NULL THEN habit_entries.value + excluded.value ELSE excluded.value END,
+    note = CASE WHEN habit_entries.deleted_at IS NULL THEN habit_entries.note ELSE excluded.note END,
+    status = excluded.status,
+    deleted_at = NULL,
+    updated_at = datetime('now')
+RETURNING *;
+
+-- name: ImportHabitEntry :one
+-- Create a habit entry unless one already exists for the day, replacing an entry in the trash. Returns no row when one already exists
+INSERT INTO habit_entries (habit_id, date, value, note, status) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET value = excluded.value, note = excluded.note, status = excluded.status, deleted_at = NULL, updated_at = datetime('now')
+WHERE habit_entries.deleted_at IS NOT NULL RETURNING *;
 
 -- name: GetHabitEntries :many
 -- Retrieve all habit entries for a habit
-SELECT * FROM habit_entries WHERE habit_id = ?;
+SELECT * FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL ORDER BY date;
+
+-- name: GetHabitEntriesBetween :many
+-- Retrieve up to limit habit entries for a habit between two dates inclusive
+SELECT * FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL AND date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date) ORDER BY date LIMIT ?;
+
+-- name: GetHabitEntriesBefore :many
+-- Retrieve up to limit of the most recent habit entries for a habit before a date
+SELECT * FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL AND date < ? ORDER BY date DESC LIMIT ?;
+
+-- name: GetUserHabitEntriesBetween :many
+-- Retrieve the habit entries of all habits for a user between two dates inclusive
+SELECT habit_entries.* FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NULL AND habit_entries.date >= sqlc.arg(from_date) AND habit_entries.date <= sqlc.arg(to_date) ORDER BY habit_entries.habit_id, habit_entries.date;
+
+-- name: TrashHabitEntry :one
+-- Move a habit entry to the trash
+UPDATE habit_entries SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING *;
+
+-- name: GetTrashedHabitEntries :many
+-- Retrieve the habit entries a user has moved to the trash, most recently deleted first
+SELECT habit_entries.* FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NOT NULL ORDER BY habit_entries.deleted_at DESC, habit_entries.id;
+
+-- name: RestoreHabitEntry :one
+-- Take a user's habit entry out of the trash
+UPDATE habit_entries SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL AND habit_id IN (SELECT id FROM habits WHERE user_id = ? AND deleted_at IS NULL) RETURNING *;
+
+-- name: PurgeHabitEntries :many
+-- Permanently delete habit entries moved to the trash before a time
+DELETE FROM habit_entries WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING *;
+
+-- name: GetHabitEntryOwner :one
+-- Retrieve the ID of the user a habit entry belongs to
+SELECT habits.user_id FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habit_entries.id = ? AND habit_entries.deleted_at IS NULL AND habits.deleted_at IS NULL;
+
+-- name: SetHabitEntryNote :one
+-- Set the note on a habit entry
+UPDATE habit_entries SET note = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING *;
+
+-- name: SetHabitEntryStatus :one
+-- Set whether a habit entry was done, skipped or failed
+UPDATE habit_entries SET status = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING *;
+
+-- name: GetHabitEntry :one
+-- Retrieve a habit entry that is not in the trash
+SELECT * FROM habit_entries WHERE id = ? AND deleted_at IS NULL;
 
--- name: DeleteHabitEntry :one
--- Delete a habit entry
-DELETE FROM habit_entries WHERE id = ? RETURNING *;
+-- name: SearchHabitEntryNotes :many
+-- Retrieve up to limit of the most recent notes on a user's habit entries containing the query, for when FTS5 is not available
+SELECT habit_entries.id, habit_entries.habit_id, habits.name AS habit_name, habit_entries.date, habit_entries.note FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id
+WHERE habits.user_id = sqlc.arg(user_id) AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NULL
+    AND instr(lower(habit_entries.note), lower(CAST(sqlc.arg(query) AS TEXT))) > 0
+ORDER BY habit_entries.date DESC, habit_entries.id DESC
+LIMIT sqlc.arg('limit');
@@ -1,19 +1,64 @@
 -- name: GetHabits :many
 -- Retrieve all habits for a user
-SELECT * FROM habits WHERE user_id = ?;
+SELECT * FROM habits WHERE user_id = ? AND deleted_at IS NULL ORDER BY id;
 
 -- name: CreateHabit :one
 -- Create a new habit
-INSERT INTO habits (user_id, name, description, colour, `index`) VALUES (?, ?, ?, ?, ?) RETURNING *;
+INSERT INTO habits (user_id, name, description, colour, `index`, active, schedule_type, schedule_count, schedule_weekdays, schedule_freezes, target_value, target_unit, target_comparison, archived_at, icon, category_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: GetHabit :one
 -- Retrieve a habit by ID
-SELECT * FROM habits WHERE id = ?;
+SELECT * FROM habits WHERE id = ? AND deleted_at IS NULL;
 
--- name: DeleteHabit :one
--- Delete a habit by ID
-DELETE FROM habits WHERE id = ? RETURNING *;
+-- name: GetHabitOwner :one
+-- Retrieve the ID of the user a habit belongs to, including habits in the trash
+SELECT user_id FROM habits WHERE id = ?;
+
+-- name: TrashHabit :one
+-- Move a habit and its entries to the trash
+UPDATE habits SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL RETURNING *;
 
 -- name: UpdateHabit :one
--- Update a habit by ID
-UPDATE habits SET name = ?, description = ?, colour = ?, `index` = ?, active = ?, updated_at = ? WHERE id = ? RETURNING *;
+-- Update a habit by ID if it is still at version, keeping the existing description, icon, category, schedule and target when none is given
+UPDATE habits SET
+    name = ?,
+    description = NULLIF(COALESCE(sqlc.narg(description), description), ''),
+    icon = COALESCE(sqlc.narg(icon), icon),
+    category_id = NULLIF(COALESCE(sqlc.narg(category_id), category_id), 0),
+    colour = ?,
+    `index` = ?,
+    active = ?,
+    schedule_type = COALESCE(sqlc.narg(schedule_type), schedule_type),
+    schedule_count = COALESCE(sqlc.narg(schedule_count), schedule_count),
+    schedule_weekdays = COALESCE(sqlc.narg(schedule_weekdays), schedule_weekdays),
+    schedule_freezes = CASE WHEN CAST(sqlc.narg(schedule_type) AS TEXT) IS NULL THEN schedule_freezes ELSE CAST(sqlc.narg(schedule_freezes) AS INTEGER) END,
+    target_value = COALESCE(sqlc.narg(target_value), target_value),
+    target_unit = COALESCE(sqlc.narg(target_unit), target_unit),
+    target_comparison = COALESCE(sqlc.narg(target_comparison), target_comparison),
+    updated_at = ?,
+    version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL RETURNING *;
+
+-- name: SetHabitIndex :execrows
+-- Move a user's habit to a new position
+UPDATE habits SET `index` = ?, updated_at = ?, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+
+-- name: SetHabitArchivedAt :one
+-- Archive a habit, or restore it when archived_at is null
+UPDATE habits SET archived_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL RETURNING *;
+
+-- name: GetTrashedHabits :many
+-- Retrieve the habits a user has moved to the trash, most recently deleted first
+SELECT * FROM habits WHERE user_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id;
+
+-- name: RestoreHabit :one
+-- Take a user's habit out of the trash
+UPDATE habits SET deleted_at = NULL, updated_at = ?, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: PurgeHabits :many
+-- Permanently delete habits moved to the trash before a time, along with their entries
+DELETE FROM habits WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING *;
+
+-- name: ClearHabitCategory :many
+-- Move a user's habits out of a category
+UPDATE habits SET category_id = NULL, updated_at = ?, version = version + 1 WHERE user_id = ? AND category_id = ? RETURNING *;
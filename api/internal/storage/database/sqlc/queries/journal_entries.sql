@@ -0,0 +1,24 @@
+-- name: SaveJournalEntry :one
+-- Create or replace a user's journal entry for a day
+INSERT INTO journal_entries (user_id, date, text, updated_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id, date) DO UPDATE SET text = excluded.text, updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetJournalEntry :one
+-- Retrieve a user's journal entry for a day
+SELECT * FROM journal_entries WHERE user_id = ? AND date = ?;
+
+-- name: GetJournalEntriesBetween :many
+-- Retrieve a user's journal entries between two dates inclusive
+SELECT * FROM journal_entries WHERE user_id = ? AND date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date) ORDER BY date;
+
+-- name: DeleteJournalEntry :one
+-- Delete a user's journal entry for a day
+DELETE FROM journal_entries WHERE user_id = ? AND date = ? RETURNING *;
+
+-- name: SearchJournalEntries :many
+-- Retrieve up to limit of a user's most recent journal entries containing the query, for when FTS5 is not available
+SELECT * FROM journal_entries
+WHERE user_id = sqlc.arg(user_id) AND instr(lower(text), lower(CAST(sqlc.arg(query) AS TEXT))) > 0
+ORDER BY date DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,35 @@
+-- name: GetTags :many
+-- Retrieve all of a user's tags
+SELECT * FROM tags WHERE user_id = ? ORDER BY name, id;
+
+-- name: GetTag :one
+-- Retrieve a user's tag by ID
+SELECT * FROM tags WHERE id = ? AND user_id = ?;
+
+-- name: CreateTag :one
+-- Create a new tag
+INSERT INTO tags (user_id, name) VALUES (?, ?) RETURNING *;
+
+-- name: UpdateTag :one
+-- Rename a user's tag
+UPDATE tags SET name = ?, updated_at = ? WHERE id = ? AND user_id = ? RETURNING *;
+
+-- name: DeleteTag :one
+-- Delete a user's tag, removing it from its habits
+DELETE FROM tags WHERE id = ? AND user_id = ? RETURNING *;
+
+-- name: GetHabitTags :many
+-- Retrieve the tags on a habit
+SELECT tags.* FROM tags JOIN habit_tags ON habit_tags.tag_id = tags.id WHERE habit_tags.habit_id = ? ORDER BY tags.name, tags.id;
+
+-- name: GetUserHabitTags :many
+-- Retrieve the tags on all of a user's habits
+SELECT habit_tags.habit_id, tags.id, tags.name FROM habit_tags JOIN tags ON tags.id = habit_tags.tag_id WHERE tags.user_id = ? ORDER BY tags.name, tags.id;
+
+-- name: AttachHabitTag :execrows
+-- Tag a habit, doing nothing if it already has the tag
+INSERT INTO habit_tags (habit_id, tag_id) VALUES (?, ?) ON CONFLICT (habit_id, tag_id) DO NOTHING;
+
+-- name: DetachHabitTag :execrows
+-- Remove a tag from a habit
+DELETE FROM habit_tags WHERE habit_id = ? AND tag_id = ?;
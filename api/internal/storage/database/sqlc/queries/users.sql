@@ -1,6 +1,6 @@
 -- name: GetUsers :many
--- Retrieve all habits
-SELECT * FROM users;
+-- Retrieve all users
+SELECT * FROM users ORDER BY id;
 
 -- name: CreateUser :one
 -- Create a new user
@@ -9,3 +9,27 @@ INSERT INTO users (name) VALUES (?) RETURNING *;
 -- name: GetUserByID :one
 -- Retrieve a user by ID
 SELECT * FROM users WHERE id = ?;
+
+-- name: GetUserByName :one
+-- Retrieve the oldest user with a name
+SELECT * FROM users WHERE name = ? ORDER BY id LIMIT 1;
+
+-- name: CreateUserWithPassword :one
+-- Create a new user that can sign in
+INSERT INTO users (name, password_hash) VALUES (?, ?) RETURNING *;
+
+-- name: UpdateUserPassword :one
+-- Set the password of a user
+UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ? RETURNING *;
+
+-- name: DeleteUser :execrows
+-- Delete a user along with all of their habits, sessions and API tokens
+DELETE FROM users WHERE id = ?;
+
+-- name: SetUserAdmin :execrows
+-- Grant or revoke a user's access to administration endpoints
+UPDATE users SET admin = ?, updated_at = ? WHERE id = ?;
+
+-- name: UpdateUserSettings :one
+-- Set the timezone and day start hour of a user
+UPDATE users SET timezone = ?, day_start_hour = ?, updated_at = ? WHERE id = ? RETURNING *;
@@ -7,20 +7,31 @@ package sqlite3Storage
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createHabitEntry = `-- name: CreateHabitEntry :one
-INSERT INTO habit_entries (habit_id, date) VALUES (?, ?) RETURNING id, habit_id, date, created_at, updated_at
+INSERT INTO habit_entries (habit_id, date, value, status) VALUES (?, ?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET value = excluded.value, note = excluded.note, status = excluded.status, deleted_at = NULL, updated_at = datetime('now')
+WHERE habit_entries.deleted_at IS NOT NULL
+RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
 `
 
 type CreateHabitEntryParams struct {
 	HabitID int64
 	Date    string
+	Value   float64
+	Status  string
 }
 
-// Create a new habit entry
+// Create a new habit entry, replacing an entry for the same day in the trash
 func (q *Queries) CreateHabitEntry(ctx context.Context, arg CreateHabitEntryParams) (HabitEntry, error) {
-	row := q.db.QueryRowContext(ctx, createHabitEntry, arg.HabitID, arg.Date)
+	row := q.db.QueryRowContext(ctx, createHabitEntry,
+		arg.HabitID,
+		arg.Date,
+		arg.Value,
+		arg.Status,
+	)
 	var i HabitEntry
 	err := row.Scan(
 		&i.ID,
@@ -28,17 +39,153 @@ func (q *Queries) CreateHabitEntry(ctx context.Context, arg CreateHabitEntryPara
 		&i.Date,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
 	)
 	return i, err
 }
 
-const deleteHabitEntry = `-- name: DeleteHabitEntry :one
-DELETE FROM habit_entries WHERE id = ? RETURNING id, habit_id, date, created_at, updated_at
+const getHabitEntries = `-- name: GetHabitEntries :many
+SELECT id, habit_id, date, created_at, updated_at, value, deleted_at, note, status FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL ORDER BY date
+`
+
+// Retrieve all habit entries for a habit
+func (q *Queries) GetHabitEntries(ctx context.Context, habitID int64) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getHabitEntries, habitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HabitEntry
+	for rows.Next() {
+		var i HabitEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.Date,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHabitEntriesBefore = `-- name: GetHabitEntriesBefore :many
+SELECT id, habit_id, date, created_at, updated_at, value, deleted_at, note, status FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL AND date < ? ORDER BY date DESC LIMIT ?
+`
+
+type GetHabitEntriesBeforeParams struct {
+	HabitID int64
+	Date    string
+	Limit   int64
+}
+
+// Retrieve up to limit of the most recent habit entries for a habit before a date
+func (q *Queries) GetHabitEntriesBefore(ctx context.Context, arg GetHabitEntriesBeforeParams) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getHabitEntriesBefore, arg.HabitID, arg.Date, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HabitEntry
+	for rows.Next() {
+		var i HabitEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.Date,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHabitEntriesBetween = `-- name: GetHabitEntriesBetween :many
+SELECT id, habit_id, date, created_at, updated_at, value, deleted_at, note, status FROM habit_entries WHERE habit_id = ? AND deleted_at IS NULL AND date >= ? AND date <= ? ORDER BY date LIMIT ?
+`
+
+type GetHabitEntriesBetweenParams struct {
+	HabitID  int64
+	FromDate string
+	ToDate   string
+	Limit    int64
+}
+
+// Retrieve up to limit habit entries for a habit between two dates inclusive
+func (q *Queries) GetHabitEntriesBetween(ctx context.Context, arg GetHabitEntriesBetweenParams) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getHabitEntriesBetween,
+		arg.HabitID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HabitEntry
+	for rows.Next() {
+		var i HabitEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.Date,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHabitEntry = `-- name: GetHabitEntry :one
+SELECT id, habit_id, date, created_at, updated_at, value, deleted_at, note, status FROM habit_entries WHERE id = ? AND deleted_at IS NULL
 `
 
-// Delete a habit entry
-func (q *Queries) DeleteHabitEntry(ctx context.Context, id int64) (HabitEntry, error) {
-	row := q.db.QueryRowContext(ctx, deleteHabitEntry, id)
+// Retrieve a habit entry that is not in the trash
+func (q *Queries) GetHabitEntry(ctx context.Context, id int64) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, getHabitEntry, id)
 	var i HabitEntry
 	err := row.Scan(
 		&i.ID,
@@ -46,17 +193,33 @@ func (q *Queries) DeleteHabitEntry(ctx context.Context, id int64) (HabitEntry, e
 		&i.Date,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
 	)
 	return i, err
 }
 
-const getHabitEntries = `-- name: GetHabitEntries :many
-SELECT id, habit_id, date, created_at, updated_at FROM habit_entries WHERE habit_id = ?
+const getHabitEntryOwner = `-- name: GetHabitEntryOwner :one
+SELECT habits.user_id FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habit_entries.id = ? AND habit_entries.deleted_at IS NULL AND habits.deleted_at IS NULL
 `
 
-// Retrieve all habit entries for a habit
-func (q *Queries) GetHabitEntries(ctx context.Context, habitID int64) ([]HabitEntry, error) {
-	rows, err := q.db.QueryContext(ctx, getHabitEntries, habitID)
+// Retrieve the ID of the user a habit entry belongs to
+func (q *Queries) GetHabitEntryOwner(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getHabitEntryOwner, id)
+	var userID int64
+	err := row.Scan(&userID)
+	return userID, err
+}
+
+const getTrashedHabitEntries = `-- name: GetTrashedHabitEntries :many
+SELECT habit_entries.id, habit_entries.habit_id, habit_entries.date, habit_entries.created_at, habit_entries.updated_at, habit_entries.value, habit_entries.deleted_at, habit_entries.note, habit_entries.status FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NOT NULL ORDER BY habit_entries.deleted_at DESC, habit_entries.id
+`
+
+// Retrieve the habit entries a user has moved to the trash, most recently deleted first
+func (q *Queries) GetTrashedHabitEntries(ctx context.Context, userID int64) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashedHabitEntries, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +233,10 @@ func (q *Queries) GetHabitEntries(ctx context.Context, habitID int64) ([]HabitEn
 			&i.Date,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -83,3 +250,322 @@ func (q *Queries) GetHabitEntries(ctx context.Context, habitID int64) ([]HabitEn
 	}
 	return items, nil
 }
+
+const getUserHabitEntriesBetween = `-- name: GetUserHabitEntriesBetween :many
+SELECT habit_entries.id, habit_entries.habit_id, habit_entries.date, habit_entries.created_at, habit_entries.updated_at, habit_entries.value, habit_entries.deleted_at, habit_entries.note, habit_entries.status FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id WHERE habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NULL AND habit_entries.date >= ? AND habit_entries.date <= ? ORDER BY habit_entries.habit_id, habit_entries.date
+`
+
+type GetUserHabitEntriesBetweenParams struct {
+	UserID   int64
+	FromDate string
+	ToDate   string
+}
+
+// Retrieve the habit entries of all habits for a user between two dates inclusive
+func (q *Queries) GetUserHabitEntriesBetween(ctx context.Context, arg GetUserHabitEntriesBetweenParams) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getUserHabitEntriesBetween, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HabitEntry
+	for rows.Next() {
+		var i HabitEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.Date,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importHabitEntry = `-- name: ImportHabitEntry :one
+INSERT INTO habit_entries (habit_id, date, value, note, status) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET value = excluded.value, note = excluded.note, status = excluded.status, deleted_at = NULL, updated_at = datetime('now')
+WHERE habit_entries.deleted_at IS NOT NULL RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type ImportHabitEntryParams struct {
+	HabitID int64
+	Date    string
+	Value   float64
+	Note    string
+	Status  string
+}
+
+// Create a habit entry unless one already exists for the day, replacing an entry in the trash. Returns no row when one already exists
+func (q *Queries) ImportHabitEntry(ctx context.Context, arg ImportHabitEntryParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, importHabitEntry,
+		arg.HabitID,
+		arg.Date,
+		arg.Value,
+		arg.Note,
+		arg.Status,
+	)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
+
+const incrementHabitEntry = `-- name: IncrementHabitEntry :one
+INSERT INTO habit_entries (habit_id, date, value) VALUES (?, ?, ?)
+ON CONFLICT (habit_id, date) DO UPDATE SET
+    value = CASE WHEN habit_entries.deleted_at IS NULL THEN habit_entries.value + excluded.value ELSE excluded.value END,
+    note = CASE WHEN habit_entries.deleted_at IS NULL THEN habit_entries.note ELSE excluded.note END,
+    status = excluded.status,
+    deleted_at = NULL,
+    updated_at = datetime('now')
+RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type IncrementHabitEntryParams struct {
+	HabitID int64
+	Date    string
+	Value   float64
+}
+
+// Add to the value of a habit entry and mark it done, creating it if it does not exist or is in the trash
+func (q *Queries) IncrementHabitEntry(ctx context.Context, arg IncrementHabitEntryParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, incrementHabitEntry, arg.HabitID, arg.Date, arg.Value)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
+
+const purgeHabitEntries = `-- name: PurgeHabitEntries :many
+DELETE FROM habit_entries WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+// Permanently delete habit entries moved to the trash before a time
+func (q *Queries) PurgeHabitEntries(ctx context.Context, deletedAt sql.NullString) ([]HabitEntry, error) {
+	rows, err := q.db.QueryContext(ctx, purgeHabitEntries, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HabitEntry
+	for rows.Next() {
+		var i HabitEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.Date,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Value,
+			&i.DeletedAt,
+			&i.Note,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreHabitEntry = `-- name: RestoreHabitEntry :one
+UPDATE habit_entries SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL AND habit_id IN (SELECT id FROM habits WHERE user_id = ? AND deleted_at IS NULL) RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type RestoreHabitEntryParams struct {
+	UpdatedAt string
+	ID        int64
+	UserID    int64
+}
+
+// Take a user's habit entry out of the trash
+func (q *Queries) RestoreHabitEntry(ctx context.Context, arg RestoreHabitEntryParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, restoreHabitEntry, arg.UpdatedAt, arg.ID, arg.UserID)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
+
+const searchHabitEntryNotes = `-- name: SearchHabitEntryNotes :many
+SELECT habit_entries.id, habit_entries.habit_id, habits.name AS habit_name, habit_entries.date, habit_entries.note FROM habit_entries JOIN habits ON habits.id = habit_entries.habit_id
+WHERE habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NULL
+    AND instr(lower(habit_entries.note), lower(CAST(? AS TEXT))) > 0
+ORDER BY habit_entries.date DESC, habit_entries.id DESC
+LIMIT ?
+`
+
+type SearchHabitEntryNotesParams struct {
+	UserID int64
+	Query  string
+	Limit  int64
+}
+
+type SearchHabitEntryNotesRow struct {
+	ID        int64
+	HabitID   int64
+	HabitName string
+	Date      string
+	Note      string
+}
+
+// Retrieve up to limit of the most recent notes on a user's habit entries containing the query, for when FTS5 is not available
+func (q *Queries) SearchHabitEntryNotes(ctx context.Context, arg SearchHabitEntryNotesParams) ([]SearchHabitEntryNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchHabitEntryNotes, arg.UserID, arg.Query, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchHabitEntryNotesRow
+	for rows.Next() {
+		var i SearchHabitEntryNotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.HabitName,
+			&i.Date,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setHabitEntryNote = `-- name: SetHabitEntryNote :one
+UPDATE habit_entries SET note = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type SetHabitEntryNoteParams struct {
+	Note      string
+	UpdatedAt string
+	ID        int64
+}
+
+// Set the note on a habit entry
+func (q *Queries) SetHabitEntryNote(ctx context.Context, arg SetHabitEntryNoteParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, setHabitEntryNote, arg.Note, arg.UpdatedAt, arg.ID)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
+
+const setHabitEntryStatus = `-- name: SetHabitEntryStatus :one
+UPDATE habit_entries SET status = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type SetHabitEntryStatusParams struct {
+	Status    string
+	UpdatedAt string
+	ID        int64
+}
+
+// Set whether a habit entry was done, skipped or failed
+func (q *Queries) SetHabitEntryStatus(ctx context.Context, arg SetHabitEntryStatusParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, setHabitEntryStatus, arg.Status, arg.UpdatedAt, arg.ID)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
+
+const trashHabitEntry = `-- name: TrashHabitEntry :one
+UPDATE habit_entries SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL RETURNING id, habit_id, date, created_at, updated_at, value, deleted_at, note, status
+`
+
+type TrashHabitEntryParams struct {
+	DeletedAt sql.NullString
+	UpdatedAt string
+	ID        int64
+}
+
+// Move a habit entry to the trash
+func (q *Queries) TrashHabitEntry(ctx context.Context, arg TrashHabitEntryParams) (HabitEntry, error) {
+	row := q.db.QueryRowContext(ctx, trashHabitEntry, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+	var i HabitEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HabitID,
+		&i.Date,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Value,
+		&i.DeletedAt,
+		&i.Note,
+		&i.Status,
+	)
+	return i, err
+}
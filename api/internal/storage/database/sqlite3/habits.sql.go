@@ -10,16 +10,83 @@ import (
 	"database/sql"
 )
 
+const clearHabitCategory = `-- name: ClearHabitCategory :many
+UPDATE habits SET category_id = NULL, updated_at = ?, version = version + 1 WHERE user_id = ? AND category_id = ? RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
+`
+
+type ClearHabitCategoryParams struct {
+	UpdatedAt  string
+	UserID     int64
+	CategoryID sql.NullInt64
+}
+
+// Move a user's habits out of a category
+func (q *Queries) ClearHabitCategory(ctx context.Context, arg ClearHabitCategoryParams) ([]Habit, error) {
+	rows, err := q.db.QueryContext(ctx, clearHabitCategory, arg.UpdatedAt, arg.UserID, arg.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Habit
+	for rows.Next() {
+		var i Habit
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Colour,
+			&i.Index,
+			&i.Active,
+			&i.ScheduleType,
+			&i.ScheduleCount,
+			&i.ScheduleWeekdays,
+			&i.TargetValue,
+			&i.TargetUnit,
+			&i.TargetComparison,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.ScheduleFreezes,
+			&i.Icon,
+			&i.CategoryID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createHabit = `-- name: CreateHabit :one
-INSERT INTO habits (user_id, name, description, colour, ` + "`" + `index` + "`" + `) VALUES (?, ?, ?, ?, ?) RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active
+INSERT INTO habits (user_id, name, description, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, schedule_freezes, target_value, target_unit, target_comparison, archived_at, icon, category_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
 `
 
 type CreateHabitParams struct {
-	UserID      int64
-	Name        string
-	Description sql.NullString
-	Colour      string
-	Index       int64
+	UserID           int64
+	Name             string
+	Description      sql.NullString
+	Colour           string
+	Index            int64
+	Active           bool
+	ScheduleType     string
+	ScheduleCount    int64
+	ScheduleWeekdays int64
+	ScheduleFreezes  sql.NullInt64
+	TargetValue      float64
+	TargetUnit       string
+	TargetComparison string
+	ArchivedAt       sql.NullString
+	Icon             string
+	CategoryID       sql.NullInt64
 }
 
 // Create a new habit
@@ -30,6 +97,17 @@ func (q *Queries) CreateHabit(ctx context.Context, arg CreateHabitParams) (Habit
 		arg.Description,
 		arg.Colour,
 		arg.Index,
+		arg.Active,
+		arg.ScheduleType,
+		arg.ScheduleCount,
+		arg.ScheduleWeekdays,
+		arg.ScheduleFreezes,
+		arg.TargetValue,
+		arg.TargetUnit,
+		arg.TargetComparison,
+		arg.ArchivedAt,
+		arg.Icon,
+		arg.CategoryID,
 	)
 	var i Habit
 	err := row.Scan(
@@ -42,34 +120,24 @@ func (q *Queries) CreateHabit(ctx context.Context, arg CreateHabitParams) (Habit
 		&i.Colour,
 		&i.Index,
 		&i.Active,
-	)
-	return i, err
-}
-
-const deleteHabit = `-- name: DeleteHabit :one
-DELETE FROM habits WHERE id = ? RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active
-`
-
-// Delete a habit by ID
-func (q *Queries) DeleteHabit(ctx context.Context, id int64) (Habit, error) {
-	row := q.db.QueryRowContext(ctx, deleteHabit, id)
-	var i Habit
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Colour,
-		&i.Index,
-		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getHabit = `-- name: GetHabit :one
-SELECT id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active FROM habits WHERE id = ?
+SELECT id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version FROM habits WHERE id = ? AND deleted_at IS NULL
 `
 
 // Retrieve a habit by ID
@@ -86,12 +154,36 @@ func (q *Queries) GetHabit(ctx context.Context, id int64) (Habit, error) {
 		&i.Colour,
 		&i.Index,
 		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getHabitOwner = `-- name: GetHabitOwner :one
+SELECT user_id FROM habits WHERE id = ?
+`
+
+// Retrieve the ID of the user a habit belongs to, including habits in the trash
+func (q *Queries) GetHabitOwner(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getHabitOwner, id)
+	var userID int64
+	err := row.Scan(&userID)
+	return userID, err
+}
+
 const getHabits = `-- name: GetHabits :many
-SELECT id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active FROM habits WHERE user_id = ?
+SELECT id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version FROM habits WHERE user_id = ? AND deleted_at IS NULL ORDER BY id
 `
 
 // Retrieve all habits for a user
@@ -114,6 +206,68 @@ func (q *Queries) GetHabits(ctx context.Context, userID int64) ([]Habit, error)
 			&i.Colour,
 			&i.Index,
 			&i.Active,
+			&i.ScheduleType,
+			&i.ScheduleCount,
+			&i.ScheduleWeekdays,
+			&i.TargetValue,
+			&i.TargetUnit,
+			&i.TargetComparison,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.ScheduleFreezes,
+			&i.Icon,
+			&i.CategoryID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedHabits = `-- name: GetTrashedHabits :many
+SELECT id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version FROM habits WHERE user_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id
+`
+
+// Retrieve the habits a user has moved to the trash, most recently deleted first
+func (q *Queries) GetTrashedHabits(ctx context.Context, userID int64) ([]Habit, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashedHabits, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Habit
+	for rows.Next() {
+		var i Habit
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Colour,
+			&i.Index,
+			&i.Active,
+			&i.ScheduleType,
+			&i.ScheduleCount,
+			&i.ScheduleWeekdays,
+			&i.TargetValue,
+			&i.TargetUnit,
+			&i.TargetComparison,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.ScheduleFreezes,
+			&i.Icon,
+			&i.CategoryID,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -128,30 +282,263 @@ func (q *Queries) GetHabits(ctx context.Context, userID int64) ([]Habit, error)
 	return items, nil
 }
 
+const purgeHabits = `-- name: PurgeHabits :many
+DELETE FROM habits WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
+`
+
+// Permanently delete habits moved to the trash before a time, along with their entries
+func (q *Queries) PurgeHabits(ctx context.Context, deletedAt sql.NullString) ([]Habit, error) {
+	rows, err := q.db.QueryContext(ctx, purgeHabits, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Habit
+	for rows.Next() {
+		var i Habit
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Colour,
+			&i.Index,
+			&i.Active,
+			&i.ScheduleType,
+			&i.ScheduleCount,
+			&i.ScheduleWeekdays,
+			&i.TargetValue,
+			&i.TargetUnit,
+			&i.TargetComparison,
+			&i.ArchivedAt,
+			&i.DeletedAt,
+			&i.ScheduleFreezes,
+			&i.Icon,
+			&i.CategoryID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreHabit = `-- name: RestoreHabit :one
+UPDATE habits SET deleted_at = NULL, updated_at = ?, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
+`
+
+type RestoreHabitParams struct {
+	UpdatedAt string
+	ID        int64
+	UserID    int64
+}
+
+// Take a user's habit out of the trash
+func (q *Queries) RestoreHabit(ctx context.Context, arg RestoreHabitParams) (Habit, error) {
+	row := q.db.QueryRowContext(ctx, restoreHabit, arg.UpdatedAt, arg.ID, arg.UserID)
+	var i Habit
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Colour,
+		&i.Index,
+		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
+	)
+	return i, err
+}
+
+const setHabitArchivedAt = `-- name: SetHabitArchivedAt :one
+UPDATE habits SET archived_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
+`
+
+type SetHabitArchivedAtParams struct {
+	ArchivedAt sql.NullString
+	UpdatedAt  string
+	ID         int64
+}
+
+// Archive a habit, or restore it when archived_at is null
+func (q *Queries) SetHabitArchivedAt(ctx context.Context, arg SetHabitArchivedAtParams) (Habit, error) {
+	row := q.db.QueryRowContext(ctx, setHabitArchivedAt, arg.ArchivedAt, arg.UpdatedAt, arg.ID)
+	var i Habit
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Colour,
+		&i.Index,
+		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
+	)
+	return i, err
+}
+
+const setHabitIndex = `-- name: SetHabitIndex :execrows
+UPDATE habits SET ` + "`" + `index` + "`" + ` = ?, updated_at = ?, version = version + 1 WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type SetHabitIndexParams struct {
+	Index     int64
+	UpdatedAt string
+	ID        int64
+	UserID    int64
+}
+
+// Move a user's habit to a new position
+func (q *Queries) SetHabitIndex(ctx context.Context, arg SetHabitIndexParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setHabitIndex,
+		arg.Index,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashHabit = `-- name: TrashHabit :one
+UPDATE habits SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
+`
+
+type TrashHabitParams struct {
+	DeletedAt sql.NullString
+	UpdatedAt string
+	ID        int64
+}
+
+// Move a habit and its entries to the trash
+func (q *Queries) TrashHabit(ctx context.Context, arg TrashHabitParams) (Habit, error) {
+	row := q.db.QueryRowContext(ctx, trashHabit, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+	var i Habit
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Colour,
+		&i.Index,
+		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
+	)
+	return i, err
+}
+
 const updateHabit = `-- name: UpdateHabit :one
-UPDATE habits SET name = ?, description = ?, colour = ?, ` + "`" + `index` + "`" + ` = ?, active = ?, updated_at = ? WHERE id = ? RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active
+UPDATE habits SET
+    name = ?,
+    description = NULLIF(COALESCE(?, description), ''),
+    icon = COALESCE(?, icon),
+    category_id = NULLIF(COALESCE(?, category_id), 0),
+    colour = ?,
+    ` + "`" + `index` + "`" + ` = ?,
+    active = ?,
+    schedule_type = COALESCE(?, schedule_type),
+    schedule_count = COALESCE(?, schedule_count),
+    schedule_weekdays = COALESCE(?, schedule_weekdays),
+    schedule_freezes = CASE WHEN CAST(? AS TEXT) IS NULL THEN schedule_freezes ELSE CAST(? AS INTEGER) END,
+    target_value = COALESCE(?, target_value),
+    target_unit = COALESCE(?, target_unit),
+    target_comparison = COALESCE(?, target_comparison),
+    updated_at = ?,
+    version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL RETURNING id, user_id, name, description, created_at, updated_at, colour, ` + "`" + `index` + "`" + `, active, schedule_type, schedule_count, schedule_weekdays, target_value, target_unit, target_comparison, archived_at, deleted_at, schedule_freezes, icon, category_id, version
 `
 
 type UpdateHabitParams struct {
-	Name        string
-	Description sql.NullString
-	Colour      string
-	Index       int64
-	Active      bool
-	UpdatedAt   string
-	ID          int64
+	Name             string
+	Description      sql.NullString
+	Icon             sql.NullString
+	CategoryID       sql.NullInt64
+	Colour           string
+	Index            int64
+	Active           bool
+	ScheduleType     sql.NullString
+	ScheduleCount    sql.NullInt64
+	ScheduleWeekdays sql.NullInt64
+	ScheduleFreezes  sql.NullInt64
+	TargetValue      sql.NullFloat64
+	TargetUnit       sql.NullString
+	TargetComparison sql.NullString
+	UpdatedAt        string
+	ID               int64
+	Version          int64
 }
 
-// Update a habit by ID
+// Update a habit by ID if it is still at version, keeping the existing description, icon, category, schedule and target when none is given
 func (q *Queries) UpdateHabit(ctx context.Context, arg UpdateHabitParams) (Habit, error) {
 	row := q.db.QueryRowContext(ctx, updateHabit,
 		arg.Name,
 		arg.Description,
+		arg.Icon,
+		arg.CategoryID,
 		arg.Colour,
 		arg.Index,
 		arg.Active,
+		arg.ScheduleType,
+		arg.ScheduleCount,
+		arg.ScheduleWeekdays,
+		arg.ScheduleType,
+		arg.ScheduleFreezes,
+		arg.TargetValue,
+		arg.TargetUnit,
+		arg.TargetComparison,
 		arg.UpdatedAt,
 		arg.ID,
+		arg.Version,
 	)
 	var i Habit
 	err := row.Scan(
@@ -164,6 +551,18 @@ func (q *Queries) UpdateHabit(ctx context.Context, arg UpdateHabitParams) (Habit
 		&i.Colour,
 		&i.Index,
 		&i.Active,
+		&i.ScheduleType,
+		&i.ScheduleCount,
+		&i.ScheduleWeekdays,
+		&i.TargetValue,
+		&i.TargetUnit,
+		&i.TargetComparison,
+		&i.ArchivedAt,
+		&i.DeletedAt,
+		&i.ScheduleFreezes,
+		&i.Icon,
+		&i.CategoryID,
+		&i.Version,
 	)
 	return i, err
 }
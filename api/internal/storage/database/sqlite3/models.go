@@ -8,16 +8,60 @@ import (
 	"database/sql"
 )
 
+type ApiToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	TokenHash  string
+	Scope      string
+	LastUsedAt sql.NullString
+	CreatedAt  string
+}
+
+type AuditEvent struct {
+	ID         int64
+	UserID     int64
+	ActorID    sql.NullInt64
+	EntityType string
+	EntityID   int64
+	Action     string
+	DataBefore sql.NullString
+	DataAfter  sql.NullString
+	RequestID  sql.NullString
+	CreatedAt  string
+}
+
+type Category struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	Colour    string
+	CreatedAt string
+	UpdatedAt string
+}
+
 type Habit struct {
-	ID          int64
-	UserID      int64
-	Name        string
-	Description sql.NullString
-	CreatedAt   string
-	UpdatedAt   string
-	Colour      string
-	Index       int64
-	Active      bool
+	ID               int64
+	UserID           int64
+	Name             string
+	Description      sql.NullString
+	CreatedAt        string
+	UpdatedAt        string
+	Colour           string
+	Index            int64
+	Active           bool
+	ScheduleType     string
+	ScheduleCount    int64
+	ScheduleWeekdays int64
+	TargetValue      float64
+	TargetUnit       string
+	TargetComparison string
+	ArchivedAt       sql.NullString
+	DeletedAt        sql.NullString
+	ScheduleFreezes  sql.NullInt64
+	Icon             string
+	CategoryID       sql.NullInt64
+	Version          int64
 }
 
 type HabitEntry struct {
@@ -26,11 +70,50 @@ type HabitEntry struct {
 	Date      string
 	CreatedAt string
 	UpdatedAt string
+	Value     float64
+	DeletedAt sql.NullString
+	Note      string
+	Status    string
 }
 
-type User struct {
+type HabitTag struct {
+	HabitID   int64
+	TagID     int64
+	CreatedAt string
+}
+
+type JournalEntry struct {
+	ID        int64
+	UserID    int64
+	Date      string
+	Text      string
+	CreatedAt string
+	UpdatedAt string
+}
+
+type Session struct {
 	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt string
+	CreatedAt string
+}
+
+type Tag struct {
+	ID        int64
+	UserID    int64
 	Name      string
 	CreatedAt string
 	UpdatedAt string
 }
+
+type User struct {
+	ID           int64
+	Name         string
+	CreatedAt    string
+	UpdatedAt    string
+	PasswordHash sql.NullString
+	Admin        bool
+	Timezone     string
+	DayStartHour int64
+}
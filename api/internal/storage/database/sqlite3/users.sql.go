@@ -7,10 +7,11 @@ package sqlite3Storage
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (name) VALUES (?) RETURNING id, name, created_at, updated_at
+INSERT INTO users (name) VALUES (?) RETURNING id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour
 `
 
 // Create a new user
@@ -22,12 +23,55 @@ func (q *Queries) CreateUser(ctx context.Context, name string) (User, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
 	)
 	return i, err
 }
 
+const createUserWithPassword = `-- name: CreateUserWithPassword :one
+INSERT INTO users (name, password_hash) VALUES (?, ?) RETURNING id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour
+`
+
+type CreateUserWithPasswordParams struct {
+	Name         string
+	PasswordHash sql.NullString
+}
+
+// Create a new user that can sign in
+func (q *Queries) CreateUserWithPassword(ctx context.Context, arg CreateUserWithPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUserWithPassword, arg.Name, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = ?
+`
+
+// Delete a user along with all of their habits, sessions and API tokens
+func (q *Queries) DeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, created_at, updated_at FROM users WHERE id = ?
+SELECT id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour FROM users WHERE id = ?
 `
 
 // Retrieve a user by ID
@@ -39,15 +83,40 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (User, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
+	)
+	return i, err
+}
+
+const getUserByName = `-- name: GetUserByName :one
+SELECT id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour FROM users WHERE name = ? ORDER BY id LIMIT 1
+`
+
+// Retrieve the oldest user with a name
+func (q *Queries) GetUserByName(ctx context.Context, name string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByName, name)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, name, created_at, updated_at FROM users
+SELECT id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour FROM users ORDER BY id
 `
 
-// Retrieve all habits
+// Retrieve all users
 func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	rows, err := q.db.QueryContext(ctx, getUsers)
 	if err != nil {
@@ -62,6 +131,10 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PasswordHash,
+			&i.Admin,
+			&i.Timezone,
+			&i.DayStartHour,
 		); err != nil {
 			return nil, err
 		}
@@ -75,3 +148,82 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	}
 	return items, nil
 }
+
+const setUserAdmin = `-- name: SetUserAdmin :execrows
+UPDATE users SET admin = ?, updated_at = ? WHERE id = ?
+`
+
+type SetUserAdminParams struct {
+	Admin     bool
+	UpdatedAt string
+	ID        int64
+}
+
+// Grant or revoke a user's access to administration endpoints
+func (q *Queries) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserAdmin, arg.Admin, arg.UpdatedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ? RETURNING id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash sql.NullString
+	UpdatedAt    string
+	ID           int64
+}
+
+// Set the password of a user
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.PasswordHash, arg.UpdatedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
+	)
+	return i, err
+}
+
+const updateUserSettings = `-- name: UpdateUserSettings :one
+UPDATE users SET timezone = ?, day_start_hour = ?, updated_at = ? WHERE id = ? RETURNING id, name, created_at, updated_at, password_hash, admin, timezone, day_start_hour
+`
+
+type UpdateUserSettingsParams struct {
+	Timezone     string
+	DayStartHour int64
+	UpdatedAt    string
+	ID           int64
+}
+
+// Set the timezone and day start hour of a user
+func (q *Queries) UpdateUserSettings(ctx context.Context, arg UpdateUserSettingsParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSettings,
+		arg.Timezone,
+		arg.DayStartHour,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PasswordHash,
+		&i.Admin,
+		&i.Timezone,
+		&i.DayStartHour,
+	)
+	return i, err
+}
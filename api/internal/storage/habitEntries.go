@@ -2,29 +2,120 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+var (
+	ErrNegativeValue = errors.New("the day's value must not be negative")
+	ErrEntryExists   = errors.New("the habit already has an entry for the day")
 )
 
 type HabitEntry struct {
 	Date    time.Time `json:"date"`
+	Note    string    `json:"note,omitempty"`
+	Status  string    `json:"status"`
 	Id      int64     `json:"id"`
 	HabitId int64     `json:"habitId"`
+	Value   float64   `json:"value"`
 }
 
-func (s Sqlite) CreateHabitEntry(habitId int64, date time.Time) (HabitEntry, error) {
-	ctx := context.Background()
-	habitEntry, err := s.Queries.CreateHabitEntry(ctx, sqlite3Storage.CreateHabitEntryParams{
-		HabitID: habitId,
-		Date:    date.Format(time.DateOnly),
+// CreateHabitEntry creates the entry for the given day with a status of done,
+// skipped or failed, recording the change in the audit log. Days that already
+// have an entry are refused with ErrEntryExists.
+func (s Database) CreateHabitEntry(ctx context.Context, habitId int64, date time.Time, value float64, status string) (HabitEntry, error) {
+	return s.changeHabitEntry(ctx, habitId, date, func(queries repository.Querier) (repository.HabitEntry, error) {
+		entry, err := queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{
+			HabitID: habitId,
+			Date:    date.Format(time.DateOnly),
+			Value:   value,
+			Status:  status,
+		})
+		// Only an entry in the trash is replaced, a live one leaves no row
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.HabitEntry{}, ErrEntryExists
+		}
+
+		return entry, err
+	})
+}
+
+// IncrementHabitEntry adds value to the entry for the given day and marks it
+// done, creating it if needed. Decrements that would take the day's value below
+// zero are refused with ErrNegativeValue.
+func (s Database) IncrementHabitEntry(ctx context.Context, habitId int64, date time.Time, value float64) (HabitEntry, error) {
+	return s.changeHabitEntry(ctx, habitId, date, func(queries repository.Querier) (repository.HabitEntry, error) {
+		entry, err := queries.IncrementHabitEntry(ctx, repository.IncrementHabitEntryParams{
+			HabitID: habitId,
+			Date:    date.Format(time.DateOnly),
+			Value:   value,
+		})
+		// Failing rolls the increment back, leaving the day's value as it was
+		if err == nil && entry.Value < 0 {
+			return repository.HabitEntry{}, ErrNegativeValue
+		}
+
+		return entry, err
 	})
+}
+
+// changeHabitEntry runs change on the habit's entry for the day and records it
+// in the audit log as a create, or an update if there already was an entry.
+func (s Database) changeHabitEntry(ctx context.Context, habitId int64, date time.Time, change func(queries repository.Querier) (repository.HabitEntry, error)) (HabitEntry, error) {
+	var habitEntry HabitEntry
+	err := s.Transaction(ctx, func(queries repository.Querier) error {
+		habit, err := queries.GetHabit(ctx, habitId)
+		if err != nil {
+			return err
+		}
+
+		day := date.Format(time.DateOnly)
+		existing, err := queries.GetHabitEntriesBetween(ctx, repository.GetHabitEntriesBetweenParams{HabitID: habitId, FromDate: day, ToDate: day, Limit: 1})
+		if err != nil {
+			return err
+		}
+
+		changed, err := change(queries)
+		if err != nil {
+			return err
+		}
+
+		habitEntry, err = NewHabitEntryFromStorage(changed)
+		if err != nil {
+			return err
+		}
+
+		audit := auditService.Change{
+			After:      habitEntry,
+			EntityType: auditService.EntityHabitEntry,
+			Action:     auditService.ActionCreate,
+			UserId:     habit.UserID,
+			EntityId:   habitEntry.Id,
+		}
+		if len(existing) > 0 {
+			audit.Action = auditService.ActionUpdate
+			audit.Before, err = NewHabitEntryFromStorage(existing[0])
+			if err != nil {
+				return err
+			}
+		}
 
+		return auditService.Record(ctx, queries, audit)
+	})
 	if err != nil {
 		return HabitEntry{}, err
 	}
 
-	date, err = time.Parse(time.DateOnly, habitEntry.Date)
+	return habitEntry, nil
+}
+
+// NewHabitEntryFromStorage converts a stored habit entry.
+func NewHabitEntryFromStorage(habitEntry repository.HabitEntry) (HabitEntry, error) {
+	date, err := time.Parse(time.DateOnly, habitEntry.Date)
 	if err != nil {
 		return HabitEntry{}, err
 	}
@@ -32,30 +123,130 @@ func (s Sqlite) CreateHabitEntry(habitId int64, date time.Time) (HabitEntry, err
 	return HabitEntry{
 		Id:      habitEntry.ID,
 		Date:    date,
+		Note:    habitEntry.Note,
+		Status:  habitEntry.Status,
 		HabitId: habitEntry.HabitID,
+		Value:   habitEntry.Value,
 	}, nil
 }
 
-func (s Sqlite) DeleteHabitEntry(id int64) (HabitEntry, error) {
-	ctx := context.Background()
-	habitEntry, err := s.Queries.DeleteHabitEntry(ctx, id)
+// DeleteHabitEntry moves a habit entry to the trash, recording the change in
+// the audit log.
+func (s Database) DeleteHabitEntry(ctx context.Context, id int64) (HabitEntry, error) {
+	var habitEntry HabitEntry
+	err := s.Transaction(ctx, func(queries repository.Querier) error {
+		userId, err := queries.GetHabitEntryOwner(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		deletedAt := time.Now().UTC().Format(time.DateTime)
+		deleted, err := queries.TrashHabitEntry(ctx, repository.TrashHabitEntryParams{
+			DeletedAt: sql.NullString{String: deletedAt, Valid: true},
+			UpdatedAt: deletedAt,
+			ID:        id,
+		})
+		if err != nil {
+			return err
+		}
+
+		habitEntry, err = NewHabitEntryFromStorage(deleted)
+		if err != nil {
+			return err
+		}
+
+		return auditService.Record(ctx, queries, auditService.Change{
+			Before:     habitEntry,
+			EntityType: auditService.EntityHabitEntry,
+			Action:     auditService.ActionDelete,
+			UserId:     userId,
+			EntityId:   id,
+		})
+	})
 	if err != nil {
 		return HabitEntry{}, err
 	}
 
-	date, err := time.Parse(time.DateOnly, habitEntry.Date)
+	return habitEntry, nil
+}
+
+// SetHabitEntryNote sets the note on a habit entry, recording the change in
+// the audit log.
+func (s Database) SetHabitEntryNote(ctx context.Context, id int64, note string) (HabitEntry, error) {
+	return s.updateHabitEntry(ctx, id, func(queries repository.Querier) (repository.HabitEntry, error) {
+		return queries.SetHabitEntryNote(ctx, repository.SetHabitEntryNoteParams{
+			Note:      note,
+			UpdatedAt: time.Now().UTC().Format(time.DateTime),
+			ID:        id,
+		})
+	})
+}
+
+// SetHabitEntryStatus marks a habit entry as done, skipped or failed,
+// recording the change in the audit log.
+func (s Database) SetHabitEntryStatus(ctx context.Context, id int64, status string) (HabitEntry, error) {
+	return s.updateHabitEntry(ctx, id, func(queries repository.Querier) (repository.HabitEntry, error) {
+		return queries.SetHabitEntryStatus(ctx, repository.SetHabitEntryStatusParams{
+			Status:    status,
+			UpdatedAt: time.Now().UTC().Format(time.DateTime),
+			ID:        id,
+		})
+	})
+}
+
+// updateHabitEntry runs update on an existing habit entry and records it in
+// the audit log.
+func (s Database) updateHabitEntry(ctx context.Context, id int64, update func(queries repository.Querier) (repository.HabitEntry, error)) (HabitEntry, error) {
+	var habitEntry HabitEntry
+	err := s.Transaction(ctx, func(queries repository.Querier) error {
+		userId, err := queries.GetHabitEntryOwner(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		existing, err := queries.GetHabitEntry(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err := update(queries)
+		if err != nil {
+			return err
+		}
+
+		before, err := NewHabitEntryFromStorage(existing)
+		if err != nil {
+			return err
+		}
+
+		habitEntry, err = NewHabitEntryFromStorage(updated)
+		if err != nil {
+			return err
+		}
+
+		return auditService.Record(ctx, queries, auditService.Change{
+			Before:     before,
+			After:      habitEntry,
+			EntityType: auditService.EntityHabitEntry,
+			Action:     auditService.ActionUpdate,
+			UserId:     userId,
+			EntityId:   id,
+		})
+	})
 	if err != nil {
 		return HabitEntry{}, err
 	}
 
-	return HabitEntry{
-		Id:      habitEntry.ID,
-		Date:    date,
-		HabitId: habitEntry.HabitID,
-	}, nil
+	return habitEntry, nil
 }
 
-func (s Sqlite) GetHabitEntries(habitId int64) ([]HabitEntry, error) {
+// GetHabitEntryUserId returns the ID of the user the habit entry belongs to.
+func (s Database) GetHabitEntryUserId(id int64) (int64, error) {
+	ctx := context.Background()
+	return s.Queries.GetHabitEntryOwner(ctx, id)
+}
+
+func (s Database) GetHabitEntries(habitId int64) ([]HabitEntry, error) {
 	ctx := context.Background()
 	habitEntries, err := s.Queries.GetHabitEntries(ctx, habitId)
 	if err != nil {
@@ -64,16 +255,10 @@ func (s Sqlite) GetHabitEntries(habitId int64) ([]HabitEntry, error) {
 
 	entries := make([]HabitEntry, len(habitEntries))
 	for i, entry := range habitEntries {
-		date, err := time.Parse(time.DateOnly, entry.Date)
+		entries[i], err = NewHabitEntryFromStorage(entry)
 		if err != nil {
 			return nil, err
 		}
-
-		entries[i] = HabitEntry{
-			Id:      entry.ID,
-			Date:    date,
-			HabitId: entry.HabitID,
-		}
 	}
 
 	return entries, nil
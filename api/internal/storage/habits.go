@@ -2,9 +2,10 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
-	sqlite3Storage "github.com/ReidMason/habit-tracker/internal/storage/database/sqlite3"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -43,7 +44,7 @@ func NewBasicHabitEntry(date time.Time, combo int, id int64) BasicHabitEntry {
 	}
 }
 
-func (s Sqlite) GetHabit(id int64) (Habit, error) {
+func (s Database) GetHabit(id int64) (Habit, error) {
 	ctx := context.Background()
 	habit, err := s.Queries.GetHabit(ctx, id)
 	if err != nil {
@@ -63,30 +64,53 @@ func (s Sqlite) GetHabit(id int64) (Habit, error) {
 	return NewHabit(habit.ID, habit.Name, habit.Colour, habit.Index, basicEntries, habit.Active), nil
 }
 
-func (s Sqlite) DeleteHabit(id int64) error {
+// GetHabitUserId returns the ID of the user the habit belongs to.
+func (s Database) GetHabitUserId(id int64) (int64, error) {
 	ctx := context.Background()
-	_, err := s.Queries.DeleteHabit(ctx, id)
+	habit, err := s.Queries.GetHabit(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return habit.UserID, nil
+}
+
+// DeleteHabit moves a habit to the trash.
+func (s Database) DeleteHabit(id int64) error {
+	ctx := context.Background()
+	deletedAt := time.Now().UTC().Format(time.DateTime)
+	_, err := s.Queries.TrashHabit(ctx, repository.TrashHabitParams{
+		DeletedAt: sql.NullString{String: deletedAt, Valid: true},
+		UpdatedAt: deletedAt,
+		ID:        id,
+	})
 	return err
 }
 
-func (s Sqlite) UpdateHabit(id int64, name string, colour string, index int64, active bool) error {
+func (s Database) UpdateHabit(id int64, name string, colour string, index int64, active bool) error {
 	ctx := context.Background()
+	habit, err := s.Queries.GetHabit(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	caser := cases.Title(language.English)
-	_, err := s.Queries.UpdateHabit(ctx, sqlite3Storage.UpdateHabitParams{
+	_, err = s.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{
 		ID:        id,
 		Name:      caser.String(name),
 		Colour:    colour,
 		Index:     index,
 		Active:    active,
-		UpdatedAt: time.Now().Format(time.DateTime),
+		UpdatedAt: time.Now().UTC().Format(time.DateTime),
+		Version:   habit.Version,
 	})
 	return err
 }
 
-func (s Sqlite) CreateHabit(userId int64, name string, colour string, index int64) (Habit, error) {
+func (s Database) CreateHabit(userId int64, name string, colour string, index int64) (Habit, error) {
 	ctx := context.Background()
 	caser := cases.Title(language.English)
-	habit, err := s.Queries.CreateHabit(ctx, sqlite3Storage.CreateHabitParams{
+	habit, err := s.Queries.CreateHabit(ctx, repository.CreateHabitParams{
 		UserID: userId,
 		Name:   caser.String(name),
 		Colour: colour,
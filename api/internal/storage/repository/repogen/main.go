@@ -0,0 +1,199 @@
+// Repogen writes the repository package from the sqlc generated SQLite
+// queries: the models and params, the Querier interface and an adapter for
+// each backend. Run it with go generate after regenerating the queries.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const header = "// Code generated by repogen. DO NOT EDIT.\n\n"
+
+type backend struct {
+	name    string
+	alias   string
+	path    string
+	adapter string
+}
+
+var backends = []backend{
+	{name: "sqlite", alias: "sqlite3Storage", path: "database/sqlite3", adapter: "sqliteQueries"},
+	{name: "postgres", alias: "postgresStorage", path: "database/postgres", adapter: "postgresQueries"},
+}
+
+const modulePath = "github.com/ReidMason/habit-tracker/internal/storage/"
+
+type method struct {
+	decl *ast.FuncDecl
+	name string
+}
+
+func main() {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, filepath.Join("..", backends[0].path))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	types := make([]*ast.TypeSpec, 0)
+	structs := make(map[string]bool)
+	methods := make([]method, 0)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					spec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if _, ok := spec.Type.(*ast.StructType); !ok || spec.Name.Name == "Queries" {
+						continue
+					}
+					types = append(types, spec)
+					structs[spec.Name.Name] = true
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || decl.Name.Name == "WithTx" {
+					continue
+				}
+				methods = append(methods, method{decl: decl, name: decl.Name.Name})
+			}
+		}
+	}
+	slices.SortFunc(methods, func(a, b method) int { return strings.Compare(a.name, b.name) })
+
+	var models bytes.Buffer
+	models.WriteString(header + "package repository\n\nimport \"database/sql\"\n\n")
+	for _, spec := range types {
+		fmt.Fprintf(&models, "type %s %s\n\n", spec.Name.Name, node(fset, spec.Type))
+	}
+	write("models.go", models.Bytes())
+
+	var querier bytes.Buffer
+	querier.WriteString(header + "package repository\n\nimport (\n\"context\"\n\"database/sql\"\n)\n\n")
+	querier.WriteString("// Querier is implemented by the queries of every storage backend.\n")
+	querier.WriteString("type Querier interface {\n")
+	querier.WriteString("WithTx(tx *sql.Tx) Querier\n")
+	for _, m := range methods {
+		fmt.Fprintf(&querier, "%s%s\n", m.name, strings.TrimPrefix(node(fset, m.decl.Type), "func"))
+	}
+	querier.WriteString("}\n")
+	write("querier.go", querier.Bytes())
+
+	for _, b := range backends {
+		write(b.name+".go", adapter(fset, b, methods, structs))
+	}
+}
+
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	files := make([]*ast.File, 0, len(paths))
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// adapter writes a type wrapping a backend's queries that implements
+// Querier by converting the backend's structs to and from the repository's.
+func adapter(fset *token.FileSet, b backend, methods []method, structs map[string]bool) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(header + "package repository\n\n")
+	fmt.Fprintf(&buf, "import (\n\"context\"\n\"database/sql\"\n\n%s %q\n)\n\n", b.alias, modulePath+b.path)
+	fmt.Fprintf(&buf, "type %s struct {\nqueries *%s.Queries\n}\n\n", b.adapter, b.alias)
+	fmt.Fprintf(&buf, "// New%s returns a Querier running queries on db.\n", upperFirst(b.adapter))
+	fmt.Fprintf(&buf, "func New%s(db %s.DBTX) Querier {\nreturn %s{queries: %s.New(db)}\n}\n\n", upperFirst(b.adapter), b.alias, b.adapter, b.alias)
+	fmt.Fprintf(&buf, "// WithTx returns a Querier running queries in tx.\n")
+	fmt.Fprintf(&buf, "func (q %s) WithTx(tx *sql.Tx) Querier {\nreturn %s{queries: q.queries.WithTx(tx)}\n}\n", b.adapter, b.adapter)
+
+	for _, m := range methods {
+		args := make([]string, 0)
+		for _, field := range m.decl.Type.Params.List {
+			for _, name := range field.Names {
+				if ident, ok := field.Type.(*ast.Ident); ok && structs[ident.Name] {
+					args = append(args, fmt.Sprintf("%s.%s(%s)", b.alias, ident.Name, name.Name))
+					continue
+				}
+				args = append(args, name.Name)
+			}
+		}
+		call := fmt.Sprintf("q.queries.%s(%s)", m.name, strings.Join(args, ", "))
+
+		fmt.Fprintf(&buf, "\nfunc (q %s) %s%s {\n", b.adapter, m.name, strings.TrimPrefix(node(fset, m.decl.Type), "func"))
+		results := m.decl.Type.Results.List
+		result := results[0].Type
+		switch {
+		case len(results) == 1:
+			fmt.Fprintf(&buf, "return %s\n", call)
+		case isStruct(result, structs):
+			fmt.Fprintf(&buf, "item, err := %s\nreturn %s(item), err\n", call, node(fset, result))
+		case isStructSlice(result, structs):
+			elem := node(fset, result.(*ast.ArrayType).Elt)
+			fmt.Fprintf(&buf, "items, err := %s\nif err != nil {\nreturn nil, err\n}\n\n", call)
+			fmt.Fprintf(&buf, "converted := make([]%s, len(items))\nfor i, item := range items {\nconverted[i] = %s(item)\n}\n\nreturn converted, nil\n", elem, elem)
+		default:
+			fmt.Fprintf(&buf, "return %s\n", call)
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes()
+}
+
+func isStruct(expr ast.Expr, structs map[string]bool) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && structs[ident.Name]
+}
+
+func isStructSlice(expr ast.Expr, structs map[string]bool) bool {
+	array, ok := expr.(*ast.ArrayType)
+	return ok && isStruct(array.Elt, structs)
+}
+
+func node(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	err := printer.Fprint(&buf, fset, n)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func upperFirst(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func write(path string, src []byte) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("failed to format %s: %v\n%s", path, err, src)
+	}
+
+	err = os.WriteFile(path, formatted, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,6 @@
+// Package repository is the storage API shared by every database backend.
+// The models, params and Querier are generated from the sqlc queries, which
+// are written for each backend with the same names and columns.
+package repository
+
+//go:generate go run ./repogen
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+)
+
+// The FTS5 indexes of habit entry notes and journal entries are kept up to
+// date by triggers. They are created outside of the migrations because FTS5
+// is only compiled into SQLite with the sqlite_fts5 build tag, without it
+// search falls back to substring matching.
+var searchTables = []string{"habit_entry_notes_search", "journal_entries_search"}
+
+var searchTriggers = []string{
+	"habit_entry_notes_search_insert",
+	"habit_entry_notes_search_delete",
+	"habit_entry_notes_search_update",
+	"journal_entries_search_insert",
+	"journal_entries_search_delete",
+	"journal_entries_search_update",
+}
+
+var searchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS habit_entry_notes_search USING fts5(note, content='habit_entries', content_rowid='id', tokenize='porter unicode61')`,
+	`CREATE TRIGGER IF NOT EXISTS habit_entry_notes_search_insert AFTER INSERT ON habit_entries BEGIN
+		INSERT INTO habit_entry_notes_search(rowid, note) VALUES (new.id, new.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS habit_entry_notes_search_delete AFTER DELETE ON habit_entries BEGIN
+		INSERT INTO habit_entry_notes_search(habit_entry_notes_search, rowid, note) VALUES ('delete', old.id, old.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS habit_entry_notes_search_update AFTER UPDATE OF note ON habit_entries BEGIN
+		INSERT INTO habit_entry_notes_search(habit_entry_notes_search, rowid, note) VALUES ('delete', old.id, old.note);
+		INSERT INTO habit_entry_notes_search(rowid, note) VALUES (new.id, new.note);
+	END`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS journal_entries_search USING fts5(text, content='journal_entries', content_rowid='id', tokenize='porter unicode61')`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_search_insert AFTER INSERT ON journal_entries BEGIN
+		INSERT INTO journal_entries_search(rowid, text) VALUES (new.id, new.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_search_delete AFTER DELETE ON journal_entries BEGIN
+		INSERT INTO journal_entries_search(journal_entries_search, rowid, text) VALUES ('delete', old.id, old.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_search_update AFTER UPDATE OF text ON journal_entries BEGIN
+		INSERT INTO journal_entries_search(journal_entries_search, rowid, text) VALUES ('delete', old.id, old.text);
+		INSERT INTO journal_entries_search(rowid, text) VALUES (new.id, new.text);
+	END`,
+}
+
+const searchHabitEntryNotes = `SELECT habit_entries.id, habit_entries.habit_id, habits.name, habit_entries.date, habit_entries.note
+FROM habit_entry_notes_search
+JOIN habit_entries ON habit_entries.id = habit_entry_notes_search.rowid
+JOIN habits ON habits.id = habit_entries.habit_id
+WHERE habit_entry_notes_search MATCH ? AND habits.user_id = ? AND habits.deleted_at IS NULL AND habit_entries.deleted_at IS NULL
+ORDER BY habit_entries.date DESC, habit_entries.id DESC
+LIMIT ?`
+
+const searchJournalEntries = `SELECT journal_entries.id, journal_entries.user_id, journal_entries.date, journal_entries.text, journal_entries.created_at, journal_entries.updated_at
+FROM journal_entries_search
+JOIN journal_entries ON journal_entries.id = journal_entries_search.rowid
+WHERE journal_entries_search MATCH ? AND journal_entries.user_id = ?
+ORDER BY journal_entries.date DESC
+LIMIT ?`
+
+// fullTextSearch reports whether searches use the FTS5 indexes.
+func (s Database) fullTextSearch() bool {
+	return s.dialect == "sqlite3" && fts5Available
+}
+
+// setupSearch creates the FTS5 indexes once the tables they index exist,
+// rebuilding them if their triggers were missing and may have missed changes.
+// Without FTS5 the triggers are dropped, as writes fail while they exist.
+func (s Database) setupSearch() error {
+	if s.dialect != "sqlite3" {
+		return nil
+	}
+	if !fts5Available {
+		return s.dropSearchTriggers()
+	}
+
+	ctx := context.Background()
+	var tables int
+	err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'journal_entries'").Scan(&tables)
+	if err != nil || tables == 0 {
+		return err
+	}
+
+	var triggers int
+	err = s.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND tbl_name IN ('habit_entries', 'journal_entries')").Scan(&triggers)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range searchSchema {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if triggers == len(searchTriggers) {
+		return nil
+	}
+
+	s.log.Info("Rebuilding search indexes")
+	for _, table := range searchTables {
+		if _, err := s.db.ExecContext(ctx, "INSERT INTO "+table+"("+table+") VALUES ('rebuild')"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropSearch removes the FTS5 indexes so migrations can change the tables
+// they index.
+func (s Database) dropSearch() error {
+	if s.dialect != "sqlite3" {
+		return nil
+	}
+	if err := s.dropSearchTriggers(); err != nil {
+		return err
+	}
+	if !fts5Available {
+		return nil
+	}
+
+	for _, table := range searchTables {
+		if _, err := s.db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Database) dropSearchTriggers() error {
+	for _, trigger := range searchTriggers {
+		if _, err := s.db.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SearchHabitEntryNotes returns up to limit of the most recent notes on a
+// user's habit entries matching query.
+func (s Database) SearchHabitEntryNotes(ctx context.Context, userId int64, query string, limit int64) ([]repository.SearchHabitEntryNotesRow, error) {
+	if !s.fullTextSearch() {
+		return s.Queries.SearchHabitEntryNotes(ctx, repository.SearchHabitEntryNotesParams{UserID: userId, Query: query, Limit: limit})
+	}
+
+	rows, err := s.db.QueryContext(ctx, searchHabitEntryNotes, matchQuery(query), userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []repository.SearchHabitEntryNotesRow
+	for rows.Next() {
+		var note repository.SearchHabitEntryNotesRow
+		if err := rows.Scan(&note.ID, &note.HabitID, &note.HabitName, &note.Date, &note.Note); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// SearchJournalEntries returns up to limit of a user's most recent journal
+// entries matching query.
+func (s Database) SearchJournalEntries(ctx context.Context, userId int64, query string, limit int64) ([]repository.JournalEntry, error) {
+	if !s.fullTextSearch() {
+		return s.Queries.SearchJournalEntries(ctx, repository.SearchJournalEntriesParams{UserID: userId, Query: query, Limit: limit})
+	}
+
+	rows, err := s.db.QueryContext(ctx, searchJournalEntries, matchQuery(query), userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []repository.JournalEntry
+	for rows.Next() {
+		var entry repository.JournalEntry
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Date, &entry.Text, &entry.CreatedAt, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// matchQuery quotes each word of query so FTS5 matches rows containing all of
+// them, rather than reading the query as FTS5 syntax.
+func matchQuery(query string) string {
+	words := strings.Fields(query)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,6 @@
+//go:build !(sqlite_fts5 || fts5)
+
+package storage
+
+// fts5Available is set by the same build tags that compile FTS5 into SQLite.
+const fts5Available = false
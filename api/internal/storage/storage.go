@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	DriverSqlite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+//go:embed database/migrations/*.sql
+var sqliteMigrations embed.FS
+
+//go:embed database/postgresMigrations/*.sql
+var postgresMigrations embed.FS
+
+type Database struct {
+	db            *sql.DB
+	Queries       repository.Querier
+	log           logger.Logger
+	migrations    fs.FS
+	dialect       string
+	migrationsDir string
+}
+
+// Open connects to the database for driver, which is a path for SQLite and a
+// connection URL for PostgreSQL.
+func Open(driver string, dataSource string, logger logger.Logger) (*Database, error) {
+	switch driver {
+	case DriverSqlite:
+		return NewSqliteStorage(dataSource, logger)
+	case DriverPostgres:
+		return NewPostgresStorage(dataSource, logger)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+}
+
+func NewSqliteStorage(databasePath string, logger logger.Logger) (*Database, error) {
+	absoluteDir, err := filepath.Abs(filepath.Dir(databasePath))
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(absoluteDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", databasePath+"?_foreign_keys=on")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{
+		db:            db,
+		Queries:       repository.NewSqliteQueries(db),
+		log:           logger,
+		migrations:    sqliteMigrations,
+		dialect:       "sqlite3",
+		migrationsDir: "database/migrations",
+	}, nil
+}
+
+func NewPostgresStorage(url string, logger logger.Logger) (*Database, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	return &Database{
+		db:            db,
+		Queries:       repository.NewPostgresQueries(db),
+		log:           logger,
+		migrations:    postgresMigrations,
+		dialect:       "postgres",
+		migrationsDir: "database/postgresMigrations",
+	}, nil
+}
+
+// goose points goose at this database's migrations. Goose keeps these
+// settings globally so they are set before every migration command.
+func (s Database) goose() error {
+	goose.SetBaseFS(s.migrations)
+	return goose.SetDialect(s.dialect)
+}
+
+// Reset rolls back every migration, deleting all data.
+func (s Database) Reset() error {
+	s.log.Warn("Resetting database")
+	if err := s.goose(); err != nil {
+		return err
+	}
+	if err := s.dropSearch(); err != nil {
+		return err
+	}
+	return goose.Reset(s.db, s.migrationsDir)
+}
+
+func (s Database) ApplyMigrations() error {
+	s.log.Info("Applying migrations")
+	if err := s.goose(); err != nil {
+		return err
+	}
+	if err := goose.Up(s.db, s.migrationsDir); err != nil {
+		return err
+	}
+	return s.setupSearch()
+}
+
+// RollbackMigration rolls back the most recent migration.
+func (s Database) RollbackMigration() error {
+	s.log.Warn("Rolling back migration")
+	if err := s.goose(); err != nil {
+		return err
+	}
+	if err := s.dropSearch(); err != nil {
+		return err
+	}
+	if err := goose.Down(s.db, s.migrationsDir); err != nil {
+		return err
+	}
+	return s.setupSearch()
+}
+
+// RedoMigration rolls back the most recent migration and applies it again.
+func (s Database) RedoMigration() error {
+	s.log.Info("Redoing migration")
+	if err := s.goose(); err != nil {
+		return err
+	}
+	if err := s.dropSearch(); err != nil {
+		return err
+	}
+	if err := goose.Redo(s.db, s.migrationsDir); err != nil {
+		return err
+	}
+	return s.setupSearch()
+}
+
+// MigrationStatus logs whether each migration has been applied.
+func (s Database) MigrationStatus() error {
+	if err := s.goose(); err != nil {
+		return err
+	}
+	return goose.Status(s.db, s.migrationsDir)
+}
+
+func (s Database) Close() error {
+	return s.db.Close()
+}
+
+// Transaction runs fn with queries inside a transaction, committing if fn
+// returns nil and rolling back otherwise.
+func (s Database) Transaction(ctx context.Context, fn func(queries repository.Querier) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(s.Queries.WithTx(tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
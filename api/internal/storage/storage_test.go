@@ -0,0 +1,822 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ReidMason/habit-tracker/internal/logger"
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresUrlEnv is a database the tests run against instead of starting an
+// embedded Postgres. All data in it is deleted.
+const postgresUrlEnv = "HABIT_TEST_POSTGRES_URL"
+
+var embeddedPostgres struct {
+	database *embeddedpostgres.EmbeddedPostgres
+	err      error
+	url      string
+	once     sync.Once
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if embeddedPostgres.database != nil {
+		if err := embeddedPostgres.database.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to stop embedded Postgres: %v\n", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// postgresUrl returns the database to test the Postgres backend against,
+// starting an embedded Postgres the first time it is needed. The binaries are
+// downloaded and cached on first use, and the test is skipped if they can't be.
+func postgresUrl(t *testing.T) string {
+	if url := os.Getenv(postgresUrlEnv); url != "" {
+		return url
+	}
+
+	embeddedPostgres.once.Do(func() {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			embeddedPostgres.err = err
+			return
+		}
+		port := uint32(listener.Addr().(*net.TCPAddr).Port)
+		listener.Close()
+
+		runtimePath, err := os.MkdirTemp("", "habit-tracker-postgres")
+		if err != nil {
+			embeddedPostgres.err = err
+			return
+		}
+
+		config := embeddedpostgres.DefaultConfig().Port(port).RuntimePath(runtimePath).Logger(io.Discard)
+		database := embeddedpostgres.NewDatabase(config)
+		if err := database.Start(); err != nil {
+			embeddedPostgres.err = err
+			return
+		}
+		embeddedPostgres.database = database
+		embeddedPostgres.url = config.GetConnectionURL() + "?sslmode=disable"
+	})
+	if embeddedPostgres.err != nil {
+		t.Skipf("embedded Postgres could not start, set %s to test against another database: %v", postgresUrlEnv, embeddedPostgres.err)
+	}
+
+	return embeddedPostgres.url
+}
+
+// forEachBackend runs test against a freshly migrated database of every
+// backend, so each backend's queries behave the same. The Postgres backend is
+// skipped by go test -short.
+func forEachBackend(t *testing.T, test func(t *testing.T, db *Database)) {
+	t.Run(DriverSqlite, func(t *testing.T) {
+		db, err := NewSqliteStorage(filepath.Join(t.TempDir(), "data.db"), &logger.MockLogger{})
+		require.NoError(t, err)
+		defer db.Close()
+		require.NoError(t, db.ApplyMigrations())
+
+		test(t, db)
+	})
+
+	t.Run(DriverPostgres, func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("Postgres is not tested in short mode")
+		}
+
+		db, err := NewPostgresStorage(postgresUrl(t), &logger.MockLogger{})
+		require.NoError(t, err)
+		defer db.Close()
+		require.NoError(t, db.Reset())
+		require.NoError(t, db.ApplyMigrations())
+
+		test(t, db)
+	})
+}
+
+func createHabit(t *testing.T, queries repository.Querier, userId int64, name string, index int64) repository.Habit {
+	habit, err := queries.CreateHabit(context.Background(), repository.CreateHabitParams{
+		UserID:       userId,
+		Name:         name,
+		Colour:       "red",
+		Index:        index,
+		Active:       true,
+		ScheduleType: "daily",
+	})
+	require.NoError(t, err)
+
+	return habit
+}
+
+func TestUsers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		bob, err := db.Queries.CreateUser(ctx, "bob")
+		require.NoError(t, err)
+		alice, err := db.Queries.CreateUserWithPassword(ctx, repository.CreateUserWithPasswordParams{
+			Name:         "alice",
+			PasswordHash: sql.NullString{String: "hash", Valid: true},
+		})
+		require.NoError(t, err)
+
+		// Act
+		updated, updateErr := db.Queries.SetUserAdmin(ctx, repository.SetUserAdminParams{Admin: true, UpdatedAt: "2024-12-16 10:00:00", ID: alice.ID})
+		byName, byNameErr := db.Queries.GetUserByName(ctx, "alice")
+		users, usersErr := db.Queries.GetUsers(ctx)
+		_, duplicateErr := db.Queries.CreateUserWithPassword(ctx, repository.CreateUserWithPasswordParams{
+			Name:         "alice",
+			PasswordHash: sql.NullString{String: "other", Valid: true},
+		})
+		_, missingErr := db.Queries.GetUserByID(ctx, alice.ID+100)
+		withSettings, settingsErr := db.Queries.UpdateUserSettings(ctx, repository.UpdateUserSettingsParams{Timezone: "Europe/London", DayStartHour: 4, UpdatedAt: "2024-12-16 11:00:00", ID: bob.ID})
+
+		// Assert
+		assert.NoError(t, updateErr)
+		assert.Equal(t, int64(1), updated)
+		assert.NoError(t, byNameErr)
+		assert.True(t, byName.Admin)
+		assert.Equal(t, "hash", byName.PasswordHash.String)
+		assert.Equal(t, "2024-12-16 10:00:00", byName.UpdatedAt)
+		assert.NoError(t, usersErr)
+		assert.Equal(t, []string{"bob", "alice"}, []string{users[0].Name, users[1].Name})
+		assert.False(t, users[0].PasswordHash.Valid)
+		assert.NotEmpty(t, bob.CreatedAt)
+		assert.Equal(t, "UTC", bob.Timezone)
+		assert.NoError(t, settingsErr)
+		assert.Equal(t, "Europe/London", withSettings.Timezone)
+		assert.Equal(t, int64(4), withSettings.DayStartHour)
+		assert.Error(t, duplicateErr)
+		assert.ErrorIs(t, missingErr, sql.ErrNoRows)
+	})
+}
+
+func TestHabits(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		second := createHabit(t, db.Queries, user.ID, "Read", 1)
+		first := createHabit(t, db.Queries, user.ID, "Run", 0)
+
+		// Act
+		updated, updateErr := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{
+			ID:           second.ID,
+			Name:         "Read More",
+			Description:  sql.NullString{String: "Ten pages", Valid: true},
+			Colour:       "blue",
+			Index:        2,
+			Active:       false,
+			ScheduleType: sql.NullString{String: "weekly", Valid: true},
+			UpdatedAt:    "2024-12-16 10:00:00",
+			Version:      second.Version,
+		})
+		moved, moveErr := db.Queries.SetHabitIndex(ctx, repository.SetHabitIndexParams{Index: 3, UpdatedAt: "2024-12-16 10:00:00", ID: first.ID, UserID: user.ID})
+		notMoved, notMovedErr := db.Queries.SetHabitIndex(ctx, repository.SetHabitIndexParams{Index: 4, UpdatedAt: "2024-12-16 10:00:00", ID: first.ID, UserID: user.ID + 1})
+		archived, archiveErr := db.Queries.SetHabitArchivedAt(ctx, repository.SetHabitArchivedAtParams{
+			ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true},
+			UpdatedAt:  "2024-12-18 10:00:00",
+			ID:         second.ID,
+		})
+		habits, habitsErr := db.Queries.GetHabits(ctx, user.ID)
+		deleted, deleteErr := db.Queries.TrashHabit(ctx, repository.TrashHabitParams{
+			DeletedAt: sql.NullString{String: "2024-12-20 10:00:00", Valid: true},
+			UpdatedAt: "2024-12-20 10:00:00",
+			ID:        first.ID,
+		})
+		_, deletedErr := db.Queries.GetHabit(ctx, first.ID)
+
+		// Assert
+		assert.NoError(t, updateErr)
+		assert.Equal(t, "Read More", updated.Name)
+		assert.Equal(t, "Ten pages", updated.Description.String)
+		assert.Equal(t, int64(2), updated.Index)
+		assert.False(t, updated.Active)
+		assert.Equal(t, "weekly", updated.ScheduleType)
+		assert.Equal(t, second.ScheduleCount, updated.ScheduleCount)
+		assert.Equal(t, second.TargetValue, updated.TargetValue)
+		assert.NoError(t, moveErr)
+		assert.Equal(t, int64(1), moved)
+		assert.NoError(t, notMovedErr)
+		assert.Equal(t, int64(0), notMoved)
+		assert.NoError(t, archiveErr)
+		assert.Equal(t, "2024-12-18 10:00:00", archived.ArchivedAt.String)
+		assert.False(t, first.ArchivedAt.Valid)
+		assert.NoError(t, habitsErr)
+		assert.Equal(t, []int64{second.ID, first.ID}, []int64{habits[0].ID, habits[1].ID})
+		assert.Equal(t, int64(3), habits[1].Index)
+		assert.NoError(t, deleteErr)
+		assert.Equal(t, first.ID, deleted.ID)
+		assert.Equal(t, "2024-12-20 10:00:00", deleted.DeletedAt.String)
+		assert.ErrorIs(t, deletedErr, sql.ErrNoRows)
+	})
+}
+
+func TestHabitVersions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 1)
+		update := repository.UpdateHabitParams{Name: "Run", Active: true, UpdatedAt: "2024-12-16 10:00:00", ID: habit.ID, Version: habit.Version}
+
+		// Act
+		updated, updateErr := db.Queries.UpdateHabit(ctx, update)
+		_, staleErr := db.Queries.UpdateHabit(ctx, update)
+		_, moveErr := db.Queries.SetHabitIndex(ctx, repository.SetHabitIndexParams{Index: 2, UpdatedAt: "2024-12-16 10:00:00", ID: habit.ID, UserID: user.ID})
+		archived, archiveErr := db.Queries.SetHabitArchivedAt(ctx, repository.SetHabitArchivedAtParams{
+			ArchivedAt: sql.NullString{String: "2024-12-18 10:00:00", Valid: true},
+			UpdatedAt:  "2024-12-18 10:00:00",
+			ID:         habit.ID,
+		})
+
+		// Assert
+		assert.Equal(t, int64(1), habit.Version)
+		assert.NoError(t, updateErr)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.ErrorIs(t, staleErr, sql.ErrNoRows)
+		assert.NoError(t, moveErr)
+		assert.NoError(t, archiveErr)
+		assert.Equal(t, int64(4), archived.Version)
+	})
+}
+
+func TestHabitDetailsAreKeptUnlessGiven(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		category, err := db.Queries.CreateCategory(ctx, repository.CreateCategoryParams{UserID: user.ID, Name: "Health", Colour: "green"})
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 1)
+		detailed, err := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{
+			Name:        "Run",
+			Description: sql.NullString{String: "5k", Valid: true},
+			Icon:        sql.NullString{String: "🏃", Valid: true},
+			CategoryID:  sql.NullInt64{Int64: category.ID, Valid: true},
+			Active:      true,
+			UpdatedAt:   "2024-12-16 10:00:00",
+			ID:          habit.ID,
+			Version:     habit.Version,
+		})
+		require.NoError(t, err)
+
+		// Act
+		kept, keepErr := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{Name: "Run", Index: 2, Active: true, UpdatedAt: "2024-12-17 10:00:00", ID: habit.ID, Version: detailed.Version})
+		cleared, clearErr := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{
+			Name:        "Run",
+			Description: sql.NullString{Valid: true},
+			Icon:        sql.NullString{Valid: true},
+			CategoryID:  sql.NullInt64{Valid: true},
+			Active:      true,
+			UpdatedAt:   "2024-12-18 10:00:00",
+			ID:          habit.ID,
+			Version:     kept.Version,
+		})
+
+		// Assert
+		assert.NoError(t, keepErr)
+		assert.Equal(t, int64(2), kept.Index)
+		assert.Equal(t, sql.NullString{String: "5k", Valid: true}, kept.Description)
+		assert.Equal(t, "🏃", kept.Icon)
+		assert.Equal(t, sql.NullInt64{Int64: category.ID, Valid: true}, kept.CategoryID)
+		assert.NoError(t, clearErr)
+		assert.False(t, cleared.Description.Valid)
+		assert.Empty(t, cleared.Icon)
+		assert.False(t, cleared.CategoryID.Valid)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 0)
+		trashedHabit := createHabit(t, db.Queries, user.ID, "Read", 1)
+		entry, err := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: "2024-12-01", Value: 4, Status: "done"})
+		require.NoError(t, err)
+		oldEntry, err := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: "2024-12-02", Value: 1, Status: "done"})
+		require.NoError(t, err)
+		trash := func(entryId int64, deletedAt string) {
+			_, err := db.Queries.TrashHabitEntry(ctx, repository.TrashHabitEntryParams{DeletedAt: sql.NullString{String: deletedAt, Valid: true}, UpdatedAt: deletedAt, ID: entryId})
+			require.NoError(t, err)
+		}
+		trash(entry.ID, "2024-12-20 10:00:00")
+		trash(oldEntry.ID, "2024-11-01 10:00:00")
+		_, err = db.Queries.TrashHabit(ctx, repository.TrashHabitParams{DeletedAt: sql.NullString{String: "2024-11-01 10:00:00", Valid: true}, UpdatedAt: "2024-11-01 10:00:00", ID: trashedHabit.ID})
+		require.NoError(t, err)
+
+		// Act
+		liveEntries, liveErr := db.Queries.GetUserHabitEntriesBetween(ctx, repository.GetUserHabitEntriesBetweenParams{UserID: user.ID, FromDate: "2024-12-01", ToDate: "2024-12-31"})
+		trashedHabits, trashedHabitsErr := db.Queries.GetTrashedHabits(ctx, user.ID)
+		trashedEntries, trashedEntriesErr := db.Queries.GetTrashedHabitEntries(ctx, user.ID)
+		_, foreignRestoreErr := db.Queries.RestoreHabitEntry(ctx, repository.RestoreHabitEntryParams{UpdatedAt: "2024-12-21 10:00:00", ID: entry.ID, UserID: user.ID + 1})
+		restored, restoreErr := db.Queries.RestoreHabitEntry(ctx, repository.RestoreHabitEntryParams{UpdatedAt: "2024-12-21 10:00:00", ID: entry.ID, UserID: user.ID})
+		_, restoreLiveErr := db.Queries.RestoreHabitEntry(ctx, repository.RestoreHabitEntryParams{UpdatedAt: "2024-12-21 10:00:00", ID: entry.ID, UserID: user.ID})
+		trashedOwner, trashedOwnerErr := db.Queries.GetHabitOwner(ctx, trashedHabit.ID)
+		purgedEntries, purgeEntriesErr := db.Queries.PurgeHabitEntries(ctx, sql.NullString{String: "2024-12-01 00:00:00", Valid: true})
+		purgedHabits, purgeHabitsErr := db.Queries.PurgeHabits(ctx, sql.NullString{String: "2024-12-01 00:00:00", Valid: true})
+		revived, reviveErr := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: "2024-12-02", Value: 2, Status: "done"})
+		trash(revived.ID, "2024-12-20 10:00:00")
+		incremented, incrementErr := db.Queries.IncrementHabitEntry(ctx, repository.IncrementHabitEntryParams{HabitID: habit.ID, Date: "2024-12-02", Value: 3})
+		remaining, remainingErr := db.Queries.GetTrashedHabits(ctx, user.ID)
+
+		// Assert
+		assert.NoError(t, liveErr)
+		assert.Empty(t, liveEntries)
+		assert.NoError(t, trashedHabitsErr)
+		assert.Len(t, trashedHabits, 1)
+		assert.Equal(t, trashedHabit.ID, trashedHabits[0].ID)
+		assert.NoError(t, trashedEntriesErr)
+		assert.Len(t, trashedEntries, 2)
+		assert.ErrorIs(t, foreignRestoreErr, sql.ErrNoRows)
+		assert.NoError(t, restoreErr)
+		assert.False(t, restored.DeletedAt.Valid)
+		assert.Equal(t, 4.0, restored.Value)
+		assert.ErrorIs(t, restoreLiveErr, sql.ErrNoRows)
+		assert.NoError(t, trashedOwnerErr)
+		assert.Equal(t, user.ID, trashedOwner)
+		assert.NoError(t, purgeEntriesErr)
+		require.Len(t, purgedEntries, 1)
+		assert.Equal(t, oldEntry.ID, purgedEntries[0].ID)
+		assert.NoError(t, purgeHabitsErr)
+		require.Len(t, purgedHabits, 1)
+		assert.Equal(t, trashedHabit.ID, purgedHabits[0].ID)
+		assert.NoError(t, reviveErr)
+		assert.Equal(t, 2.0, revived.Value)
+		assert.NoError(t, incrementErr)
+		assert.Equal(t, revived.ID, incremented.ID)
+		assert.Equal(t, 3.0, incremented.Value)
+		assert.False(t, incremented.DeletedAt.Valid)
+		assert.NoError(t, remainingErr)
+		assert.Empty(t, remaining)
+	})
+}
+
+func TestHabitEntries(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 0)
+		for _, date := range []string{"2024-12-01", "2024-12-02", "2024-12-03"} {
+			_, err := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: date, Value: 1, Status: "done"})
+			require.NoError(t, err)
+		}
+
+		// Act
+		incremented, incrementErr := db.Queries.IncrementHabitEntry(ctx, repository.IncrementHabitEntryParams{HabitID: habit.ID, Date: "2024-12-03", Value: 2.5})
+		_, importErr := db.Queries.ImportHabitEntry(ctx, repository.ImportHabitEntryParams{HabitID: habit.ID, Date: "2024-12-03", Value: 9, Status: "done"})
+		between, betweenErr := db.Queries.GetHabitEntriesBetween(ctx, repository.GetHabitEntriesBetweenParams{HabitID: habit.ID, FromDate: "2024-12-02", ToDate: "2024-12-03", Limit: 10})
+		before, beforeErr := db.Queries.GetHabitEntriesBefore(ctx, repository.GetHabitEntriesBeforeParams{HabitID: habit.ID, Date: "2024-12-03", Limit: 1})
+		userEntries, userEntriesErr := db.Queries.GetUserHabitEntriesBetween(ctx, repository.GetUserHabitEntriesBetweenParams{UserID: user.ID, FromDate: "2024-12-01", ToDate: "2024-12-31"})
+		owner, ownerErr := db.Queries.GetHabitEntryOwner(ctx, incremented.ID)
+
+		// Assert
+		assert.NoError(t, incrementErr)
+		assert.Equal(t, 3.5, incremented.Value)
+		assert.ErrorIs(t, importErr, sql.ErrNoRows)
+		assert.NoError(t, betweenErr)
+		assert.Equal(t, []string{"2024-12-02", "2024-12-03"}, []string{between[0].Date, between[1].Date})
+		assert.NoError(t, beforeErr)
+		assert.Len(t, before, 1)
+		assert.Equal(t, "2024-12-02", before[0].Date)
+		assert.NoError(t, userEntriesErr)
+		assert.Len(t, userEntries, 3)
+		assert.NoError(t, ownerErr)
+		assert.Equal(t, user.ID, owner)
+	})
+}
+
+func TestIncrementHabitEntryIsNeverNegative(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Water", 1)
+		date := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+		_, err = db.IncrementHabitEntry(ctx, habit.ID, date, 2)
+		require.NoError(t, err)
+
+		// Act
+		decremented, decrementErr := db.IncrementHabitEntry(ctx, habit.ID, date, -1)
+		_, negativeErr := db.IncrementHabitEntry(ctx, habit.ID, date, -2)
+		_, newNegativeErr := db.IncrementHabitEntry(ctx, habit.ID, date.AddDate(0, 0, 1), -1)
+		entries, entriesErr := db.Queries.GetHabitEntriesBetween(ctx, repository.GetHabitEntriesBetweenParams{HabitID: habit.ID, FromDate: "2024-12-20", ToDate: "2024-12-21", Limit: 10})
+
+		// Assert
+		assert.NoError(t, decrementErr)
+		assert.Equal(t, 1.0, decremented.Value)
+		assert.ErrorIs(t, negativeErr, ErrNegativeValue)
+		assert.ErrorIs(t, newNegativeErr, ErrNegativeValue)
+		assert.NoError(t, entriesErr)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, 1.0, entries[0].Value)
+	})
+}
+
+func TestEntryStatusAndFreezes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{
+			UserID:          user.ID,
+			Name:            "Run",
+			Active:          true,
+			ScheduleType:    "daily",
+			ScheduleCount:   1,
+			ScheduleFreezes: sql.NullInt64{Int64: 2, Valid: true},
+		})
+		require.NoError(t, err)
+		date := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		skipped, skipErr := db.CreateHabitEntry(ctx, habit.ID, date, 0, "skipped")
+		failed, failErr := db.SetHabitEntryStatus(ctx, skipped.Id, "failed")
+		incremented, incrementErr := db.IncrementHabitEntry(ctx, habit.ID, date, 1)
+		kept, keepErr := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{Name: "Run", Active: true, UpdatedAt: "2024-12-20 10:00:00", ID: habit.ID, Version: habit.Version})
+		cleared, clearErr := db.Queries.UpdateHabit(ctx, repository.UpdateHabitParams{
+			Name:         "Run",
+			Active:       true,
+			ScheduleType: sql.NullString{String: "daily", Valid: true},
+			UpdatedAt:    "2024-12-20 10:00:00",
+			ID:           habit.ID,
+			Version:      habit.Version + 1,
+		})
+
+		// Assert
+		assert.NoError(t, skipErr)
+		assert.Equal(t, "skipped", skipped.Status)
+		assert.NoError(t, failErr)
+		assert.Equal(t, "failed", failed.Status)
+		assert.NoError(t, incrementErr)
+		assert.Equal(t, "done", incremented.Status)
+		assert.Equal(t, 1.0, incremented.Value)
+		assert.Equal(t, sql.NullInt64{Int64: 2, Valid: true}, habit.ScheduleFreezes)
+		assert.NoError(t, keepErr)
+		assert.Equal(t, habit.ScheduleFreezes, kept.ScheduleFreezes)
+		assert.NoError(t, clearErr)
+		assert.False(t, cleared.ScheduleFreezes.Valid)
+	})
+}
+
+func TestSessionsAndApiTokens(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		_, err = db.Queries.UpdateUserSettings(ctx, repository.UpdateUserSettingsParams{Timezone: "Asia/Tokyo", DayStartHour: 3, UpdatedAt: "2024-12-16 11:00:00", ID: user.ID})
+		require.NoError(t, err)
+		_, err = db.Queries.CreateSession(ctx, repository.CreateSessionParams{UserID: user.ID, TokenHash: "current", ExpiresAt: "2024-12-20 00:00:00"})
+		require.NoError(t, err)
+		_, err = db.Queries.CreateSession(ctx, repository.CreateSessionParams{UserID: user.ID, TokenHash: "expired", ExpiresAt: "2024-12-10 00:00:00"})
+		require.NoError(t, err)
+		token, err := db.Queries.CreateApiToken(ctx, repository.CreateApiTokenParams{UserID: user.ID, Name: "script", TokenHash: "token", Scope: "read"})
+		require.NoError(t, err)
+
+		// Act
+		deleteErr := db.Queries.DeleteExpiredSessions(ctx, "2024-12-16 00:00:00")
+		sessionUser, sessionErr := db.Queries.GetSessionUser(ctx, repository.GetSessionUserParams{TokenHash: "current", ExpiresAt: "2024-12-16 00:00:00"})
+		_, expiredErr := db.Queries.GetSessionUser(ctx, repository.GetSessionUserParams{TokenHash: "expired", ExpiresAt: "2024-12-01 00:00:00"})
+		tokenUser, tokenErr := db.Queries.GetApiTokenUser(ctx, "token")
+		deletedTokens, deleteTokenErr := db.Queries.DeleteApiToken(ctx, repository.DeleteApiTokenParams{ID: token.ID, UserID: user.ID + 1})
+
+		// Assert
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, sessionErr)
+		assert.Equal(t, "alice", sessionUser.Name)
+		assert.Equal(t, "Asia/Tokyo", sessionUser.Timezone)
+		assert.Equal(t, int64(3), sessionUser.DayStartHour)
+		assert.ErrorIs(t, expiredErr, sql.ErrNoRows)
+		assert.NoError(t, tokenErr)
+		assert.Equal(t, "read", tokenUser.Scope)
+		assert.Equal(t, user.ID, tokenUser.UserID)
+		assert.Equal(t, "Asia/Tokyo", tokenUser.Timezone)
+		assert.NoError(t, deleteTokenErr)
+		assert.Equal(t, int64(0), deletedTokens)
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		failure := errors.New("failure")
+
+		// Act
+		rolledBackErr := db.Transaction(ctx, func(queries repository.Querier) error {
+			createHabit(t, queries, user.ID, "Run", 0)
+			return failure
+		})
+		committedErr := db.Transaction(ctx, func(queries repository.Querier) error {
+			createHabit(t, queries, user.ID, "Read", 0)
+			return nil
+		})
+		habits, habitsErr := db.Queries.GetHabits(ctx, user.ID)
+
+		// Assert
+		assert.ErrorIs(t, rolledBackErr, failure)
+		assert.NoError(t, committedErr)
+		assert.NoError(t, habitsErr)
+		assert.Len(t, habits, 1)
+		assert.Equal(t, "Read", habits[0].Name)
+	})
+}
+
+func TestAuditEvents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		other, err := db.Queries.CreateUser(ctx, "bob")
+		require.NoError(t, err)
+		record := func(userId int64, entityType string, entityId int64, action string, createdAt string) int64 {
+			event, err := db.Queries.CreateAuditEvent(ctx, repository.CreateAuditEventParams{
+				UserID:     userId,
+				ActorID:    sql.NullInt64{Int64: userId, Valid: true},
+				EntityType: entityType,
+				EntityID:   entityId,
+				Action:     action,
+				DataAfter:  sql.NullString{String: `{"id":1}`, Valid: true},
+				RequestID:  sql.NullString{String: "request", Valid: true},
+				CreatedAt:  createdAt,
+			})
+			require.NoError(t, err)
+			return event.ID
+		}
+		created := record(user.ID, "habit", 1, "create", "2024-12-20 10:00:00")
+		updated := record(user.ID, "habit", 1, "update", "2024-12-21 10:00:00")
+		entry := record(user.ID, "habitEntry", 4, "create", "2024-12-22 10:00:00")
+		record(other.ID, "habit", 2, "create", "2024-12-22 10:00:00")
+
+		// Act
+		all, allErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.ID, Limit: 10})
+		page, pageErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.ID, BeforeID: sql.NullInt64{Int64: entry, Valid: true}, Limit: 1})
+		habit, habitErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{
+			UserID:     user.ID,
+			EntityType: sql.NullString{String: "habit", Valid: true},
+			EntityID:   sql.NullInt64{Int64: 1, Valid: true},
+			Action:     sql.NullString{String: "create", Valid: true},
+			Limit:      10,
+		})
+		between, betweenErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{
+			UserID:   user.ID,
+			FromTime: sql.NullString{String: "2024-12-21 00:00:00", Valid: true},
+			ToTime:   sql.NullString{String: "2024-12-21 23:59:59", Valid: true},
+			Limit:    10,
+		})
+
+		// Assert
+		assert.NoError(t, allErr)
+		assert.Equal(t, []int64{entry, updated, created}, []int64{all[0].ID, all[1].ID, all[2].ID})
+		assert.Len(t, all, 3)
+		assert.Equal(t, "request", all[0].RequestID.String)
+		assert.NoError(t, pageErr)
+		assert.Len(t, page, 1)
+		assert.Equal(t, updated, page[0].ID)
+		assert.NoError(t, habitErr)
+		assert.Len(t, habit, 1)
+		assert.Equal(t, created, habit[0].ID)
+		assert.NoError(t, betweenErr)
+		assert.Len(t, between, 1)
+		assert.Equal(t, updated, between[0].ID)
+	})
+}
+
+func TestAuditedChanges(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := auditService.WithRequestId(context.Background(), "request")
+		user, err := db.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		ctx = auditService.WithActor(ctx, user.Id)
+		habit := createHabit(t, db.Queries, user.Id, "Run", 0)
+		date := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		created, createErr := db.CreateHabitEntry(ctx, habit.ID, date, 1, "done")
+		_, incrementErr := db.IncrementHabitEntry(ctx, habit.ID, date, 2)
+		_, deleteErr := db.DeleteHabitEntry(ctx, created.Id)
+		events, eventsErr := db.Queries.GetAuditEvents(ctx, repository.GetAuditEventsParams{UserID: user.Id, Limit: 10})
+
+		// Assert
+		assert.NoError(t, createErr)
+		assert.NoError(t, incrementErr)
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, eventsErr)
+		actions := make([]string, len(events))
+		for i, event := range events {
+			actions[i] = event.EntityType + " " + event.Action
+			assert.Equal(t, "request", event.RequestID.String)
+		}
+		assert.Equal(t, []string{"habitEntry delete", "habitEntry update", "habitEntry create", "user create"}, actions)
+		assert.False(t, events[3].ActorID.Valid)
+		assert.Equal(t, user.Id, events[0].ActorID.Int64)
+		assert.Contains(t, events[0].DataBefore.String, `"value":3`)
+		assert.False(t, events[0].DataAfter.Valid)
+	})
+}
+
+func TestNotesAndSearch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		other, err := db.Queries.CreateUser(ctx, "bob")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 0)
+		otherHabit := createHabit(t, db.Queries, other.ID, "Run", 0)
+		first, err := db.CreateHabitEntry(ctx, habit.ID, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), 1, "done")
+		require.NoError(t, err)
+		second, err := db.CreateHabitEntry(ctx, habit.ID, time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), 1, "done")
+		require.NoError(t, err)
+		otherEntry, err := db.CreateHabitEntry(ctx, otherHabit.ID, time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), 1, "done")
+		require.NoError(t, err)
+		_, err = db.SetHabitEntryNote(ctx, otherEntry.Id, "Running in the rain")
+		require.NoError(t, err)
+		_, err = db.Queries.SaveJournalEntry(ctx, repository.SaveJournalEntryParams{UserID: user.ID, Date: "2024-12-20", Text: "A rainy day", UpdatedAt: "2024-12-20 20:00:00"})
+		require.NoError(t, err)
+		_, err = db.Queries.SaveJournalEntry(ctx, repository.SaveJournalEntryParams{UserID: user.ID, Date: "2024-12-21", Text: "Sunny", UpdatedAt: "2024-12-21 20:00:00"})
+		require.NoError(t, err)
+
+		// Act
+		noted, noteErr := db.SetHabitEntryNote(ctx, first.Id, "Slow run, heavy rain")
+		_, err = db.SetHabitEntryNote(ctx, second.Id, "Rain again")
+		require.NoError(t, err)
+		_, err = db.SetHabitEntryNote(ctx, second.Id, "Dry")
+		require.NoError(t, err)
+		incremented, incrementErr := db.IncrementHabitEntry(ctx, habit.ID, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), 1)
+		notes, notesErr := db.SearchHabitEntryNotes(ctx, user.ID, "rain", 10)
+		journal, journalErr := db.SearchJournalEntries(ctx, user.ID, "rainy", 10)
+		_, err = db.Queries.DeleteJournalEntry(ctx, repository.DeleteJournalEntryParams{UserID: user.ID, Date: "2024-12-20"})
+		require.NoError(t, err)
+		deletedJournal, deletedErr := db.SearchJournalEntries(ctx, user.ID, "rainy", 10)
+
+		// Assert
+		assert.NoError(t, noteErr)
+		assert.Equal(t, "Slow run, heavy rain", noted.Note)
+		assert.NoError(t, incrementErr)
+		assert.Equal(t, "Slow run, heavy rain", incremented.Note)
+		assert.NoError(t, notesErr)
+		assert.Len(t, notes, 1)
+		assert.Equal(t, first.Id, notes[0].ID)
+		assert.Equal(t, "Run", notes[0].HabitName)
+		assert.NoError(t, journalErr)
+		assert.Len(t, journal, 1)
+		assert.Equal(t, "2024-12-20", journal[0].Date)
+		assert.NoError(t, deletedErr)
+		assert.Empty(t, deletedJournal)
+	})
+}
+
+func TestCategories(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		other, err := db.Queries.CreateUser(ctx, "bob")
+		require.NoError(t, err)
+		health, err := db.Queries.CreateCategory(ctx, repository.CreateCategoryParams{UserID: user.ID, Name: "Health", Colour: "#16a34a"})
+		require.NoError(t, err)
+		_, err = db.Queries.CreateCategory(ctx, repository.CreateCategoryParams{UserID: user.ID, Name: "Admin", Colour: "#dc2626"})
+		require.NoError(t, err)
+		habit, err := db.Queries.CreateHabit(ctx, repository.CreateHabitParams{
+			UserID:       user.ID,
+			Name:         "Run",
+			Colour:       "red",
+			Active:       true,
+			ScheduleType: "daily",
+			Icon:         "🏃",
+			CategoryID:   sql.NullInt64{Int64: health.ID, Valid: true},
+		})
+		require.NoError(t, err)
+
+		// Act
+		_, duplicateErr := db.Queries.CreateCategory(ctx, repository.CreateCategoryParams{UserID: user.ID, Name: "Health", Colour: "#16a34a"})
+		_, otherErr := db.Queries.GetCategory(ctx, repository.GetCategoryParams{ID: health.ID, UserID: other.ID})
+		updated, updateErr := db.Queries.UpdateCategory(ctx, repository.UpdateCategoryParams{Name: "Fitness", Colour: "#0284c7", UpdatedAt: "2024-12-30 10:00:00", ID: health.ID, UserID: user.ID})
+		categories, categoriesErr := db.Queries.GetCategories(ctx, user.ID)
+		clearedHabits, clearErr := db.Queries.ClearHabitCategory(ctx, repository.ClearHabitCategoryParams{UpdatedAt: "2024-12-30 10:00:00", UserID: user.ID, CategoryID: sql.NullInt64{Int64: health.ID, Valid: true}})
+		cleared, clearedErr := db.Queries.GetHabit(ctx, habit.ID)
+		deleted, deleteErr := db.Queries.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: health.ID, UserID: user.ID})
+		_, deletedErr := db.Queries.GetCategory(ctx, repository.GetCategoryParams{ID: health.ID, UserID: user.ID})
+
+		// Assert
+		assert.Equal(t, "🏃", habit.Icon)
+		assert.Equal(t, health.ID, habit.CategoryID.Int64)
+		assert.Error(t, duplicateErr)
+		assert.ErrorIs(t, otherErr, sql.ErrNoRows)
+		assert.NoError(t, updateErr)
+		assert.Equal(t, "Fitness", updated.Name)
+		assert.NoError(t, categoriesErr)
+		require.Len(t, categories, 2)
+		assert.Equal(t, "Admin", categories[0].Name)
+		assert.Equal(t, "Fitness", categories[1].Name)
+		assert.NoError(t, clearErr)
+		require.Len(t, clearedHabits, 1)
+		assert.Equal(t, habit.ID, clearedHabits[0].ID)
+		assert.NoError(t, clearedErr)
+		assert.False(t, cleared.CategoryID.Valid)
+		assert.NoError(t, deleteErr)
+		assert.Equal(t, health.ID, deleted.ID)
+		assert.ErrorIs(t, deletedErr, sql.ErrNoRows)
+	})
+}
+
+func TestTags(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		run := createHabit(t, db.Queries, user.ID, "Run", 0)
+		read := createHabit(t, db.Queries, user.ID, "Read", 1)
+		morning, err := db.Queries.CreateTag(ctx, repository.CreateTagParams{UserID: user.ID, Name: "morning"})
+		require.NoError(t, err)
+		health, err := db.Queries.CreateTag(ctx, repository.CreateTagParams{UserID: user.ID, Name: "health"})
+		require.NoError(t, err)
+
+		// Act
+		attached, attachErr := db.Queries.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: run.ID, TagID: morning.ID})
+		reattached, reattachErr := db.Queries.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: run.ID, TagID: morning.ID})
+		_, err = db.Queries.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: run.ID, TagID: health.ID})
+		require.NoError(t, err)
+		_, err = db.Queries.AttachHabitTag(ctx, repository.AttachHabitTagParams{HabitID: read.ID, TagID: morning.ID})
+		require.NoError(t, err)
+		_, duplicateErr := db.Queries.CreateTag(ctx, repository.CreateTagParams{UserID: user.ID, Name: "morning"})
+		runTags, runTagsErr := db.Queries.GetHabitTags(ctx, run.ID)
+		detached, detachErr := db.Queries.DetachHabitTag(ctx, repository.DetachHabitTagParams{HabitID: read.ID, TagID: morning.ID})
+		_, err = db.Queries.DeleteTag(ctx, repository.DeleteTagParams{ID: health.ID, UserID: user.ID})
+		require.NoError(t, err)
+		userTags, userTagsErr := db.Queries.GetUserHabitTags(ctx, user.ID)
+
+		// Assert
+		assert.NoError(t, attachErr)
+		assert.Equal(t, int64(1), attached)
+		assert.NoError(t, reattachErr)
+		assert.Equal(t, int64(0), reattached)
+		assert.Error(t, duplicateErr)
+		assert.NoError(t, runTagsErr)
+		require.Len(t, runTags, 2)
+		assert.Equal(t, "health", runTags[0].Name)
+		assert.Equal(t, "morning", runTags[1].Name)
+		assert.NoError(t, detachErr)
+		assert.Equal(t, int64(1), detached)
+		assert.NoError(t, userTagsErr)
+		assert.Equal(t, []repository.GetUserHabitTagsRow{{HabitID: run.ID, ID: morning.ID, Name: "morning"}}, userTags)
+	})
+}
+
+func TestDeleteUserCascades(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *Database) {
+		// Arrange
+		ctx := context.Background()
+		user, err := db.Queries.CreateUser(ctx, "alice")
+		require.NoError(t, err)
+		habit := createHabit(t, db.Queries, user.ID, "Run", 0)
+		entry, err := db.Queries.CreateHabitEntry(ctx, repository.CreateHabitEntryParams{HabitID: habit.ID, Date: "2024-12-01", Value: 1, Status: "done"})
+		require.NoError(t, err)
+
+		// Act
+		deleted, deleteErr := db.Queries.DeleteUser(ctx, user.ID)
+		_, habitErr := db.Queries.GetHabit(ctx, habit.ID)
+		_, entryErr := db.Queries.GetHabitEntryOwner(ctx, entry.ID)
+
+		// Assert
+		assert.NoError(t, deleteErr)
+		assert.Equal(t, int64(1), deleted)
+		assert.ErrorIs(t, habitErr, sql.ErrNoRows)
+		assert.ErrorIs(t, entryErr, sql.ErrNoRows)
+	})
+}
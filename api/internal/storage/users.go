@@ -2,6 +2,9 @@ package storage
 
 import (
 	"context"
+
+	"github.com/ReidMason/habit-tracker/internal/services/auditService"
+	"github.com/ReidMason/habit-tracker/internal/storage/repository"
 )
 
 type User struct {
@@ -9,20 +12,36 @@ type User struct {
 	Id   int64  `json:"id"`
 }
 
-func (s Sqlite) CreateUser(name string) (User, error) {
-	ctx := context.Background()
-	user, err := s.Queries.CreateUser(ctx, name)
+// CreateUser creates a user without a password, recording the change in the
+// audit log.
+func (s Database) CreateUser(ctx context.Context, name string) (User, error) {
+	var user User
+	err := s.Transaction(ctx, func(queries repository.Querier) error {
+		created, err := queries.CreateUser(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		user = User{
+			Id:   created.ID,
+			Name: created.Name,
+		}
+		return auditService.Record(ctx, queries, auditService.Change{
+			After:      user,
+			EntityType: auditService.EntityUser,
+			Action:     auditService.ActionCreate,
+			UserId:     user.Id,
+			EntityId:   user.Id,
+		})
+	})
 	if err != nil {
 		return User{}, err
 	}
 
-	return User{
-		Id:   user.ID,
-		Name: user.Name,
-	}, nil
+	return user, nil
 }
 
-func (s Sqlite) GetUsers() ([]User, error) {
+func (s Database) GetUsers() ([]User, error) {
 	ctx := context.Background()
 	user, err := s.Queries.GetUsers(ctx)
 	if err != nil {
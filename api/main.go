@@ -1,49 +1,27 @@
 package main
 
 import (
-	"context"
-	"flag"
+	"errors"
 	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
+	// Users' timezones must load in containers without a zoneinfo database
+	_ "time/tzdata"
 
-	"github.com/ReidMason/habit-tracker/internal/config"
-	"github.com/ReidMason/habit-tracker/internal/server"
+	"github.com/ReidMason/habit-tracker/internal/cli"
 	"github.com/charmbracelet/log"
 )
 
-type cmdArgs struct {
-	listenAddr string
-}
-
 func main() {
-	args := cmdArgs{listenAddr: *flag.String("listen-addr", ":8000", "server listen address")}
-	flag.Parse()
-
-	handler := log.New(os.Stdout)
-	handler.SetLevel(log.DebugLevel)
-	logger := slog.New(handler)
-	slog.SetLogLoggerLevel(slog.LevelDebug)
-	slog.SetDefault(logger)
+	// Replaced by the configured logger once the config has loaded
+	slog.SetDefault(slog.New(log.New(os.Stderr)))
 
-	cfg, err := config.Load(args.listenAddr)
-	if err != nil {
-		logger.Error("Failed to load config", slog.Any("error", err))
-		os.Exit(1)
+	err := cli.Execute(os.Args[1:])
+	if errors.Is(err, cli.ErrUsage) {
+		slog.Error(err.Error())
+		os.Exit(2)
 	}
-
-	srv, err := server.New(cfg, logger)
 	if err != nil {
-		logger.Error("Failed to create server", slog.Any("error", err))
-		os.Exit(1)
-	}
-
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
-	if err := srv.Start(ctx); err != nil {
-		logger.Error("Failed to start server", slog.Any("error", err))
+		slog.Error("Command failed", slog.Any("error", err))
 		os.Exit(1)
 	}
 }
@@ -1,38 +1,66 @@
 import {
   createHabitEntry,
+  getCurrentUser,
   getHabits,
+  logout,
   updateHabits,
   type Habit,
+  type User,
 } from "@/lib/api";
 import { useEffect, useState } from "react";
 import Month from "@/components/habitLayouts/longMonth/Month";
 import MonthSelector from "@/components/habitLayouts/longMonth/MonthSelector";
 import type { CreateHabitEntry, FetchHabits } from "./types";
 import HabitsSplit from "./habitLayouts/habitsSplit/HabitsSplit";
-import { tryTriggerConfetti } from "@/lib/utils";
-
-const userId = 1;
+import { reportUpdateError, tryTriggerConfetti } from "@/lib/utils";
+import LoginForm from "./auth/LoginForm";
+import { Button } from "./ui/button";
+import LoadingSpinner from "./loadingSpinner/LoadingSpinner";
 
 export default function HabitWrapper() {
+  const [user, setUser] = useState<User | null | undefined>(undefined);
   const [habits, setHabits] = useState<Habit[]>([]);
   const [pivotDate, setPivotDate] = useState(new Date());
 
   const fetchHabits: FetchHabits = async () => {
-    const response = await getHabits(userId);
+    if (!user) return [];
+
+    const response = await getHabits(user.id);
     setHabits(response);
     return response;
   };
 
   const updateAllHabits = async (habits: Habit[]) => {
+    if (!user) return;
+
     setHabits(habits);
-    await updateHabits(userId, habits);
+    try {
+      await updateHabits(user.id, habits);
+    } catch (error) {
+      reportUpdateError(error);
+    }
     await fetchHabits();
   };
 
+  const signOut = async () => {
+    await logout();
+    setHabits([]);
+    setUser(null);
+  };
+
   useEffect(() => {
-    fetchHabits();
+    getCurrentUser()
+      .then(setUser)
+      .catch((error) => {
+        console.error(error);
+        setUser(null);
+      });
   }, []);
 
+  useEffect(() => {
+    fetchHabits();
+  }, [user]);
+
   const createNewHabitEntry: CreateHabitEntry = async (
     habitId: number,
     date: Date
@@ -42,12 +70,29 @@ export default function HabitWrapper() {
     tryTriggerConfetti(newHabits, date);
   };
 
+  if (user === undefined) {
+    return <LoadingSpinner />;
+  }
+
+  if (user === null) {
+    return <LoginForm signedIn={setUser} />;
+  }
+
   return (
     <div className="flex flex-col gap-4">
+      <div className="flex items-center justify-between">
+        <span className="text-sm text-muted-foreground">
+          Signed in as {user.name}
+        </span>
+        <Button variant="ghost" onClick={signOut}>
+          Sign out
+        </Button>
+      </div>
       <MonthSelector pivotDate={pivotDate} setPivotDate={setPivotDate} />
       <div className="flex flex-col gap-4">
         <div className="border rounded-xl flex-col p-4">
           <Month
+            userId={user.id}
             habits={habits}
             pivotDate={pivotDate}
             fetchHabits={fetchHabits}
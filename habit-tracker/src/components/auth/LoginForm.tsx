@@ -0,0 +1,87 @@
+import React from "react";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { login, register, type User } from "@/lib/api";
+import LoadingSpinner from "@/components/loadingSpinner/LoadingSpinner";
+
+interface LoginFormProps {
+  signedIn: (user: User) => void;
+}
+
+export default function LoginForm({ signedIn }: LoginFormProps) {
+  const [mode, setMode] = React.useState<"login" | "register">("login");
+  const [name, setName] = React.useState("");
+  const [password, setPassword] = React.useState("");
+  const [error, setError] = React.useState("");
+  const [state, setState] = React.useState<"loading" | "idle">("idle");
+
+  const submit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (name.trim().length === 0 || password.length === 0) return;
+
+    setState("loading");
+    setError("");
+    try {
+      const authenticate = mode === "login" ? login : register;
+      signedIn(await authenticate(name, password));
+    } catch (error) {
+      setError(error instanceof Error ? error.message : String(error));
+    }
+    setState("idle");
+  };
+
+  const toggleMode = () => {
+    setMode((prev) => (prev === "login" ? "register" : "login"));
+    setError("");
+  };
+
+  return (
+    <form
+      className="border rounded-xl flex flex-col gap-4 p-4 max-w-sm"
+      onSubmit={submit}
+    >
+      <h2 className="text-lg font-semibold">
+        {mode === "login" ? "Sign in" : "Create an account"}
+      </h2>
+      <div className="grid gap-2">
+        <Label htmlFor="login-name">Name</Label>
+        <Input
+          id="login-name"
+          autoComplete="username"
+          disabled={state === "loading"}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+      </div>
+      <div className="grid gap-2">
+        <Label htmlFor="login-password">Password</Label>
+        <Input
+          id="login-password"
+          type="password"
+          autoComplete={
+            mode === "login" ? "current-password" : "new-password"
+          }
+          disabled={state === "loading"}
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+        />
+      </div>
+      {error && <p className="text-sm text-red-500">{error}</p>}
+      <div className="flex gap-2">
+        <Button type="submit" disabled={state === "loading"}>
+          {state === "loading" && <LoadingSpinner />}
+          {mode === "login" ? "Sign in" : "Register"}
+        </Button>
+        <Button
+          type="button"
+          variant="ghost"
+          disabled={state === "loading"}
+          onClick={toggleMode}
+        >
+          {mode === "login" ? "Create an account" : "I have an account"}
+        </Button>
+      </div>
+    </form>
+  );
+}
@@ -1,5 +1,5 @@
 import { useState } from "react";
-import { createHabit, type Habit } from "@/lib/api";
+import { createHabit, type Habit, type NewHabit } from "@/lib/api";
 import {
   closestCenter,
   DndContext,
@@ -22,6 +22,7 @@ import {
   restrictToVerticalAxis,
 } from "@dnd-kit/modifiers";
 import { datesMatch } from "@/lib/dates";
+import { reportUpdateError } from "@/lib/utils";
 import { SortableItem } from "@/components/sortable/SortableItem";
 import HabitDialog from "@/components/habits/HabitDialog";
 import { Button } from "@/components/ui/button";
@@ -30,9 +31,8 @@ import HabitRow from "./HabitRow";
 import type { CreateHabitEntry, FetchHabits } from "@/components/types";
 import { getDaysInMonth } from "@/lib/dates";
 
-const userId = 1;
-
 interface Props {
+  userId: number;
   habits: Habit[];
   fetchHabits: FetchHabits;
   updateHabits: (habits: Habit[]) => Promise<void>;
@@ -41,6 +41,7 @@ interface Props {
 }
 
 export default function Month({
+  userId,
   habits,
   fetchHabits,
   updateHabits,
@@ -174,16 +175,13 @@ export default function Month({
         title="Add habit"
         description="Add a new habit to track"
         confirmText="Add habit"
-        habit={{
-          id: 0,
-          name: "",
-          colour: "#000000",
-          entries: [],
-          index: 0,
-          active: false,
-        }}
-        submit={async (newHabit: Habit) => {
-          await createHabit(userId, newHabit);
+        habit={{ name: "", colour: "#000000" }}
+        submit={async (newHabit: NewHabit) => {
+          try {
+            await createHabit(userId, newHabit);
+          } catch (error) {
+            reportUpdateError(error);
+          }
           await fetchHabits();
         }}
       >
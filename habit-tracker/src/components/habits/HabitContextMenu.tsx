@@ -1,4 +1,5 @@
 import { updateHabit, type Habit } from "@/lib/api";
+import { reportUpdateError } from "@/lib/utils";
 import type { FetchHabits } from "../types";
 import { useState } from "react";
 import HabitDialog from "./HabitDialog";
@@ -18,13 +19,16 @@ export default function HabitContextMenu({
   const [removeDialogOpen, setRemoveDialogOpen] = useState(false);
 
   const editHabit = async (newHabit: Habit) => {
-    await updateHabit(newHabit);
+    try {
+      await updateHabit(newHabit);
+    } catch (error) {
+      reportUpdateError(error);
+    }
     await fetchHabits();
   };
 
   const removeHabit = async (habit: Habit) => {
-    await updateHabit({ ...habit, active: false });
-    await fetchHabits();
+    await editHabit({ ...habit, active: false });
   };
 
   return (
@@ -11,12 +11,12 @@ import {
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
-import { type Habit } from "@/lib/api";
+import { type NewHabit } from "@/lib/api";
 import LoadingSpinner from "@/components/loadingSpinner/LoadingSpinner";
 
-interface HabitDialogProps {
-  habit: Habit;
-  submit: (habit: Habit) => Promise<void>;
+interface HabitDialogProps<T extends NewHabit> {
+  habit: T;
+  submit: (habit: T) => Promise<void>;
   title: string;
   description: string;
   confirmText: string;
@@ -25,7 +25,7 @@ interface HabitDialogProps {
   setOpen?: (open: boolean) => void;
 }
 
-export default function HabitDialog({
+export default function HabitDialog<T extends NewHabit>({
   habit,
   submit,
   children,
@@ -34,7 +34,7 @@ export default function HabitDialog({
   confirmText,
   open,
   setOpen,
-}: HabitDialogProps) {
+}: HabitDialogProps<T>) {
   const [newHabit, setNewHabit] = React.useState(structuredClone(habit));
   const [state, setState] = React.useState<"loading" | "idle">("idle");
   const [stateOpen, setStateOpen] = React.useState(open ?? false);
@@ -15,13 +15,87 @@ const habitSchema = z.object({
   index: z.number(),
   entries: z.array(habitEntrySchema),
   active: z.boolean(),
+  version: z.number(),
+});
+
+const userSchema = z.object({
+  id: z.number(),
+  name: z.string(),
+  admin: z.boolean(),
 });
 
 export type Habit = z.infer<typeof habitSchema>;
 export type HabitEntry = z.infer<typeof habitEntrySchema>;
+export type User = z.infer<typeof userSchema>;
+
+// HabitConflictError is thrown when a habit was changed somewhere else since
+// it was loaded, so the update was rejected and the habits need reloading.
+export class HabitConflictError extends Error {
+  constructor() {
+    super(
+      "This habit was changed on another device. It has been reloaded, so make your change again."
+    );
+    this.name = "HabitConflictError";
+  }
+}
+
+// Requests are authenticated by the session cookie, which has to be sent to
+// the API even when it is served from another origin.
+function request(path: string, init: RequestInit = {}) {
+  return fetch(`${baseUrl}${path}`, { ...init, credentials: "include" });
+}
+
+export async function getCurrentUser(): Promise<User | null> {
+  const result = await request("/auth/me");
+  if (result.status === 401) {
+    return null;
+  }
+  const response = await result.json();
+
+  return userSchema.parse(response);
+}
+
+async function authenticate(
+  action: "login" | "register",
+  name: string,
+  password: string
+): Promise<User> {
+  const result = await request(`/auth/${action}`, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify({ name, password }),
+  });
+  if (result.status === 404) {
+    throw new Error("Registering is disabled");
+  }
+  if (!result.ok) {
+    throw new Error((await result.text()) || result.statusText);
+  }
+  const response = await result.json();
+
+  return userSchema.parse(response);
+}
+
+export function login(name: string, password: string): Promise<User> {
+  return authenticate("login", name, password);
+}
+
+export function register(name: string, password: string): Promise<User> {
+  return authenticate("register", name, password);
+}
+
+export async function logout() {
+  try {
+    await request("/auth/logout", { method: "POST" });
+  } catch (error) {
+    console.error(error);
+  }
+}
 
 export async function getHabits(userId: number): Promise<Habit[]> {
-  const result = await fetch(`${baseUrl}/users/${userId}/habits`);
+  const result = await request(`/users/${userId}/habits`);
   const response = await result.json();
   const data = z.array(habitSchema).parse(response);
 
@@ -37,32 +111,24 @@ export async function createHabit(
   userId: number,
   newHabit: NewHabit
 ): Promise<Habit> {
-  try {
-    const result = await fetch(`${baseUrl}/users/${userId}/habits`, {
-      method: "POST",
-      headers: {
-        "Content-Type": "application/json",
-      },
-      body: JSON.stringify(newHabit),
-    });
-    const data = await result.json();
-
-    return data;
-  } catch (error) {
-    return {
-      id: -1,
-      name: "",
-      entries: [],
-      colour: "",
-      index: 0,
-      active: false,
-    };
+  const result = await request(`/users/${userId}/habits`, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify(newHabit),
+  });
+  if (!result.ok) {
+    throw new Error((await result.text()) || result.statusText);
   }
+  const response = await result.json();
+
+  return habitSchema.parse(response);
 }
 
 export async function deleteHabit(habitId: number) {
   try {
-    await fetch(`${baseUrl}/habits/${habitId}`, {
+    await request(`/habits/${habitId}`, {
       method: "DELETE",
     });
   } catch (error) {
@@ -70,24 +136,45 @@ export async function deleteHabit(habitId: number) {
   }
 }
 
+// checkUpdate throws if a habit update was rejected, with a
+// HabitConflictError when the habit's version was out of date or missing.
+async function checkUpdate(result: Response) {
+  if (result.status === 409 || result.status === 428) {
+    throw new HabitConflictError();
+  }
+  if (!result.ok) {
+    throw new Error((await result.text()) || result.statusText);
+  }
+}
+
 export async function updateHabit(habit: Habit) {
-  await fetch(`${baseUrl}/habits/${habit.id}`, {
+  const result = await request(`/habits/${habit.id}`, {
     method: "PUT",
     headers: {
       "Content-Type": "application/json",
     },
     body: JSON.stringify(habit),
   });
+  await checkUpdate(result);
+}
+
+// formatDate returns the local calendar date as YYYY-MM-DD, so entries are for
+// the day the user picked whatever their timezone.
+function formatDate(date: Date) {
+  const month = String(date.getMonth() + 1).padStart(2, "0");
+  const day = String(date.getDate()).padStart(2, "0");
+
+  return `${date.getFullYear()}-${month}-${day}`;
 }
 
 export async function createHabitEntry(habitId: number, date: Date) {
   try {
-    await fetch(`${baseUrl}/habitEntries`, {
+    await request(`/habitEntries`, {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
       },
-      body: JSON.stringify({ habitId, date }),
+      body: JSON.stringify({ habitId, date: formatDate(date) }),
     });
   } catch (error) {
     console.error(error);
@@ -96,7 +183,7 @@ export async function createHabitEntry(habitId: number, date: Date) {
 
 export async function deleteHabitEntry(entryId: number) {
   try {
-    await fetch(`${baseUrl}/habitEntries/${entryId}`, {
+    await request(`/habitEntries/${entryId}`, {
       method: "DELETE",
     });
   } catch (error) {
@@ -105,15 +192,12 @@ export async function deleteHabitEntry(entryId: number) {
 }
 
 export async function updateHabits(userId: number, habits: Habit[]) {
-  try {
-    await fetch(`${baseUrl}/users/${userId}/habits`, {
-      method: "PUT",
-      headers: {
-        "Content-Type": "application/json",
-      },
-      body: JSON.stringify(habits),
-    });
-  } catch (error) {
-    console.error(error);
-  }
+  const result = await request(`/users/${userId}/habits`, {
+    method: "PUT",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify(habits),
+  });
+  await checkUpdate(result);
 }
@@ -71,3 +71,12 @@ export function hexToRgb(hex: string) {
       }
     : null;
 }
+
+// reportUpdateError tells the user why saving their habits failed. Callers
+// reload the habits afterwards, so a conflict shows the latest changes.
+export function reportUpdateError(error: unknown) {
+  console.error(error);
+  window.alert(
+    error instanceof Error ? error.message : "Failed to save your habits."
+  );
+}